@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,10 +11,16 @@ import (
 
 	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/handlers"
 	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/routes"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/rpc"
 	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/kafka"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/service"
 	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
 	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/redis"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
 )
 
 func main() {
@@ -50,10 +57,76 @@ func main() {
 		}
 	}()
 
-	analysisHandler := handlers.NewAnalysisHandler(kafkaProducer, logger)
+	redisClient, err := redis.NewClient(ctx, &cfg.Redis)
+	if err != nil {
+		logger.Fatal("Failed to connect to Redis", zap.Error(err))
+	}
+	defer func() {
+		logger.Info("Closing Redis client")
+		if err := redisClient.Close(); err != nil {
+			logger.Error("Error closing Redis client", zap.Error(err))
+		}
+	}()
+
+	statusRepository := repository.NewRedisStatusRepository(redisClient, cfg.Redis.StatusTTL)
+	historyRepository := repository.NewRedisStatusHistoryRepository(redisClient, cfg.Redis.StatusTTL)
+
+	statusConsumer, err := initStatusConsumer(cfg, cfg.Kafka.ConsumerGroup, cfg.Kafka.Consumer.InitialOffset, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize Kafka status consumer", zap.Error(err))
+	}
+	defer func() {
+		logger.Info("Closing Kafka status consumer")
+		if err := statusConsumer.Close(); err != nil {
+			logger.Error("Error closing Kafka status consumer", zap.Error(err))
+		}
+	}()
+
+	statusProjector := service.NewStatusProjector(statusConsumer, statusRepository, historyRepository, logger)
+	go func() {
+		if err := statusProjector.Run(ctx); err != nil {
+			logger.Error("Status projector stopped", zap.Error(err))
+		}
+	}()
+
+	// Живые события нужны каждому экземпляру целиком, поэтому у брокера своя consumer group
+	streamConsumer, err := initStatusConsumer(cfg, streamConsumerGroup(cfg), "latest", logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize Kafka stream consumer", zap.Error(err))
+	}
+	defer func() {
+		logger.Info("Closing Kafka stream consumer")
+		if err := streamConsumer.Close(); err != nil {
+			logger.Error("Error closing Kafka stream consumer", zap.Error(err))
+		}
+	}()
+
+	statusBroker := service.NewStatusBroker()
+	go func() {
+		if err := streamConsumer.Run(ctx, statusBroker.Publish); err != nil && ctx.Err() == nil {
+			logger.Error("Status stream consumer stopped", zap.Error(err))
+		}
+	}()
+
+	analysisService := service.NewAnalysisService(kafkaProducer, statusProjector, logger)
+
+	analysisHandler := handlers.NewAnalysisHandler(analysisService, logger)
+	uploadHandler := handlers.NewUploadHandler(analysisService, cfg.Server.MaxRequestSize, logger)
+	statusHandler := handlers.NewStatusHandler(statusRepository, logger)
+	eventsHandler := handlers.NewEventsHandler(statusRepository, historyRepository, statusBroker, logger)
+	webSocketHandler := handlers.NewWebSocketHandler(statusRepository, statusBroker, cfg.CORS.AllowedOrigins, logger)
 	healthHandler := handlers.NewHealthHandler(logger)
 
-	router := routes.SetupRoutes(analysisHandler, healthHandler, cfg, logger)
+	router := routes.SetupRoutes(
+		analysisHandler,
+		uploadHandler,
+		statusHandler,
+		eventsHandler,
+		webSocketHandler,
+		healthHandler,
+		cfg,
+		logger,
+	)
 
 	server := &http.Server{
 		Addr:         cfg.Server.GetConfig(),
@@ -74,16 +147,52 @@ func main() {
 		}
 	}()
 
+	analysisServer := rpc.NewAnalysisServer(
+		analysisService,
+		statusRepository,
+		historyRepository,
+		statusBroker,
+		logger,
+	)
+	grpcServer, grpcHealth := rpc.NewServer(analysisServer, logger)
+
+	grpcListener, err := net.Listen("tcp", cfg.Server.GetGRPCAddress())
+	if err != nil {
+		logger.Fatal("Failed to listen gRPC address", zap.Error(err))
+	}
+
+	go func() {
+		logger.Info("gRPC server starting",
+			zap.String("address", grpcListener.Addr().String()),
+		)
+
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logger.Fatal("Failed to start gRPC server", zap.Error(err))
+		}
+	}()
+
 	logger.Info("API Gateway started successfully",
 		zap.Strings("kafka_brokers", cfg.Kafka.Brokers),
 		zap.String("kafka_topic", cfg.Kafka.Topic),
+		zap.String("kafka_status_topic", cfg.Kafka.StatusTopic),
+		zap.String("redis", cfg.Redis.GetRedisAddress()),
 		zap.String("server_mode", cfg.Server.Mode),
 	)
 
-	gracefulShutdown(ctx, server, cfg, logger)
+	gracefulShutdown(ctx, server, grpcServer, grpcHealth, cfg, logger)
+
+	// Останавливаем консьюмеры до закрытия соединений с Kafka
+	cancel()
 }
 
-func gracefulShutdown(ctx context.Context, server *http.Server, cfg *config.Config, logger *logger.Logger) {
+func gracefulShutdown(
+	ctx context.Context,
+	server *http.Server,
+	grpcServer *grpc.Server,
+	grpcHealth *health.Server,
+	cfg *config.Config,
+	logger *logger.Logger,
+) {
 	term := make(chan os.Signal, 1)
 	signal.Notify(term, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
@@ -93,13 +202,33 @@ func gracefulShutdown(ctx context.Context, server *http.Server, cfg *config.Conf
 	shutDownCtx, cancel := context.WithTimeout(ctx, cfg.Server.ShutdownTimeout)
 	defer cancel()
 
+	logger.Info("Shutting down gRPC server...")
+
+	// Клиенты health check перестают слать трафик до остановки сервера
+	grpcHealth.Shutdown()
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
 	logger.Info("Shutting down HTTP server...")
 
 	if err := server.Shutdown(shutDownCtx); err != nil {
 		logger.Error("Server forced to shutdown", zap.Error(err))
-		return
 	}
-	logger.Info("Server exited gracefully")
+
+	select {
+	case <-grpcStopped:
+	case <-shutDownCtx.Done():
+		// Долгие WatchStatus стримы не дают GracefulStop завершиться
+		logger.Warn("gRPC server forced to stop")
+		grpcServer.Stop()
+	}
+
+	if shutDownCtx.Err() == nil {
+		logger.Info("Server exited gracefully")
+	}
 }
 
 func initKafkaProducer(cfg *config.Config, logger *logger.Logger) (*kafka.APIGatewayProducer, error) {
@@ -116,3 +245,36 @@ func initKafkaProducer(cfg *config.Config, logger *logger.Logger) (*kafka.APIGat
 	logger.Info("Kafka producer initialized successfully")
 	return producer, nil
 }
+
+func initStatusConsumer(
+	cfg *config.Config,
+	groupID string,
+	initialOffset string,
+	logger *logger.Logger,
+) (*kafka.StatusConsumer, error) {
+	logger.Info("Subscribing to analysis status events",
+		zap.Strings("brokers", cfg.Kafka.Brokers),
+		zap.String("topic", cfg.Kafka.StatusTopic),
+		zap.String("consumer_group", groupID),
+	)
+
+	consumer, err := kafka.NewStatusConsumer(
+		cfg.Kafka.Brokers,
+		groupID,
+		cfg.Kafka.StatusTopic,
+		initialOffset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka status consumer: %w", err)
+	}
+
+	return consumer, nil
+}
+
+func streamConsumerGroup(cfg *config.Config) string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = fmt.Sprintf("pid-%d", os.Getpid())
+	}
+	return fmt.Sprintf("%s-stream-%s", cfg.Kafka.ConsumerGroup, hostname)
+}
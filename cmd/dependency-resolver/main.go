@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/redis"
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/app"
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/kafka"
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/metrics"
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/service"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"go.uber.org/zap"
+)
+
+func main() {
+	tLogg, _ := zap.NewDevelopment()
+	defer tLogg.Sync()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		tLogg.Fatal("Failed to load config", zap.Error(err))
+	}
+
+	logger, err := logger.NewLogger(&cfg.Logger)
+	if err != nil {
+		tLogg.Fatal("Failed to initialize logger", zap.Error(err))
+	}
+	defer logger.Sync()
+
+	logger.Info("Starting Dependency Resolver",
+		zap.String("version", "1.0.0"),
+		zap.Bool("offline", cfg.PyPI.Offline),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	index, cache, err := initIndex(ctx, cfg, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize package index", zap.Error(err))
+	}
+
+	producer, err := kafka.NewResolverProducer(cfg.Kafka.Brokers, cfg.Kafka.StatusTopic, cfg.Kafka.ResultTopic)
+	if err != nil {
+		logger.Fatal("Failed to initialize Kafka producer", zap.Error(err))
+	}
+	defer func() {
+		logger.Info("Closing Kafka producer")
+		if err := producer.Close(); err != nil {
+			logger.Error("Error closing Kafka producer", zap.Error(err))
+		}
+	}()
+
+	logger.Info("Subscribing to analysis requests",
+		zap.Strings("brokers", cfg.Kafka.Brokers),
+		zap.String("topic", cfg.Kafka.Topic),
+		zap.String("consumer_group", cfg.Resolver.ConsumerGroup),
+	)
+	consumer, err := kafka.NewRequestConsumer(
+		cfg.Kafka.Brokers,
+		cfg.Resolver.ConsumerGroup,
+		cfg.Kafka.Topic,
+		cfg.Kafka.Consumer.InitialOffset,
+	)
+	if err != nil {
+		logger.Fatal("Failed to initialize Kafka consumer", zap.Error(err))
+	}
+	defer func() {
+		logger.Info("Closing Kafka consumer")
+		if err := consumer.Close(); err != nil {
+			logger.Error("Error closing Kafka consumer", zap.Error(err))
+		}
+	}()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	// cache остаётся nil, если Redis недоступен: тогда нет ни метрик кеша, ни его сброса
+	var purger app.CachePurger
+	if cache != nil {
+		metrics.RegisterCacheStats(registry, cache.Stats)
+		purger = cache
+		if cfg.Resolver.AdminToken == "" {
+			logger.Info("Cache purge endpoint disabled, set RESOLVER_ADMIN_TOKEN to enable it")
+		}
+	}
+
+	analysisService := service.NewAnalysisService(index, producer, metrics.NewMetrics(registry), cfg.Resolver, logger)
+	go func() {
+		if err := consumer.Run(ctx, analysisService.Handle); err != nil && ctx.Err() == nil {
+			logger.Error("Analysis request consumer stopped", zap.Error(err))
+		}
+	}()
+
+	if cfg.Server.Mode == "release" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	server := &http.Server{
+		Addr:    cfg.Resolver.GetAddress(),
+		Handler: app.SetupRouter(registry, purger, cfg.Resolver.AdminToken),
+	}
+
+	go func() {
+		logger.Info("HTTP server starting", zap.String("address", server.Addr))
+
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			logger.Fatal("Failed to start server", zap.Error(err))
+		}
+	}()
+
+	logger.Info("Dependency Resolver started successfully",
+		zap.String("kafka_topic", cfg.Kafka.Topic),
+		zap.String("kafka_status_topic", cfg.Kafka.StatusTopic),
+		zap.String("kafka_result_topic", cfg.Kafka.ResultTopic),
+	)
+
+	term := make(chan os.Signal, 1)
+	signal.Notify(term, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+
+	sig := <-term
+	logger.Info("Shutdown signal received", zap.String("signal", sig.String()))
+
+	// Останавливаем консьюмер до закрытия соединений с Kafka
+	cancel()
+
+	shutDownCtx, shutDownCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer shutDownCancel()
+
+	if err := server.Shutdown(shutDownCtx); err != nil {
+		logger.Error("Server forced to shutdown", zap.Error(err))
+	}
+}
+
+// initIndex подключает индексы пакетов; кеш Redis необязателен и равен nil, если Redis недоступен
+func initIndex(ctx context.Context, cfg *config.Config, logger *logger.Logger) (pypi.Index, *pypi.Cache, error) {
+	var opts []pypi.Option
+	var cache *pypi.Cache
+	if !cfg.PyPI.Offline {
+		redisClient, err := redis.NewClient(ctx, &cfg.Redis)
+		if err != nil {
+			logger.Warn("Redis is unavailable, package index cache disabled", zap.Error(err))
+		} else {
+			cache = pypi.NewCache(redisClient, cfg.PyPI.CacheTimeout)
+			opts = append(opts, pypi.WithCache(cache))
+		}
+	}
+
+	index, err := pypi.NewIndex(cfg.PyPI, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open package index: %w", err)
+	}
+	return index, cache, nil
+}
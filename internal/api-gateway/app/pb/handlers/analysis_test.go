@@ -1,17 +1,23 @@
 package handlers_test
 
 import (
+	"bytes"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/handlers"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/middleware"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/service"
 	"github.com/0hJonny/python-deps-crawler/internal/pkg/mocks"
 	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -31,6 +37,20 @@ func getValidProtoRequest() []byte {
 	return data
 }
 
+// decodeErrorResponse читает ErrorResponse в той кодировке, в которой его отправил сервер
+func decodeErrorResponse(t *testing.T, w *httptest.ResponseRecorder) *pbapi.ErrorResponse {
+	t.Helper()
+
+	var response pbapi.ErrorResponse
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		require.NoError(t, protojson.Unmarshal(w.Body.Bytes(), &response))
+	} else {
+		require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &response))
+	}
+
+	return &response
+}
+
 func TestStartAnalysis_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -50,7 +70,7 @@ func TestStartAnalysis_Success(t *testing.T) {
 		mock.AnythingOfType("zapcore.Field"),
 	).Return()
 
-	handler := handlers.NewAnalysisHandler(mockProducer, mockLogger)
+	handler := handlers.NewAnalysisHandler(service.NewAnalysisService(mockProducer, nil, mockLogger), mockLogger)
 
 	router := gin.New()
 	router.POST("/analyze", func(c *gin.Context) {
@@ -90,7 +110,7 @@ func TestStartAnalysis_ValidationWarn(t *testing.T) {
 		return strings.Contains(msg, "Non-protobuf request")
 	})).Return()
 
-	handler := handlers.NewAnalysisHandler(mockProducer, mockLogger)
+	handler := handlers.NewAnalysisHandler(service.NewAnalysisService(mockProducer, nil, mockLogger), mockLogger)
 
 	router := gin.New()
 	router.POST("/analyze", func(c *gin.Context) {
@@ -107,7 +127,7 @@ func TestStartAnalysis_ValidationWarn(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	mockLogger.AssertCalled(t, "Warn", mock.Anything)
-	assert.Contains(t, w.Body.String(), "INVALID_CONTENT_TYPE")
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_INVALID_CONTENT_TYPE, decodeErrorResponse(t, w).Code)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
@@ -124,7 +144,7 @@ func TestStartAnalysis_ValidationBodyError(t *testing.T) {
 		mock.Anything,
 	).Return()
 
-	handler := handlers.NewAnalysisHandler(mockProducer, mockLogger)
+	handler := handlers.NewAnalysisHandler(service.NewAnalysisService(mockProducer, nil, mockLogger), mockLogger)
 
 	router := gin.New()
 	router.POST("/analyze", func(c *gin.Context) {
@@ -141,7 +161,7 @@ func TestStartAnalysis_ValidationBodyError(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	mockLogger.AssertCalled(t, "Error", mock.Anything, mock.Anything)
-	assert.Contains(t, w.Body.String(), "MISSING_PROTOBUF_DATA")
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_MISSING_PROTOBUF_DATA, decodeErrorResponse(t, w).Code)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
@@ -158,7 +178,7 @@ func TestStartAnalysis_ValidationProtobufError(t *testing.T) {
 		mock.Anything,
 	).Return()
 
-	handler := handlers.NewAnalysisHandler(mockProducer, mockLogger)
+	handler := handlers.NewAnalysisHandler(service.NewAnalysisService(mockProducer, nil, mockLogger), mockLogger)
 
 	badData := []byte("Bad Protobuf!")
 
@@ -178,7 +198,7 @@ func TestStartAnalysis_ValidationProtobufError(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	mockLogger.AssertCalled(t, "Error", mock.Anything, mock.Anything)
-	assert.Contains(t, w.Body.String(), "PROTOBUF_UNMARSHAL_ERROR")
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_PROTOBUF_UNMARSHAL_ERROR, decodeErrorResponse(t, w).Code)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
@@ -195,7 +215,7 @@ func TestStartAnalysis_ValidationRequestError(t *testing.T) {
 		mock.Anything,
 	).Return()
 
-	handler := handlers.NewAnalysisHandler(mockProducer, mockLogger)
+	handler := handlers.NewAnalysisHandler(service.NewAnalysisService(mockProducer, nil, mockLogger), mockLogger)
 
 	var request pbapi.AnalyzeRequest
 
@@ -217,7 +237,17 @@ func TestStartAnalysis_ValidationRequestError(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "VALIDATION_ERROR")
+
+	errorResponse := decodeErrorResponse(t, w)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_VALIDATION_ERROR, errorResponse.Code)
+	assert.Equal(t, "test-id-123", errorResponse.RequestId)
+	assert.False(t, errorResponse.Retryable)
+
+	fields := make([]string, len(errorResponse.Violations))
+	for i, violation := range errorResponse.Violations {
+		fields[i] = violation.Field
+	}
+	assert.Equal(t, []string{"user_id", "python_version", "packages"}, fields)
 	mockLogger.AssertCalled(t, "Warn", mock.Anything, mock.Anything)
 }
 
@@ -242,7 +272,7 @@ func TestStartAnalysis_KafkaError(t *testing.T) {
 		return strings.Contains(msg, "Failed to publish event")
 	}), mock.Anything, mock.Anything).Return()
 
-	handler := handlers.NewAnalysisHandler(mockProducer, mockLogger)
+	handler := handlers.NewAnalysisHandler(service.NewAnalysisService(mockProducer, nil, mockLogger), mockLogger)
 
 	router := gin.New()
 	router.POST("/analyze", func(c *gin.Context) {
@@ -260,8 +290,537 @@ func TestStartAnalysis_KafkaError(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "KAFKA_PUBLISH_ERROR")
+
+	errorResponse := decodeErrorResponse(t, w)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_KAFKA_PUBLISH_ERROR, errorResponse.Code)
+	assert.True(t, errorResponse.Retryable)
 
 	mockLogger.AssertCalled(t, "Error", mock.Anything, mock.Anything, mock.Anything)
 	mockProducer.AssertExpectations(t)
 }
+
+func setupNegotiationTestRouter(mockProducer *mocks.MockKafkaProducer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	mockLogger := mocks.NewMockLogger()
+	mockLogger.On("WithRequestID", "test-id-123").Return(mockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warn", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return()
+
+	handler := handlers.NewAnalysisHandler(service.NewAnalysisService(mockProducer, nil, mockLogger), mockLogger)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("request_id", "test-id-123")
+	})
+	router.Use(middleware.ProtobufMiddleware())
+	router.POST("/analyze", handler.StartAnalysis)
+
+	return router
+}
+
+func TestStartAnalysis_JSONAndProtobufProduceIdenticalEvents(t *testing.T) {
+	var events []*eventspb.AnalysisStartedEvent
+
+	mockProducer := mocks.NewMockKafkaProducer()
+	mockProducer.On("PublishEvent", mock.Anything, mock.AnythingOfType("*kafka_message.AnalysisStartedEvent")).
+		Run(func(args mock.Arguments) {
+			events = append(events, args.Get(1).(*eventspb.AnalysisStartedEvent))
+		}).
+		Return(nil)
+
+	router := setupNegotiationTestRouter(mockProducer)
+
+	jsonBody := `{
+		"userId": "user123",
+		"pythonVersion": "3.10",
+		"repositoryUrl": "https://github.com/user/project",
+		"packages": [{"packageName": "requests", "packageVersion": "2.28.1"}]
+	}`
+
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var jsonResponse pbapi.AnalyzeResponse
+	require.NoError(t, protojson.Unmarshal(w.Body.Bytes(), &jsonResponse))
+	assert.Equal(t, "pending", jsonResponse.Status)
+
+	req = httptest.NewRequest(http.MethodPost, "/analyze", bytes.NewReader(getValidProtoRequest()))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-protobuf", w.Header().Get("Content-Type"))
+
+	require.Len(t, events, 2)
+	for _, event := range events {
+		event.RequestId = ""
+		event.Timestamp = nil
+	}
+	assert.True(t, proto.Equal(events[0], events[1]), "events differ: %v vs %v", events[0], events[1])
+}
+
+func TestStartAnalysis_AcceptHeaderSelectsResponseEncoding(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	mockProducer.On("PublishEvent", mock.Anything, mock.Anything).Return(nil)
+
+	router := setupNegotiationTestRouter(mockProducer)
+
+	req := httptest.NewRequest(http.MethodPost, "/analyze", bytes.NewReader(getValidProtoRequest()))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response pbapi.AnalyzeResponse
+	require.NoError(t, protojson.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.RequestId)
+}
+
+func TestStartAnalysis_InvalidJSON(t *testing.T) {
+	router := setupNegotiationTestRouter(mocks.NewMockKafkaProducer())
+
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(`{"userId": 42}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_JSON_UNMARSHAL_ERROR, decodeErrorResponse(t, w).Code)
+}
+
+func TestStartAnalysis_JSONValidationError(t *testing.T) {
+	router := setupNegotiationTestRouter(mocks.NewMockKafkaProducer())
+
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(`{"userId": "user123"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_VALIDATION_ERROR, decodeErrorResponse(t, w).Code)
+}
+
+func TestStartAnalysis_JSONErrorForEmptyBody(t *testing.T) {
+	router := setupNegotiationTestRouter(mocks.NewMockKafkaProducer())
+
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(""))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_EMPTY_BODY, decodeErrorResponse(t, w).Code)
+}
+
+func TestStartAnalysis_ReportsAllPackageViolations(t *testing.T) {
+	router := setupNegotiationTestRouter(mocks.NewMockKafkaProducer())
+
+	body := `{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": ""}, {"packageName": "ok"}, {}]}`
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	errorResponse := decodeErrorResponse(t, w)
+	require.Len(t, errorResponse.Violations, 2)
+	assert.Equal(t, "packages[0].package_name", errorResponse.Violations[0].Field)
+	assert.Equal(t, "packages[2].package_name", errorResponse.Violations[1].Field)
+}
+
+func TestStartAnalysis_LockedRequiresPinnedVersions(t *testing.T) {
+	router := setupNegotiationTestRouter(mocks.NewMockKafkaProducer())
+
+	body := `{"userId": "u", "pythonVersion": "3.12", "locked": true, "packages": [
+		{"packageName": "requests", "packageVersion": "==2.32.3"},
+		{"packageName": "flask", "packageVersion": ">=3"},
+		{"packageName": "httpx", "url": "git+https://github.com/encode/httpx@0.27.0"}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	errorResponse := decodeErrorResponse(t, w)
+	require.Len(t, errorResponse.Violations, 1)
+	assert.Equal(t, "packages[1].package_version", errorResponse.Violations[0].Field)
+}
+
+func TestStartAnalysis_RejectsInvalidVersionSpecifiers(t *testing.T) {
+	router := setupNegotiationTestRouter(mocks.NewMockKafkaProducer())
+
+	body := `{"userId": "u", "pythonVersion": "3.12", "packages": [
+		{"packageName": "requests", "packageVersion": "2.32.3"},
+		{"packageName": "flask", "packageVersion": ">=3.0.*"},
+		{"packageName": "django", "packageVersion": "~=5"}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	errorResponse := decodeErrorResponse(t, w)
+	require.Len(t, errorResponse.Violations, 2)
+	assert.Equal(t, "packages[1].package_version", errorResponse.Violations[0].Field)
+	assert.Contains(t, errorResponse.Violations[0].Description, "wildcard .* is only allowed with == and !=")
+	assert.Equal(t, "packages[2].package_version", errorResponse.Violations[1].Field)
+	assert.Contains(t, errorResponse.Violations[1].Description, "~= requires at least two release segments")
+}
+
+func TestStartAnalysis_RejectsInvalidMarkers(t *testing.T) {
+	router := setupNegotiationTestRouter(mocks.NewMockKafkaProducer())
+
+	body := `{"userId": "u", "pythonVersion": "3.12", "packages": [
+		{"packageName": "tomli", "marker": "python_version < \"3.11\""},
+		{"packageName": "pywin32", "marker": "platform == \"win32\""}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	errorResponse := decodeErrorResponse(t, w)
+	require.Len(t, errorResponse.Violations, 1)
+	assert.Equal(t, "packages[1].marker", errorResponse.Violations[0].Field)
+	assert.Contains(t, errorResponse.Violations[0].Description, `unknown environment variable "platform"`)
+}
+
+func TestStartAnalysis_NormalizesPackageNames(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupNegotiationTestRouter(mockProducer)
+
+	for _, name := range []string{"Django", "django", "zope_interface", "Zope.Interface"} {
+		body := `{"userId": "u", "pythonVersion": "3.12", "packages": [
+			{"packageName": "` + name + `", "extras": ["Argon2_CFFI"]}
+		]}`
+		req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	require.Len(t, *events, 4)
+	expected := []string{"django", "django", "zope-interface", "zope-interface"}
+	for i, event := range *events {
+		assert.Equal(t, expected[i], event.Packages[0].PackageName)
+		assert.Equal(t, []string{"argon2-cffi"}, event.Packages[0].Extras)
+	}
+	assert.Equal(t, "Zope.Interface", (*events)[3].Packages[0].DisplayName)
+}
+
+func TestStartAnalysis_NormalizesPackageVersions(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupNegotiationTestRouter(mockProducer)
+
+	body := `{"userId": "u", "pythonVersion": "3.12", "packages": [
+		{"packageName": "django", "packageVersion": "v4.2"},
+		{"packageName": "flask", "packageVersion": "3.0.0-rc1"},
+		{"packageName": "requests", "packageVersion": ">= 2.28, < 3"},
+		{"packageName": "click"}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Len(t, *events, 1)
+	versions := make([]string, len((*events)[0].Packages))
+	for i, pkg := range (*events)[0].Packages {
+		versions[i] = pkg.PackageVersion
+	}
+	assert.Equal(t, []string{"==4.2", "==3.0.0rc1", ">=2.28,<3", ""}, versions)
+}
+
+func TestStartAnalysis_RejectsInvalidPackageNames(t *testing.T) {
+	router := setupNegotiationTestRouter(mocks.NewMockKafkaProducer())
+
+	body := `{"userId": "u", "pythonVersion": "3.12", "packages": [
+		{"packageName": "requests"},
+		{"packageName": "-leading-dash"},
+		{"packageName": "flask", "extras": ["async", "bad extra"]}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	errorResponse := decodeErrorResponse(t, w)
+	require.Len(t, errorResponse.Violations, 2)
+	assert.Equal(t, "packages[1].package_name", errorResponse.Violations[0].Field)
+	assert.Contains(t, errorResponse.Violations[0].Description, "invalid project name")
+	assert.Equal(t, "packages[2].extras[1]", errorResponse.Violations[1].Field)
+}
+
+func TestStartAnalysis_NormalizesTargetPlatform(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupNegotiationTestRouter(mockProducer)
+
+	for _, body := range []string{
+		`{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": "numpy"}],
+			"targetPlatform": {"os": "darwin", "arch": "aarch64"}}`,
+		`{"userId": "u", "pythonVersion": "3.11", "packages": [{"packageName": "numpy"}]}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	require.Len(t, *events, 2)
+	assert.True(t, proto.Equal(&eventspb.TargetPlatform{
+		Os:           "macos",
+		Arch:         "arm64",
+		MacosVersion: "14.0",
+	}, (*events)[0].TargetPlatform))
+	assert.Nil(t, (*events)[1].TargetPlatform)
+}
+
+func TestStartAnalysis_RejectsInvalidTargetPlatform(t *testing.T) {
+	router := setupNegotiationTestRouter(mocks.NewMockKafkaProducer())
+
+	body := `{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": "numpy"}],
+		"targetPlatform": {"os": "windows", "libc": "musl"}}`
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	errorResponse := decodeErrorResponse(t, w)
+	require.Len(t, errorResponse.Violations, 1)
+	assert.Equal(t, "target_platform", errorResponse.Violations[0].Field)
+	assert.Contains(t, errorResponse.Violations[0].Description, "libc can only be set for linux")
+}
+
+func TestStartAnalysis_ResolutionStrategy(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupNegotiationTestRouter(mockProducer)
+
+	for _, body := range []string{
+		`{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": "flask"}]}`,
+		`{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": "flask"}],
+			"resolutionStrategy": "Lowest_Direct"}`,
+		`{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": "flask"}],
+			"resolutionStrategy": "prefer-pinned", "pinnedVersions": {"Flask": "2.3.3", "Werkzeug": "2.3.8"}}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	require.Len(t, *events, 3)
+	assert.Equal(t, "highest", (*events)[0].ResolutionStrategy)
+	assert.Equal(t, "lowest-direct", (*events)[1].ResolutionStrategy)
+	assert.Equal(t, "prefer-pinned", (*events)[2].ResolutionStrategy)
+	assert.Equal(t, map[string]string{"flask": "2.3.3", "werkzeug": "2.3.8"}, (*events)[2].PinnedVersions)
+}
+
+func TestStartAnalysis_RejectsInvalidResolutionStrategy(t *testing.T) {
+	router := setupNegotiationTestRouter(mocks.NewMockKafkaProducer())
+
+	tests := []struct {
+		name   string
+		body   string
+		fields []string
+	}{
+		{
+			name: "unknown strategy",
+			body: `{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": "flask"}],
+				"resolutionStrategy": "newest"}`,
+			fields: []string{"resolution_strategy"},
+		},
+		{
+			name: "pins without prefer-pinned",
+			body: `{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": "flask"}],
+				"resolutionStrategy": "lowest", "pinnedVersions": {"flask": "2.3.3"}}`,
+			fields: []string{"pinned_versions"},
+		},
+		{
+			name: "invalid pinned version",
+			body: `{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": "flask"}],
+				"resolutionStrategy": "prefer-pinned", "pinnedVersions": {"flask": "latest"}}`,
+			fields: []string{"pinned_versions[flask]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			errorResponse := decodeErrorResponse(t, w)
+			fields := make([]string, len(errorResponse.Violations))
+			for i, violation := range errorResponse.Violations {
+				fields[i] = violation.Field
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestStartAnalysis_UniversalResolution(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupNegotiationTestRouter(mockProducer)
+
+	for _, body := range []string{
+		`{"userId": "u", "packages": [{"packageName": "numpy"}],
+			"universal": {"pythonVersions": ["3.9", "3.13"], "platforms": [{"os": "linux", "libc": "musl"}, {"os": "darwin"}]}}`,
+		`{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": "numpy"}], "universal": {}}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	require.Len(t, *events, 2)
+	assert.True(t, proto.Equal(&eventspb.UniversalResolution{
+		PythonVersions: []string{"3.9", "3.13"},
+		Platforms: []*eventspb.TargetPlatform{
+			{Os: "linux", Arch: "x86_64", Libc: "musl", LibcVersion: "1.2"},
+			{Os: "macos", Arch: "x86_64", MacosVersion: "14.0"},
+		},
+	}, (*events)[0].Universal))
+
+	universal := (*events)[1].Universal
+	assert.Equal(t, []string{"3.12"}, universal.PythonVersions)
+	platforms := make([]string, len(universal.Platforms))
+	for i, platform := range universal.Platforms {
+		platforms[i] = platform.Os + "/" + platform.Arch
+	}
+	assert.Equal(t, []string{"linux/x86_64", "macos/arm64", "windows/x86_64"}, platforms)
+}
+
+func TestStartAnalysis_RejectsInvalidUniversalResolution(t *testing.T) {
+	router := setupNegotiationTestRouter(mocks.NewMockKafkaProducer())
+
+	tests := []struct {
+		name   string
+		body   string
+		fields []string
+	}{
+		{
+			name:   "no python version",
+			body:   `{"userId": "u", "packages": [{"packageName": "numpy"}], "universal": {}}`,
+			fields: []string{"python_version"},
+		},
+		{
+			name: "target platform",
+			body: `{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": "numpy"}],
+				"targetPlatform": {"os": "linux"}, "universal": {}}`,
+			fields: []string{"target_platform"},
+		},
+		{
+			name: "invalid and duplicate environments",
+			body: `{"userId": "u", "packages": [{"packageName": "numpy"}],
+				"universal": {"pythonVersions": ["3.12", "2.7", "3.12.4"], "platforms": [{"os": "windows"}, {"os": "win32"}, {"os": "beos"}]}}`,
+			fields: []string{
+				"universal.python_versions[1]",
+				"universal.python_versions[2]",
+				"universal.platforms[1]",
+				"universal.platforms[2]",
+			},
+		},
+		{
+			name: "too many environments",
+			body: `{"userId": "u", "packages": [{"packageName": "numpy"}],
+				"universal": {"pythonVersions": ["3.5", "3.6", "3.7", "3.8", "3.9", "3.10", "3.11", "3.12", "3.13"],
+				"platforms": [{"os": "linux"}, {"os": "linux", "arch": "aarch64"}, {"os": "macos"}, {"os": "windows"}]}}`,
+			fields: []string{"universal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			errorResponse := decodeErrorResponse(t, w)
+			fields := make([]string, len(errorResponse.Violations))
+			for i, violation := range errorResponse.Violations {
+				fields[i] = violation.Field
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestStartAnalysis_ConstraintsAndOverrides(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupNegotiationTestRouter(mockProducer)
+
+	body := `{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": "requests"}],
+		"constraints": ["URLLib3 <2", "urllib3!=1.26.0"],
+		"overrides": ["Pydantic>=2; python_version >= '3.8'", "pydantic<2"]}`
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, *events, 1)
+	assert.Equal(t, []string{"urllib3<2", "urllib3!=1.26.0"}, (*events)[0].Constraints)
+	assert.Equal(t, []string{`pydantic>=2; python_version >= "3.8"`, "pydantic<2"}, (*events)[0].Overrides)
+}
+
+func TestStartAnalysis_RejectsInvalidConstraintsAndOverrides(t *testing.T) {
+	router := setupNegotiationTestRouter(mocks.NewMockKafkaProducer())
+
+	body := `{"userId": "u", "pythonVersion": "3.12", "packages": [{"packageName": "requests"}],
+		"constraints": ["urllib3<2", "requests[socks]>=2", "not a requirement!"],
+		"overrides": ["httpx @ https://example.com/httpx.whl", "pydantic>=2", "Pydantic<3"]}`
+	req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	errorResponse := decodeErrorResponse(t, w)
+	fields := make([]string, len(errorResponse.Violations))
+	for i, violation := range errorResponse.Violations {
+		fields[i] = violation.Field
+	}
+	assert.Equal(t, []string{"constraints[1]", "constraints[2]", "overrides[0]", "overrides[2]"}, fields)
+}
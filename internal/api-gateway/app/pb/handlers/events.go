@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/middleware"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/service"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/analysis"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const defaultHeartbeatInterval = 15 * time.Second
+
+type EventsHandler struct {
+	statusRepository  repository.StatusRepository
+	historyRepository repository.StatusHistoryRepository
+	broker            *service.StatusBroker
+	logger            logger.LoggerInterface
+	heartbeatInterval time.Duration
+}
+
+func NewEventsHandler(
+	statusRepository repository.StatusRepository,
+	historyRepository repository.StatusHistoryRepository,
+	broker *service.StatusBroker,
+	logger logger.LoggerInterface,
+) *EventsHandler {
+	return &EventsHandler{
+		statusRepository:  statusRepository,
+		historyRepository: historyRepository,
+		broker:            broker,
+		logger:            logger,
+		heartbeatInterval: defaultHeartbeatInterval,
+	}
+}
+
+// StreamEvents отдаёт события статуса анализа как Server-Sent Events.
+// Сначала отправляется история (после Last-Event-ID, если он передан), затем живые события.
+// Поток закрывается после терминального статуса. Для неизвестного анализа отвечает 404, как GetStatus.
+func (h *EventsHandler) StreamEvents(c *gin.Context) {
+	requestID := c.GetString("request_id")
+	analysisID := c.Param("id")
+
+	contextLogger := h.logger.WithRequestID(requestID)
+
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_VALIDATION_ERROR, "Invalid Last-Event-ID")
+		return
+	}
+
+	stream, err := service.OpenStatusStream(c.Request.Context(), h.broker,
+		h.statusRepository, h.historyRepository, analysisID, lastEventID)
+	if err != nil {
+		if errors.Is(err, repository.ErrStatusNotFound) {
+			middleware.SendProtobufError(c, http.StatusNotFound,
+				pbapi.ErrorCode_ERROR_CODE_ANALYSIS_NOT_FOUND, "Analysis not found")
+			return
+		}
+
+		contextLogger.Error("Failed to load analysis status",
+			zap.String("analysis_id", analysisID),
+			zap.Error(err),
+		)
+		middleware.SendProtobufError(c, http.StatusInternalServerError,
+			pbapi.ErrorCode_ERROR_CODE_STATUS_STORE_ERROR, "Failed to load analysis status")
+		return
+	}
+	defer stream.Close()
+
+	// Поток живёт дольше, чем WriteTimeout сервера
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	for _, event := range stream.History {
+		if done := h.writeEvent(c, event, stream); done {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-stream.Events():
+			if !ok {
+				contextLogger.Warn("Status stream subscriber dropped",
+					zap.String("analysis_id", analysisID),
+				)
+				return
+			}
+			if stream.IsNew(event) {
+				if done := h.writeEvent(c, event, stream); done {
+					return
+				}
+			}
+		case <-heartbeat.C:
+			c.Render(-1, sse.Event{Event: "heartbeat", Data: ""})
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeEvent пишет событие и сообщает, достигнут ли терминальный статус
+func (h *EventsHandler) writeEvent(c *gin.Context, event *eventspb.AnalysisStatusEvent, stream *service.StatusStream) bool {
+	data, err := protojson.Marshal(statusResponseFromEvent(event))
+	if err != nil {
+		h.logger.Error("Failed to serialize status event", zap.Error(err))
+		return false
+	}
+
+	id := repository.StatusEventID(event)
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatInt(id, 10),
+		Event: "status",
+		Data:  string(data),
+	})
+	c.Writer.Flush()
+	stream.MarkSent(event)
+
+	return analysis.IsTerminalStatus(event.Status)
+}
+
+func parseLastEventID(c *gin.Context) (int64, error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		// EventSource не умеет задавать заголовки при первом подключении
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
@@ -0,0 +1,188 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/handlers"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/service"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/mocks"
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var eventsBaseTime = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newStatusEvent(status string, progress int64, offset time.Duration) *eventspb.AnalysisStatusEvent {
+	return &eventspb.AnalysisStatusEvent{
+		RequestId:   "analysis-1",
+		Status:      status,
+		Message:     status,
+		Progress:    progress,
+		ServiceName: "dependency-resolver",
+		Timestamp:   timestamppb.New(eventsBaseTime.Add(offset)),
+	}
+}
+
+func setupEventsTestRouter(t *testing.T, history ...*eventspb.AnalysisStatusEvent) (*gin.Engine, *service.StatusBroker) {
+	gin.SetMode(gin.TestMode)
+
+	mockLogger := mocks.NewMockLogger()
+	mockLogger.On("WithRequestID", "test-id-123").Return(mockLogger)
+
+	statusRepository := repository.NewInMemoryStatusRepository()
+	historyRepository := repository.NewInMemoryStatusHistoryRepository()
+	for _, event := range history {
+		require.NoError(t, historyRepository.Append(t.Context(), event))
+		require.NoError(t, statusRepository.Save(t.Context(), event))
+	}
+
+	broker := service.NewStatusBroker()
+	handler := handlers.NewEventsHandler(statusRepository, historyRepository, broker, mockLogger)
+
+	router := gin.New()
+	router.GET("/analysis/:id/events", func(c *gin.Context) {
+		c.Set("request_id", "test-id-123")
+
+		handler.StreamEvents(c)
+	})
+
+	return router, broker
+}
+
+func TestStreamEvents_ReplaysHistoryAndClosesOnTerminalStatus(t *testing.T) {
+	router, _ := setupEventsTestRouter(t,
+		newStatusEvent("resolving", 40, 0),
+		newStatusEvent("completed", 100, time.Second),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/analysis/analysis-1/events", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	assert.Equal(t, 2, strings.Count(body, "event:status"))
+	assert.Contains(t, body, `"status":"resolving"`)
+	assert.Contains(t, body, `"status":"completed"`)
+	assert.Contains(t, body, `"serviceName":"dependency-resolver"`)
+	assert.Contains(t, body, "id:"+strconv.FormatInt(eventsBaseTime.UnixMicro(), 10))
+}
+
+func TestStreamEvents_ResumesAfterLastEventID(t *testing.T) {
+	router, _ := setupEventsTestRouter(t,
+		newStatusEvent("resolving", 40, 0),
+		newStatusEvent("completed", 100, time.Second),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/analysis/analysis-1/events", nil)
+	req.Header.Set("Last-Event-ID", strconv.FormatInt(eventsBaseTime.UnixMicro(), 10))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	assert.Equal(t, 1, strings.Count(body, "event:status"))
+	assert.NotContains(t, body, `"status":"resolving"`)
+	assert.Contains(t, body, `"status":"completed"`)
+}
+
+func TestStreamEvents_UnknownAnalysis(t *testing.T) {
+	router, _ := setupEventsTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/analysis/unknown/events", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_ANALYSIS_NOT_FOUND, decodeErrorResponse(t, w).Code)
+}
+
+func TestStreamEvents_InvalidLastEventID(t *testing.T) {
+	router, _ := setupEventsTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/analysis/analysis-1/events?last_event_id=abc", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_VALIDATION_ERROR, decodeErrorResponse(t, w).Code)
+}
+
+func TestStreamEvents_StreamsLiveEvents(t *testing.T) {
+	router, broker := setupEventsTestRouter(t, newStatusEvent("resolving", 40, 0))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	// Публикуем, пока обработчик не подписался и не получил терминальное событие
+	go func() {
+		for ctx.Err() == nil {
+			broker.Publish(ctx, newStatusEvent("resolving", 40, 0))
+			broker.Publish(ctx, newStatusEvent("completed", 100, time.Second))
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "/analysis/analysis-1/events", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	// Поток должен завершиться сам, не дожидаясь таймаута
+	require.NoError(t, ctx.Err())
+	cancel()
+
+	body := w.Body.String()
+	assert.Equal(t, 1, strings.Count(body, `"status":"resolving"`))
+	assert.Equal(t, 1, strings.Count(body, `"status":"completed"`))
+}
+
+func TestStreamEvents_KeepsLiveEventsWithSameOrMissingTimestamp(t *testing.T) {
+	router, broker := setupEventsTestRouter(t, newStatusEvent("resolving", 40, 0))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	sameMicrosecond := newStatusEvent("resolving", 60, 0)
+	withoutTimestamp := newStatusEvent("resolving", 80, 0)
+	withoutTimestamp.Timestamp = nil
+
+	go func() {
+		for ctx.Err() == nil {
+			broker.Publish(ctx, sameMicrosecond)
+			broker.Publish(ctx, withoutTimestamp)
+			broker.Publish(ctx, newStatusEvent("completed", 100, time.Second))
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "/analysis/analysis-1/events", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	require.NoError(t, ctx.Err())
+	cancel()
+
+	body := w.Body.String()
+	assert.Equal(t, 1, strings.Count(body, `"progress":"40"`))
+	assert.Equal(t, 1, strings.Count(body, `"progress":"60"`))
+	assert.Equal(t, 1, strings.Count(body, `"progress":"80"`))
+	assert.Equal(t, 1, strings.Count(body, `"status":"completed"`))
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/middleware"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type StatusHandler struct {
+	statusRepository repository.StatusRepository
+	logger           logger.LoggerInterface
+}
+
+func NewStatusHandler(statusRepository repository.StatusRepository, logger logger.LoggerInterface) *StatusHandler {
+	return &StatusHandler{
+		statusRepository: statusRepository,
+		logger:           logger,
+	}
+}
+
+func (h *StatusHandler) GetStatus(c *gin.Context) {
+	requestID := c.GetString("request_id")
+	analysisID := c.Param("id")
+
+	contextLogger := h.logger.WithRequestID(requestID)
+
+	if analysisID == "" {
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_VALIDATION_ERROR, "analysis id is required")
+		return
+	}
+
+	event, err := h.statusRepository.Get(c.Request.Context(), analysisID)
+	if err != nil {
+		if errors.Is(err, repository.ErrStatusNotFound) {
+			middleware.SendProtobufError(c, http.StatusNotFound,
+				pbapi.ErrorCode_ERROR_CODE_ANALYSIS_NOT_FOUND, "Analysis not found")
+			return
+		}
+
+		contextLogger.Error("Failed to load analysis status",
+			zap.String("analysis_id", analysisID),
+			zap.Error(err),
+		)
+		middleware.SendProtobufError(c, http.StatusInternalServerError,
+			pbapi.ErrorCode_ERROR_CODE_STATUS_STORE_ERROR, "Failed to load analysis status")
+		return
+	}
+
+	middleware.SendProtobufResponse(c, statusResponseFromEvent(event))
+}
+
+func statusResponseFromEvent(event *eventspb.AnalysisStatusEvent) *pbapi.StatusResponse {
+	return &pbapi.StatusResponse{
+		RequestId:   event.RequestId,
+		Status:      event.Status,
+		Message:     event.Message,
+		Progress:    event.Progress,
+		ServiceName: event.ServiceName,
+		UpdatedAt:   event.Timestamp,
+		Explanation: event.Explanation,
+	}
+}
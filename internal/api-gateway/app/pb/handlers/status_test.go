@@ -0,0 +1,139 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/handlers"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/mocks"
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func setupStatusTestRouter(statusRepository repository.StatusRepository, mockLogger *mocks.MockLogger) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	handler := handlers.NewStatusHandler(statusRepository, mockLogger)
+
+	router := gin.New()
+	router.GET("/analysis/:id/status", func(c *gin.Context) {
+		c.Set("request_id", "test-id-123")
+
+		handler.GetStatus(c)
+	})
+
+	return router
+}
+
+func TestGetStatus_Success(t *testing.T) {
+	mockLogger := mocks.NewMockLogger()
+	mockLogger.On("WithRequestID", "test-id-123").Return(mockLogger)
+
+	statusRepository := repository.NewInMemoryStatusRepository()
+	err := statusRepository.Save(t.Context(), &eventspb.AnalysisStatusEvent{
+		RequestId:   "analysis-1",
+		Status:      "resolving",
+		Message:     "Resolving transitive dependencies",
+		Progress:    40,
+		ServiceName: "dependency-resolver",
+		Timestamp:   timestamppb.Now(),
+	})
+	assert.NoError(t, err)
+
+	router := setupStatusTestRouter(statusRepository, mockLogger)
+
+	req := httptest.NewRequest(http.MethodGet, "/analysis/analysis-1/status", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	respProto := &pbapi.StatusResponse{}
+	assert.NoError(t, proto.Unmarshal(w.Body.Bytes(), respProto))
+	assert.Equal(t, "analysis-1", respProto.RequestId)
+	assert.Equal(t, "resolving", respProto.Status)
+	assert.Equal(t, "Resolving transitive dependencies", respProto.Message)
+	assert.Equal(t, int64(40), respProto.Progress)
+	assert.Equal(t, "dependency-resolver", respProto.ServiceName)
+	assert.NotNil(t, respProto.UpdatedAt)
+}
+
+func TestGetStatus_FailedWithExplanation(t *testing.T) {
+	mockLogger := mocks.NewMockLogger()
+	mockLogger.On("WithRequestID", "test-id-123").Return(mockLogger)
+
+	explanation := []string{
+		"Because flask 3.0.3 depends on werkzeug>=3.0.0 and the request requires werkzeug<3, flask>=3.0.3 cannot be used.",
+		"And because the request requires flask>=3, the requirements are unsatisfiable.",
+	}
+	statusRepository := repository.NewInMemoryStatusRepository()
+	err := statusRepository.Save(t.Context(), &eventspb.AnalysisStatusEvent{
+		RequestId:   "analysis-1",
+		Status:      "failed",
+		Message:     "dependency conflict",
+		ServiceName: "dependency-resolver",
+		Timestamp:   timestamppb.Now(),
+		Explanation: explanation,
+	})
+	assert.NoError(t, err)
+
+	router := setupStatusTestRouter(statusRepository, mockLogger)
+
+	req := httptest.NewRequest(http.MethodGet, "/analysis/analysis-1/status", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	respProto := &pbapi.StatusResponse{}
+	assert.NoError(t, proto.Unmarshal(w.Body.Bytes(), respProto))
+	assert.Equal(t, "failed", respProto.Status)
+	assert.Equal(t, explanation, respProto.Explanation)
+}
+
+func TestGetStatus_NotFound(t *testing.T) {
+	mockLogger := mocks.NewMockLogger()
+	mockLogger.On("WithRequestID", "test-id-123").Return(mockLogger)
+
+	router := setupStatusTestRouter(repository.NewInMemoryStatusRepository(), mockLogger)
+
+	req := httptest.NewRequest(http.MethodGet, "/analysis/unknown/status", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_ANALYSIS_NOT_FOUND, decodeErrorResponse(t, w).Code)
+}
+
+func TestGetStatus_StoreError(t *testing.T) {
+	mockLogger := mocks.NewMockLogger()
+	mockLogger.On("WithRequestID", "test-id-123").Return(mockLogger)
+	mockLogger.On("Error", mock.MatchedBy(func(msg string) bool {
+		return strings.Contains(msg, "Failed to load analysis status")
+	}), mock.Anything, mock.Anything).Return()
+
+	mockRepository := mocks.NewMockStatusRepository()
+	mockRepository.On("Get", mock.Anything, "analysis-1").Return(nil, assert.AnError)
+
+	router := setupStatusTestRouter(mockRepository, mockLogger)
+
+	req := httptest.NewRequest(http.MethodGet, "/analysis/analysis-1/status", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_STATUS_STORE_ERROR, decodeErrorResponse(t, w).Code)
+	mockRepository.AssertExpectations(t)
+}
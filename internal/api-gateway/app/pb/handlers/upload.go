@@ -0,0 +1,516 @@
+package handlers
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/middleware"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/service"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/analysis"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/lockfile"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pyproject"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/requirements"
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// uploadFormField — имя поля multipart-формы с загружаемым файлом
+	uploadFormField = "file"
+	// manifestFormField — поле с pyproject.toml или Pipfile для проверки lock-файла
+	manifestFormField = "manifest"
+	// lockfileFormField — поле с предыдущим lock-файлом для стратегии prefer-pinned
+	lockfileFormField = "lockfile"
+	// constraintsFormField и overridesFormField — поля с constraints и overrides в формате requirements.txt
+	constraintsFormField = "constraints"
+	overridesFormField   = "overrides"
+)
+
+var errUploadTooLarge = errors.New("uploaded file is too large")
+
+// UploadHandler запускает анализ по загруженному файлу зависимостей.
+// Файл передаётся телом запроса или полем "file" multipart-формы,
+// user_id, python_version, repository_url и остальные параметры — полями формы или query-параметрами.
+// Поле lockfile с предыдущим lock-файлом задаёт версии для стратегии prefer-pinned,
+// поля constraints и overrides — ограничения и замены версий в формате requirements.txt.
+// universal=true или список python_versions включают универсальное разрешение
+// для платформ из universal_platforms ("linux-aarch64", "macos", ...).
+type UploadHandler struct {
+	analysisService *service.AnalysisService
+	maxUploadSize   int64
+	logger          logger.LoggerInterface
+}
+
+func NewUploadHandler(analysisService *service.AnalysisService, maxUploadSize int64, logger logger.LoggerInterface) *UploadHandler {
+	return &UploadHandler{
+		analysisService: analysisService,
+		maxUploadSize:   maxUploadSize,
+		logger:          logger,
+	}
+}
+
+// UploadRequirements разбирает requirements.txt в формате pip
+func (h *UploadHandler) UploadRequirements(c *gin.Context) {
+	requestID := c.GetString("request_id")
+	contextLogger := h.logger.WithRequestID(requestID)
+
+	content, _, ok := h.readUpload(c, contextLogger)
+	if !ok {
+		return
+	}
+
+	file, err := requirements.Parse(bytes.NewReader(content))
+	if err != nil {
+		contextLogger.Warn("Failed to parse requirements file", zap.Error(err))
+
+		var parseErr *requirements.ParseError
+		if !errors.As(err, &parseErr) {
+			middleware.SendProtobufError(c, http.StatusBadRequest,
+				pbapi.ErrorCode_ERROR_CODE_INVALID_BODY, "Failed to read requirements file")
+			return
+		}
+
+		violations := make([]*pbapi.ErrorResponse_FieldViolation, len(parseErr.Errors))
+		for i, lineErr := range parseErr.Errors {
+			violations[i] = &pbapi.ErrorResponse_FieldViolation{
+				Field:       fmt.Sprintf("line %d", lineErr.Line),
+				Description: lineErr.Message,
+			}
+		}
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_REQUIREMENTS_PARSE_ERROR, "Failed to parse requirements file",
+			violations...)
+		return
+	}
+
+	request := uploadMetadata(c)
+	request.Packages = packagesFromRequirements(file.Requirements)
+	if !h.readPinnedVersions(c, request, contextLogger) || !h.readConstraintsAndOverrides(c, request, contextLogger) {
+		return
+	}
+
+	var warnings []string
+	for _, ref := range file.References {
+		hint := "upload its contents separately"
+		if ref.Kind == requirements.ReferenceConstraints {
+			hint = "upload it in the " + constraintsFormField + " field"
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"line %d: %s file %q is not resolved, %s", ref.Line, ref.Kind, ref.Path, hint))
+	}
+
+	h.startAnalysis(c, requestID, request, warnings)
+}
+
+// UploadPyproject разбирает pyproject.toml (PEP 621, Poetry, PDM).
+// Параметры extras и groups через запятую добавляют extras и dev-группы проекта,
+// а requires-python подставляется в python_version, если он не передан
+func (h *UploadHandler) UploadPyproject(c *gin.Context) {
+	requestID := c.GetString("request_id")
+	contextLogger := h.logger.WithRequestID(requestID)
+
+	content, _, ok := h.readUpload(c, contextLogger)
+	if !ok {
+		return
+	}
+
+	project, err := pyproject.Parse(content)
+	if err != nil {
+		contextLogger.Warn("Failed to parse pyproject.toml", zap.Error(err))
+
+		var parseErr *pyproject.ParseError
+		if !errors.As(err, &parseErr) {
+			middleware.SendProtobufError(c, http.StatusBadRequest,
+				pbapi.ErrorCode_ERROR_CODE_INVALID_BODY, "Failed to read pyproject.toml")
+			return
+		}
+
+		violations := make([]*pbapi.ErrorResponse_FieldViolation, len(parseErr.Errors))
+		for i, fieldErr := range parseErr.Errors {
+			violations[i] = &pbapi.ErrorResponse_FieldViolation{
+				Field:       fieldErr.Field,
+				Description: fieldErr.Message,
+			}
+		}
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_PYPROJECT_PARSE_ERROR, "Failed to parse pyproject.toml",
+			violations...)
+		return
+	}
+
+	reqs, err := project.Requirements(uploadListParam(c, "extras"), uploadListParam(c, "groups"))
+	if err != nil {
+		var fieldErr *pyproject.FieldError
+		if !errors.As(err, &fieldErr) {
+			contextLogger.Warn("Failed to select project requirements", zap.Error(err))
+			middleware.SendProtobufError(c, http.StatusBadRequest,
+				pbapi.ErrorCode_ERROR_CODE_VALIDATION_ERROR, "Request validation failed")
+			return
+		}
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_VALIDATION_ERROR, "Request validation failed",
+			&pbapi.ErrorResponse_FieldViolation{Field: fieldErr.Field, Description: fieldErr.Message})
+		return
+	}
+
+	request := uploadMetadata(c)
+	request.Packages = packagesFromRequirements(reqs)
+	if !h.readPinnedVersions(c, request, contextLogger) || !h.readConstraintsAndOverrides(c, request, contextLogger) {
+		return
+	}
+
+	warnings := project.Warnings
+	if request.PythonVersion == "" {
+		request.PythonVersion = pyproject.PythonVersion(project.RequiresPython)
+		if request.PythonVersion != "" {
+			warnings = append(warnings, fmt.Sprintf(
+				"python_version %s derived from requires-python %q", request.PythonVersion, project.RequiresPython))
+		}
+	}
+
+	h.startAnalysis(c, requestID, request, warnings)
+}
+
+// UploadLockfile импортирует poetry.lock, Pipfile.lock, uv.lock или pdm.lock.
+// Формат берётся из параметра format или имени файла. Пакеты уже закреплены,
+// поэтому событие помечается locked и разрешение зависимостей пропускается.
+// Если в поле manifest передан манифест, устаревший lock-файл возвращается предупреждением
+func (h *UploadHandler) UploadLockfile(c *gin.Context) {
+	requestID := c.GetString("request_id")
+	contextLogger := h.logger.WithRequestID(requestID)
+
+	content, filename, ok := h.readUpload(c, contextLogger)
+	if !ok {
+		return
+	}
+
+	format, err := lockfile.ParseFormat(cmp.Or(uploadParam(c, "format"), filename))
+	if err != nil {
+		contextLogger.Warn("Unknown lock file format", zap.Error(err))
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_VALIDATION_ERROR, "Request validation failed",
+			&pbapi.ErrorResponse_FieldViolation{
+				Field:       "format",
+				Description: "format must be one of poetry.lock, Pipfile.lock, uv.lock, pdm.lock",
+			})
+		return
+	}
+
+	lock, err := lockfile.Parse(format, content)
+	if err != nil {
+		contextLogger.Warn("Failed to parse lock file", zap.String("format", string(format)), zap.Error(err))
+
+		var parseErr *lockfile.ParseError
+		if !errors.As(err, &parseErr) {
+			middleware.SendProtobufError(c, http.StatusBadRequest,
+				pbapi.ErrorCode_ERROR_CODE_INVALID_BODY, fmt.Sprintf("Failed to read %s", format))
+			return
+		}
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_LOCKFILE_PARSE_ERROR, fmt.Sprintf("Failed to parse %s", format),
+			&pbapi.ErrorResponse_FieldViolation{Field: parseErr.Field, Description: parseErr.Message})
+		return
+	}
+
+	request := uploadMetadata(c)
+	request.Packages = packagesFromRequirements(lock.Packages)
+	request.Locked = true
+	if request.PythonVersion == "" {
+		request.PythonVersion = pyproject.PythonVersion(lock.RequiresPython)
+	}
+
+	warnings := lock.Warnings
+	if warning := h.checkManifest(c, lock, contextLogger); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	h.startAnalysis(c, requestID, request, warnings)
+}
+
+// checkManifest сверяет lock-файл с манифестом из формы и возвращает предупреждение
+func (h *UploadHandler) checkManifest(c *gin.Context, lock *lockfile.Lock, contextLogger logger.LoggerInterface) string {
+	header, err := c.FormFile(manifestFormField)
+	if err != nil {
+		return fmt.Sprintf("%s freshness was not verified, upload the manifest in the %q field", lock.Format, manifestFormField)
+	}
+
+	manifest, err := h.readFormFile(header)
+	if err != nil {
+		contextLogger.Warn("Failed to read manifest", zap.Error(err))
+		return fmt.Sprintf("manifest %q could not be read, %s freshness was not verified", header.Filename, lock.Format)
+	}
+
+	warning, err := lock.CheckManifest(manifest)
+	if err != nil {
+		contextLogger.Warn("Failed to check lock file against manifest", zap.Error(err))
+		return fmt.Sprintf("manifest %q could not be parsed (%s), %s freshness was not verified", header.Filename, err, lock.Format)
+	}
+	return warning
+}
+
+// readPinnedVersions берёт закреплённые версии из предыдущего lock-файла в поле lockfile.
+// Без явной стратегии с ним включается prefer-pinned. false — ответ с ошибкой уже отправлен
+func (h *UploadHandler) readPinnedVersions(c *gin.Context, request *pbapi.AnalyzeRequest, contextLogger logger.LoggerInterface) bool {
+	header, err := c.FormFile(lockfileFormField)
+	if err != nil {
+		return true
+	}
+
+	content, err := h.readFormFile(header)
+	if err != nil {
+		contextLogger.Warn("Failed to read previous lock file", zap.Error(err))
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_INVALID_BODY, "Failed to read previous lock file")
+		return false
+	}
+
+	format, err := lockfile.ParseFormat(header.Filename)
+	if err != nil {
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_VALIDATION_ERROR, "Request validation failed",
+			&pbapi.ErrorResponse_FieldViolation{
+				Field:       lockfileFormField,
+				Description: "lockfile must be named poetry.lock, Pipfile.lock, uv.lock or pdm.lock",
+			})
+		return false
+	}
+
+	lock, err := lockfile.Parse(format, content)
+	if err != nil {
+		contextLogger.Warn("Failed to parse previous lock file", zap.String("format", string(format)), zap.Error(err))
+
+		var parseErr *lockfile.ParseError
+		if !errors.As(err, &parseErr) {
+			middleware.SendProtobufError(c, http.StatusBadRequest,
+				pbapi.ErrorCode_ERROR_CODE_INVALID_BODY, fmt.Sprintf("Failed to read %s", format))
+			return false
+		}
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_LOCKFILE_PARSE_ERROR, fmt.Sprintf("Failed to parse %s", format),
+			&pbapi.ErrorResponse_FieldViolation{
+				Field:       lockfileFormField + "." + parseErr.Field,
+				Description: parseErr.Message,
+			})
+		return false
+	}
+
+	request.PinnedVersions = make(map[string]string, len(lock.Packages))
+	for _, pkg := range lock.Packages {
+		// Пакеты из прямых ссылок версии не имеют
+		if version, ok := strings.CutPrefix(pkg.Specifier, "=="); ok {
+			request.PinnedVersions[pkg.Name] = version
+		}
+	}
+	if request.ResolutionStrategy == "" {
+		request.ResolutionStrategy = analysis.StrategyPreferPinned
+	}
+	return true
+}
+
+// readConstraintsAndOverrides берёт constraints и overrides из полей формы. false — ответ с ошибкой уже отправлен
+func (h *UploadHandler) readConstraintsAndOverrides(c *gin.Context, request *pbapi.AnalyzeRequest, contextLogger logger.LoggerInterface) bool {
+	var ok bool
+	if request.Constraints, ok = h.readRequirementsField(c, constraintsFormField, contextLogger); !ok {
+		return false
+	}
+	request.Overrides, ok = h.readRequirementsField(c, overridesFormField, contextLogger)
+	return ok
+}
+
+// readRequirementsField разбирает поле формы в формате requirements.txt в строки PEP 508
+func (h *UploadHandler) readRequirementsField(c *gin.Context, field string, contextLogger logger.LoggerInterface) ([]string, bool) {
+	header, err := c.FormFile(field)
+	if err != nil {
+		return nil, true
+	}
+
+	content, err := h.readFormFile(header)
+	if err != nil {
+		contextLogger.Warn("Failed to read form file", zap.String("field", field), zap.Error(err))
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_INVALID_BODY, fmt.Sprintf("Failed to read %s file", field))
+		return nil, false
+	}
+
+	file, err := requirements.Parse(bytes.NewReader(content))
+	if err != nil {
+		contextLogger.Warn("Failed to parse form file", zap.String("field", field), zap.Error(err))
+
+		var violations []*pbapi.ErrorResponse_FieldViolation
+		var parseErr *requirements.ParseError
+		if errors.As(err, &parseErr) {
+			for _, lineErr := range parseErr.Errors {
+				violations = append(violations, &pbapi.ErrorResponse_FieldViolation{
+					Field:       fmt.Sprintf("%s line %d", field, lineErr.Line),
+					Description: lineErr.Message,
+				})
+			}
+		}
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_REQUIREMENTS_PARSE_ERROR, fmt.Sprintf("Failed to parse %s file", field),
+			violations...)
+		return nil, false
+	}
+
+	lines := make([]string, len(file.Requirements))
+	for i, requirement := range file.Requirements {
+		lines[i] = requirement.String()
+	}
+	return lines, true
+}
+
+func (h *UploadHandler) startAnalysis(c *gin.Context, requestID string, request *pbapi.AnalyzeRequest, warnings []string) {
+	response, err := h.analysisService.StartAnalysis(c.Request.Context(), requestID, request)
+	if err != nil {
+		sendStartAnalysisError(c, err)
+		return
+	}
+
+	response.Warnings = warnings
+	middleware.SendProtobufResponse(c, response)
+}
+
+// readUpload читает файл из multipart-формы или из тела запроса.
+// Имя файла известно только для multipart-формы
+func (h *UploadHandler) readUpload(c *gin.Context, contextLogger logger.LoggerInterface) ([]byte, string, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize)
+
+	content, filename, err := h.readUploadContent(c)
+	if err != nil {
+		contextLogger.Warn("Failed to read uploaded file", zap.Error(err))
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.Is(err, errUploadTooLarge) || errors.As(err, &maxBytesErr) {
+			middleware.SendProtobufError(c, http.StatusRequestEntityTooLarge,
+				pbapi.ErrorCode_ERROR_CODE_INVALID_BODY,
+				fmt.Sprintf("Uploaded file exceeds %d bytes", h.maxUploadSize))
+			return nil, "", false
+		}
+
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_INVALID_BODY, "Failed to read uploaded file")
+		return nil, "", false
+	}
+
+	if len(bytes.TrimSpace(content)) == 0 {
+		middleware.SendProtobufError(c, http.StatusBadRequest,
+			pbapi.ErrorCode_ERROR_CODE_EMPTY_BODY, "Empty file uploaded")
+		return nil, "", false
+	}
+
+	return content, filename, true
+}
+
+func (h *UploadHandler) readUploadContent(c *gin.Context) ([]byte, string, error) {
+	if !strings.HasPrefix(c.ContentType(), gin.MIMEMultipartPOSTForm) {
+		content, err := io.ReadAll(c.Request.Body)
+		return content, "", err
+	}
+
+	header, err := c.FormFile(uploadFormField)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := h.readFormFile(header)
+	return content, header.Filename, err
+}
+
+func (h *UploadHandler) readFormFile(header *multipart.FileHeader) ([]byte, error) {
+	if header.Size > h.maxUploadSize {
+		return nil, errUploadTooLarge
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+func uploadMetadata(c *gin.Context) *pbapi.AnalyzeRequest {
+	return &pbapi.AnalyzeRequest{
+		UserId:             uploadParam(c, "user_id"),
+		PythonVersion:      uploadParam(c, "python_version"),
+		RepositoryUrl:      uploadParam(c, "repository_url"),
+		TargetPlatform:     uploadPlatform(c),
+		ResolutionStrategy: uploadParam(c, "resolution_strategy"),
+		Universal:          uploadUniversal(c),
+	}
+}
+
+// uploadUniversal собирает окружения универсального разрешения, nil — разрешение для одного окружения
+func uploadUniversal(c *gin.Context) *pbapi.UniversalResolution {
+	universal := &pbapi.UniversalResolution{PythonVersions: uploadListParam(c, "python_versions")}
+	for _, value := range uploadListParam(c, "universal_platforms") {
+		// Архитектуры пишутся через "_", поэтому ОС отделяется первым "-"
+		osName, arch, _ := strings.Cut(value, "-")
+		universal.Platforms = append(universal.Platforms, &pbapi.TargetPlatform{Os: osName, Arch: arch})
+	}
+
+	enabled, _ := strconv.ParseBool(uploadParam(c, "universal"))
+	if !enabled && len(universal.PythonVersions) == 0 && len(universal.Platforms) == 0 {
+		return nil
+	}
+	return universal
+}
+
+// uploadPlatform собирает целевую платформу из параметров platform_*, nil — ни один не задан
+func uploadPlatform(c *gin.Context) *pbapi.TargetPlatform {
+	platform := &pbapi.TargetPlatform{
+		Os:           uploadParam(c, "platform_os"),
+		Arch:         uploadParam(c, "platform_arch"),
+		Libc:         uploadParam(c, "platform_libc"),
+		LibcVersion:  uploadParam(c, "platform_libc_version"),
+		MacosVersion: uploadParam(c, "platform_macos_version"),
+	}
+	if cmp.Or(platform.Os, platform.Arch, platform.Libc, platform.LibcVersion, platform.MacosVersion) == "" {
+		return nil
+	}
+	return platform
+}
+
+// uploadParam берёт значение из формы, а если его нет — из query-параметров
+func uploadParam(c *gin.Context, key string) string {
+	if value := c.PostForm(key); value != "" {
+		return value
+	}
+	return c.Query(key)
+}
+
+// uploadListParam разбирает параметр со списком значений через запятую
+func uploadListParam(c *gin.Context, key string) []string {
+	var values []string
+	for value := range strings.SplitSeq(uploadParam(c, key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func packagesFromRequirements(reqs []*requirements.Requirement) []*pbapi.AnalyzeRequest_RequiredPackage {
+	packages := make([]*pbapi.AnalyzeRequest_RequiredPackage, len(reqs))
+	for i, req := range reqs {
+		packages[i] = &pbapi.AnalyzeRequest_RequiredPackage{
+			PackageName:    req.Name,
+			PackageVersion: req.Specifier,
+			Extras:         req.Extras,
+			Marker:         req.Marker,
+			Url:            req.URL,
+			Hashes:         req.Hashes,
+		}
+	}
+	return packages
+}
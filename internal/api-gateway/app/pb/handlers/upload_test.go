@@ -0,0 +1,477 @@
+package handlers_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/handlers"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/middleware"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/service"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/mocks"
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func setupUploadTestRouter(mockProducer *mocks.MockKafkaProducer, maxUploadSize int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	mockLogger := mocks.NewMockLogger()
+	mockLogger.On("WithRequestID", "test-id-123").Return(mockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warn", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warn", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return()
+
+	handler := handlers.NewUploadHandler(service.NewAnalysisService(mockProducer, nil, mockLogger), maxUploadSize, mockLogger)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("request_id", "test-id-123")
+	})
+	router.Use(middleware.ProtobufMiddleware())
+	router.POST("/analysis/requirements", handler.UploadRequirements)
+	router.POST("/analysis/pyproject", handler.UploadPyproject)
+	router.POST("/analysis/lockfile", handler.UploadLockfile)
+
+	return router
+}
+
+func captureStartedEvents(mockProducer *mocks.MockKafkaProducer) *[]*eventspb.AnalysisStartedEvent {
+	var events []*eventspb.AnalysisStartedEvent
+	mockProducer.On("PublishEvent", mock.Anything, mock.AnythingOfType("*kafka_message.AnalysisStartedEvent")).
+		Run(func(args mock.Arguments) {
+			events = append(events, args.Get(1).(*eventspb.AnalysisStartedEvent))
+		}).
+		Return(nil)
+	return &events
+}
+
+func TestUploadRequirements_RawBody(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupUploadTestRouter(mockProducer, 1024)
+
+	body := `requests[socks]>=2.28 ; python_version >= "3.8"
+django==4.2.1 --hash=sha256:abc123
+-r dev.txt
+`
+	req := httptest.NewRequest(http.MethodPost,
+		"/analysis/requirements?user_id=user123&python_version=3.11", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response pbapi.AnalyzeResponse
+	require.NoError(t, protojson.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.RequestId)
+	require.Len(t, response.Warnings, 1)
+	assert.Contains(t, response.Warnings[0], `line 3: requirements file "dev.txt"`)
+
+	require.Len(t, *events, 1)
+	event := (*events)[0]
+	assert.Equal(t, "user123", event.UserId)
+	assert.Equal(t, "3.11", event.PythonVersion)
+	require.Len(t, event.Packages, 2)
+	assert.Equal(t, "requests", event.Packages[0].PackageName)
+	assert.Equal(t, ">=2.28", event.Packages[0].PackageVersion)
+	assert.Equal(t, []string{"socks"}, event.Packages[0].Extras)
+	assert.Equal(t, `python_version >= "3.8"`, event.Packages[0].Marker)
+	assert.Equal(t, []string{"sha256:abc123"}, event.Packages[1].Hashes)
+}
+
+func TestUploadRequirements_Multipart(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupUploadTestRouter(mockProducer, 1024)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("user_id", "user123"))
+	require.NoError(t, writer.WriteField("python_version", "3.12"))
+	part, err := writer.CreateFormFile("file", "requirements.txt")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("flask\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/analysis/requirements", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, *events, 1)
+	assert.Equal(t, "3.12", (*events)[0].PythonVersion)
+	assert.Equal(t, "flask", (*events)[0].Packages[0].PackageName)
+}
+
+func TestUploadRequirements_ParseErrorsReportLines(t *testing.T) {
+	router := setupUploadTestRouter(mocks.NewMockKafkaProducer(), 1024)
+
+	body := "requests\ndjango=>4\n\nflask --hash=bad\n"
+	req := httptest.NewRequest(http.MethodPost,
+		"/analysis/requirements?user_id=user123&python_version=3.11", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	errorResponse := decodeErrorResponse(t, w)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_REQUIREMENTS_PARSE_ERROR, errorResponse.Code)
+	require.Len(t, errorResponse.Violations, 2)
+	assert.Equal(t, "line 2", errorResponse.Violations[0].Field)
+	assert.Equal(t, "line 4", errorResponse.Violations[1].Field)
+}
+
+func TestUploadRequirements_TooLarge(t *testing.T) {
+	router := setupUploadTestRouter(mocks.NewMockKafkaProducer(), 8)
+
+	req := httptest.NewRequest(http.MethodPost, "/analysis/requirements", strings.NewReader("requests==2.28.1\n"))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+const testPyproject = `
+[project]
+name = "demo"
+requires-python = ">=3.10"
+dependencies = ["requests>=2.28"]
+
+[project.optional-dependencies]
+cache = ["redis>=5"]
+
+[tool.poetry.group.test.dependencies]
+pytest = "^7.4"
+`
+
+func TestUploadPyproject_PrefillsPythonVersion(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupUploadTestRouter(mockProducer, 4096)
+
+	req := httptest.NewRequest(http.MethodPost,
+		"/analysis/pyproject?user_id=user123&extras=cache&groups=test", strings.NewReader(testPyproject))
+	req.Header.Set("Content-Type", "application/toml")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response pbapi.AnalyzeResponse
+	require.NoError(t, protojson.Unmarshal(w.Body.Bytes(), &response))
+	assert.Contains(t, response.Warnings, `python_version 3.10 derived from requires-python ">=3.10"`)
+
+	require.Len(t, *events, 1)
+	event := (*events)[0]
+	assert.Equal(t, "3.10", event.PythonVersion)
+
+	packages := make(map[string]string)
+	for _, pkg := range event.Packages {
+		packages[pkg.PackageName] = pkg.PackageVersion
+	}
+	assert.Equal(t, map[string]string{
+		"requests": ">=2.28",
+		"redis":    ">=5",
+		"pytest":   ">=7.4,<8",
+	}, packages)
+}
+
+func TestUploadPyproject_UnknownExtra(t *testing.T) {
+	router := setupUploadTestRouter(mocks.NewMockKafkaProducer(), 4096)
+
+	req := httptest.NewRequest(http.MethodPost,
+		"/analysis/pyproject?user_id=user123&extras=missing", strings.NewReader(testPyproject))
+	req.Header.Set("Content-Type", "application/toml")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	errorResponse := decodeErrorResponse(t, w)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_VALIDATION_ERROR, errorResponse.Code)
+	require.Len(t, errorResponse.Violations, 1)
+	assert.Equal(t, "extras", errorResponse.Violations[0].Field)
+}
+
+func TestUploadPyproject_ParseError(t *testing.T) {
+	router := setupUploadTestRouter(mocks.NewMockKafkaProducer(), 4096)
+
+	req := httptest.NewRequest(http.MethodPost,
+		"/analysis/pyproject?user_id=user123", strings.NewReader("[project]\ndependencies = [\"bad>>1\"]\n"))
+	req.Header.Set("Content-Type", "application/toml")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	errorResponse := decodeErrorResponse(t, w)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_PYPROJECT_PARSE_ERROR, errorResponse.Code)
+	assert.Equal(t, "project.dependencies[0]", errorResponse.Violations[0].Field)
+}
+
+const testPoetryLock = `
+[[package]]
+name = "requests"
+version = "2.32.3"
+files = [{file = "requests-2.32.3-py3-none-any.whl", hash = "sha256:70761cfe"}]
+
+[[package]]
+name = "tomli"
+version = "2.0.1"
+markers = "python_version < \"3.11\""
+files = []
+
+[metadata]
+lock-version = "2.0"
+python-versions = "^3.9"
+content-hash = "0000000000000000000000000000000000000000000000000000000000000000"
+`
+
+func TestUploadLockfile_MultipartWithStaleManifest(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupUploadTestRouter(mockProducer, 4096)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("user_id", "user123"))
+	part, err := writer.CreateFormFile("file", "poetry.lock")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(testPoetryLock))
+	require.NoError(t, err)
+	part, err = writer.CreateFormFile("manifest", "pyproject.toml")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("[tool.poetry.dependencies]\npython = \"^3.9\"\nrequests = \"^2.32\"\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/analysis/lockfile", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response pbapi.AnalyzeResponse
+	require.NoError(t, protojson.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Warnings, 1)
+	assert.Contains(t, response.Warnings[0], "the lock file is stale")
+
+	require.Len(t, *events, 1)
+	event := (*events)[0]
+	assert.True(t, event.Locked)
+	assert.Equal(t, "3.9", event.PythonVersion)
+	require.Len(t, event.Packages, 2)
+	assert.Equal(t, "==2.32.3", event.Packages[0].PackageVersion)
+	assert.Equal(t, []string{"sha256:70761cfe"}, event.Packages[0].Hashes)
+	assert.Equal(t, `python_version < "3.11"`, event.Packages[1].Marker)
+}
+
+func TestUploadLockfile_RawBodyRequiresFormat(t *testing.T) {
+	router := setupUploadTestRouter(mocks.NewMockKafkaProducer(), 4096)
+
+	req := httptest.NewRequest(http.MethodPost, "/analysis/lockfile?user_id=user123", strings.NewReader(testPoetryLock))
+	req.Header.Set("Content-Type", "application/toml")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	errorResponse := decodeErrorResponse(t, w)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_VALIDATION_ERROR, errorResponse.Code)
+	assert.Equal(t, "format", errorResponse.Violations[0].Field)
+}
+
+func TestUploadLockfile_ParseError(t *testing.T) {
+	router := setupUploadTestRouter(mocks.NewMockKafkaProducer(), 4096)
+
+	req := httptest.NewRequest(http.MethodPost, "/analysis/lockfile?user_id=user123&format=pipenv",
+		strings.NewReader(`{"default": {"requests": {"version": ">=2"}}}`))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	errorResponse := decodeErrorResponse(t, w)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_LOCKFILE_PARSE_ERROR, errorResponse.Code)
+	assert.Equal(t, "default.requests", errorResponse.Violations[0].Field)
+}
+
+func TestUploadRequirements_TargetPlatformParams(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupUploadTestRouter(mockProducer, 1024)
+
+	req := httptest.NewRequest(http.MethodPost,
+		"/analysis/requirements?user_id=user123&python_version=3.11&platform_libc=musl&platform_arch=arm64",
+		strings.NewReader("numpy\n"))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, *events, 1)
+	platform := (*events)[0].TargetPlatform
+	require.NotNil(t, platform)
+	assert.Equal(t, "linux", platform.Os)
+	assert.Equal(t, "aarch64", platform.Arch)
+	assert.Equal(t, "musl", platform.Libc)
+	assert.Equal(t, "1.2", platform.LibcVersion)
+}
+
+func TestUploadRequirements_PreviousLockfilePinsVersions(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupUploadTestRouter(mockProducer, 4096)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("user_id", "user123"))
+	require.NoError(t, writer.WriteField("python_version", "3.12"))
+	part, err := writer.CreateFormFile("file", "requirements.txt")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("requests>=2\n"))
+	require.NoError(t, err)
+	part, err = writer.CreateFormFile("lockfile", "poetry.lock")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(testPoetryLock))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/analysis/requirements", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, *events, 1)
+	event := (*events)[0]
+	assert.False(t, event.Locked)
+	assert.Equal(t, "prefer-pinned", event.ResolutionStrategy)
+	assert.Equal(t, map[string]string{"requests": "2.32.3", "tomli": "2.0.1"}, event.PinnedVersions)
+}
+
+func TestUploadRequirements_ResolutionStrategyParam(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupUploadTestRouter(mockProducer, 1024)
+
+	req := httptest.NewRequest(http.MethodPost,
+		"/analysis/requirements?user_id=user123&python_version=3.12&resolution_strategy=lowest",
+		strings.NewReader("requests>=2\n"))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, *events, 1)
+	assert.Equal(t, "lowest", (*events)[0].ResolutionStrategy)
+	assert.Empty(t, (*events)[0].PinnedVersions)
+}
+
+func TestUploadRequirements_UniversalParams(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupUploadTestRouter(mockProducer, 1024)
+
+	req := httptest.NewRequest(http.MethodPost,
+		"/analysis/requirements?user_id=user123&python_versions=3.9,3.13&universal_platforms=linux-aarch64,windows",
+		strings.NewReader("numpy>=1.26\n"))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, *events, 1)
+	universal := (*events)[0].Universal
+	require.NotNil(t, universal)
+	assert.Equal(t, []string{"3.9", "3.13"}, universal.PythonVersions)
+	require.Len(t, universal.Platforms, 2)
+	assert.Equal(t, "aarch64", universal.Platforms[0].Arch)
+	assert.Equal(t, "windows", universal.Platforms[1].Os)
+	assert.Equal(t, "x86_64", universal.Platforms[1].Arch)
+}
+
+func TestUploadRequirements_ConstraintsAndOverridesFiles(t *testing.T) {
+	mockProducer := mocks.NewMockKafkaProducer()
+	events := captureStartedEvents(mockProducer)
+	router := setupUploadTestRouter(mockProducer, 4096)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("user_id", "user123"))
+	require.NoError(t, writer.WriteField("python_version", "3.12"))
+	for field, content := range map[string]string{
+		"file":        "requests>=2\n-c constraints.txt\n",
+		"constraints": "# org-wide pins\nurllib3<2\nidna>=3 ; python_version >= \"3.8\"\n",
+		"overrides":   "charset-normalizer==3.3.2\n",
+	} {
+		part, err := writer.CreateFormFile(field, field+".txt")
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/analysis/requirements", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, *events, 1)
+	event := (*events)[0]
+	assert.Equal(t, []string{"urllib3<2", `idna>=3; python_version >= "3.8"`}, event.Constraints)
+	assert.Equal(t, []string{"charset-normalizer==3.3.2"}, event.Overrides)
+
+	var response pbapi.AnalyzeResponse
+	require.NoError(t, protojson.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Warnings, 1)
+	assert.Contains(t, response.Warnings[0], "upload it in the constraints field")
+}
+
+func TestUploadRequirements_InvalidConstraintsFile(t *testing.T) {
+	router := setupUploadTestRouter(mocks.NewMockKafkaProducer(), 4096)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("user_id", "user123"))
+	require.NoError(t, writer.WriteField("python_version", "3.12"))
+	part, err := writer.CreateFormFile("file", "requirements.txt")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("requests>=2\n"))
+	require.NoError(t, err)
+	part, err = writer.CreateFormFile("constraints", "constraints.txt")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("urllib3<2\nurllib3 >>= 2\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/analysis/requirements", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	errorResponse := decodeErrorResponse(t, w)
+	assert.Equal(t, pbapi.ErrorCode_ERROR_CODE_REQUIREMENTS_PARSE_ERROR, errorResponse.Code)
+	require.Len(t, errorResponse.Violations, 1)
+	assert.Equal(t, "constraints line 2", errorResponse.Violations[0].Field)
+}
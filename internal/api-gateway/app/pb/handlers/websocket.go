@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/service"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/analysis"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Сабпротоколы WebSocket определяют кодировку кадров в обе стороны
+const (
+	SubprotocolProtobuf = "analysis-status.v1.proto"
+	SubprotocolJSON     = "analysis-status.v1.json"
+)
+
+const (
+	wsWriteWait      = 10 * time.Second
+	wsPongWait       = 60 * time.Second
+	wsPingPeriod     = wsPongWait * 9 / 10
+	wsMaxMessageSize = 64 * 1024
+)
+
+type WebSocketHandler struct {
+	statusRepository repository.StatusRepository
+	broker           *service.StatusBroker
+	logger           logger.LoggerInterface
+	upgrader         websocket.Upgrader
+}
+
+func NewWebSocketHandler(
+	statusRepository repository.StatusRepository,
+	broker *service.StatusBroker,
+	allowedOrigins []string,
+	logger logger.LoggerInterface,
+) *WebSocketHandler {
+	return &WebSocketHandler{
+		statusRepository: statusRepository,
+		broker:           broker,
+		logger:           logger,
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{SubprotocolProtobuf, SubprotocolJSON},
+			CheckOrigin:  originChecker(allowedOrigins),
+		},
+	}
+}
+
+// wsCodec кодирует кадры в согласованном сабпротоколе
+type wsCodec struct {
+	messageType int
+	marshal     func(proto.Message) ([]byte, error)
+	unmarshal   func([]byte, proto.Message) error
+}
+
+func codecForSubprotocol(subprotocol string) wsCodec {
+	if subprotocol == SubprotocolProtobuf {
+		return wsCodec{
+			messageType: websocket.BinaryMessage,
+			marshal:     proto.Marshal,
+			unmarshal:   proto.Unmarshal,
+		}
+	}
+
+	// JSON используется и когда клиент не запросил сабпротокол
+	return wsCodec{
+		messageType: websocket.TextMessage,
+		marshal:     protojson.Marshal,
+		unmarshal:   protojson.Unmarshal,
+	}
+}
+
+// Subscribe открывает WebSocket, по которому клиент управляет подписками
+// командами SubscriptionRequest и получает кадры AnalysisStatusEvent
+func (h *WebSocketHandler) Subscribe(c *gin.Context) {
+	requestID := c.GetString("request_id")
+	contextLogger := h.logger.WithRequestID(requestID)
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrader уже записал ответ с ошибкой
+		contextLogger.Warn("WebSocket upgrade failed", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	codec := codecForSubprotocol(conn.Subprotocol())
+	subscription := h.broker.Subscribe()
+	defer subscription.Close()
+
+	commands := make(chan *pbapi.SubscriptionRequest)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go h.readCommands(conn, codec, commands, readErr, done)
+
+	ping := time.NewTicker(wsPingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case err := <-readErr:
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				contextLogger.Debug("WebSocket read loop stopped", zap.Error(err))
+			}
+			h.writeClose(conn, err)
+			return
+
+		case command := <-commands:
+			if err := h.applyCommand(c, conn, codec, subscription, command); err != nil {
+				contextLogger.Debug("WebSocket write failed", zap.Error(err))
+				return
+			}
+
+		case event, ok := <-subscription.Events():
+			if !ok {
+				contextLogger.Warn("WebSocket subscriber dropped")
+				h.writeClose(conn, errSubscriberLagging)
+				return
+			}
+			if !subscription.Has(event.RequestId) {
+				continue
+			}
+			if err := h.writeEvent(conn, codec, event); err != nil {
+				contextLogger.Debug("WebSocket write failed", zap.Error(err))
+				return
+			}
+			if analysis.IsTerminalStatus(event.Status) {
+				subscription.Remove(event.RequestId)
+			}
+
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+var (
+	errSubscriberLagging = errors.New("subscriber is too slow")
+	errInvalidCommand    = errors.New("invalid subscription command")
+)
+
+func (h *WebSocketHandler) readCommands(
+	conn *websocket.Conn,
+	codec wsCodec,
+	commands chan<- *pbapi.SubscriptionRequest,
+	readErr chan<- error,
+	done <-chan struct{},
+) {
+	conn.SetReadLimit(wsMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			readErr <- err
+			return
+		}
+
+		var command pbapi.SubscriptionRequest
+		if err := codec.unmarshal(data, &command); err != nil {
+			readErr <- fmt.Errorf("%w: %v", errInvalidCommand, err)
+			return
+		}
+		if command.Action == pbapi.SubscriptionRequest_ACTION_UNSPECIFIED || len(command.RequestIds) == 0 {
+			readErr <- fmt.Errorf("%w: action and request_ids are required", errInvalidCommand)
+			return
+		}
+
+		select {
+		case commands <- &command:
+		case <-done:
+			return
+		}
+	}
+}
+
+func (h *WebSocketHandler) applyCommand(
+	c *gin.Context,
+	conn *websocket.Conn,
+	codec wsCodec,
+	subscription *service.StatusSubscription,
+	command *pbapi.SubscriptionRequest,
+) error {
+	if command.Action == pbapi.SubscriptionRequest_ACTION_UNSUBSCRIBE {
+		subscription.Remove(command.RequestIds...)
+		return nil
+	}
+
+	subscription.Add(command.RequestIds...)
+
+	// Сразу отдаём последний известный статус, чтобы клиенту не ждать следующего события
+	for _, requestID := range command.RequestIds {
+		event, err := h.statusRepository.Get(c.Request.Context(), requestID)
+		if err != nil {
+			if !errors.Is(err, repository.ErrStatusNotFound) {
+				h.logger.Error("Failed to load analysis status",
+					zap.String("analysis_id", requestID),
+					zap.Error(err),
+				)
+			}
+			continue
+		}
+
+		if err := h.writeEvent(conn, codec, event); err != nil {
+			return err
+		}
+		if analysis.IsTerminalStatus(event.Status) {
+			subscription.Remove(requestID)
+		}
+	}
+
+	return nil
+}
+
+func (h *WebSocketHandler) writeEvent(conn *websocket.Conn, codec wsCodec, event *eventspb.AnalysisStatusEvent) error {
+	data, err := codec.marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to serialize status event: %w", err)
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return conn.WriteMessage(codec.messageType, data)
+}
+
+func (h *WebSocketHandler) writeClose(conn *websocket.Conn, reason error) {
+	code := websocket.CloseNormalClosure
+	text := ""
+
+	switch {
+	case errors.Is(reason, errInvalidCommand):
+		code, text = websocket.CloseUnsupportedData, reason.Error()
+	case errors.Is(reason, errSubscriberLagging):
+		code, text = websocket.CloseTryAgainLater, reason.Error()
+	}
+
+	conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, text),
+		time.Now().Add(wsWriteWait))
+}
+
+func originChecker(allowedOrigins []string) func(r *http.Request) bool {
+	if len(allowedOrigins) == 0 || slices.Contains(allowedOrigins, "*") {
+		return func(r *http.Request) bool { return true }
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		if _, err := url.Parse(origin); err != nil {
+			return false
+		}
+		return slices.Contains(allowedOrigins, origin)
+	}
+}
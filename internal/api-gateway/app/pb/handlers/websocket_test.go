@@ -0,0 +1,172 @@
+package handlers_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/handlers"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/service"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/mocks"
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func setupWebSocketTestServer(t *testing.T, statusRepository repository.StatusRepository) (*httptest.Server, *service.StatusBroker) {
+	gin.SetMode(gin.TestMode)
+
+	mockLogger := mocks.NewMockLogger()
+	mockLogger.On("WithRequestID", mock.Anything).Return(mockLogger)
+	mockLogger.On("Debug", mock.Anything, mock.Anything).Return()
+
+	broker := service.NewStatusBroker()
+	handler := handlers.NewWebSocketHandler(statusRepository, broker, []string{"*"}, mockLogger)
+
+	router := gin.New()
+	router.GET("/analysis/subscribe", handler.Subscribe)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server, broker
+}
+
+func dialWebSocket(t *testing.T, server *httptest.Server, subprotocol string) *websocket.Conn {
+	dialer := websocket.Dialer{Subprotocols: []string{subprotocol}}
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/analysis/subscribe"
+
+	conn, resp, err := dialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	assert.Equal(t, subprotocol, resp.Header.Get("Sec-WebSocket-Protocol"))
+
+	return conn
+}
+
+// publishUntilReceived публикует событие, пока команда подписки не дойдёт до обработчика
+func publishUntilReceived(t *testing.T, broker *service.StatusBroker, event *eventspb.AnalysisStatusEvent, receive func() *eventspb.AnalysisStatusEvent) *eventspb.AnalysisStatusEvent {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				broker.Publish(t.Context(), event)
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+	}()
+
+	return receive()
+}
+
+func TestWebSocket_ProtobufSubprotocol(t *testing.T) {
+	server, broker := setupWebSocketTestServer(t, repository.NewInMemoryStatusRepository())
+	conn := dialWebSocket(t, server, handlers.SubprotocolProtobuf)
+
+	command, err := proto.Marshal(&pbapi.SubscriptionRequest{
+		Action:     pbapi.SubscriptionRequest_ACTION_SUBSCRIBE,
+		RequestIds: []string{"analysis-1", "analysis-2"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, command))
+
+	receive := func() *eventspb.AnalysisStatusEvent {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		messageType, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, websocket.BinaryMessage, messageType)
+
+		var event eventspb.AnalysisStatusEvent
+		require.NoError(t, proto.Unmarshal(data, &event))
+		return &event
+	}
+
+	event := publishUntilReceived(t, broker, &eventspb.AnalysisStatusEvent{
+		RequestId: "analysis-2",
+		Status:    "resolving",
+		Progress:  30,
+	}, receive)
+
+	assert.Equal(t, "analysis-2", event.RequestId)
+	assert.Equal(t, "resolving", event.Status)
+	assert.Equal(t, int64(30), event.Progress)
+}
+
+func TestWebSocket_JSONSubprotocolWithSnapshotAndUnsubscribe(t *testing.T) {
+	statusRepository := repository.NewInMemoryStatusRepository()
+	require.NoError(t, statusRepository.Save(t.Context(), &eventspb.AnalysisStatusEvent{
+		RequestId: "analysis-1",
+		Status:    "fetching",
+		Progress:  10,
+		Timestamp: timestamppb.Now(),
+	}))
+
+	server, broker := setupWebSocketTestServer(t, statusRepository)
+	conn := dialWebSocket(t, server, handlers.SubprotocolJSON)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage,
+		[]byte(`{"action":"ACTION_SUBSCRIBE","requestIds":["analysis-1","analysis-2"]}`)))
+
+	receive := func() *eventspb.AnalysisStatusEvent {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		messageType, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, websocket.TextMessage, messageType)
+
+		var event eventspb.AnalysisStatusEvent
+		require.NoError(t, protojson.Unmarshal(data, &event))
+		return &event
+	}
+
+	// Снимок текущего статуса приходит сразу после подписки
+	snapshot := receive()
+	assert.Equal(t, "analysis-1", snapshot.RequestId)
+	assert.Equal(t, "fetching", snapshot.Status)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage,
+		[]byte(`{"action":"ACTION_UNSUBSCRIBE","requestIds":["analysis-1"]}`)))
+
+	// После отписки события analysis-1 не доставляются, analysis-2 — доставляются
+	go func() {
+		for range 50 {
+			broker.Publish(t.Context(), &eventspb.AnalysisStatusEvent{RequestId: "analysis-1", Status: "resolving"})
+			time.Sleep(5 * time.Millisecond)
+		}
+		broker.Publish(t.Context(), &eventspb.AnalysisStatusEvent{RequestId: "analysis-2", Status: "completed"})
+	}()
+
+	for {
+		event := receive()
+		if event.RequestId == "analysis-2" {
+			assert.Equal(t, "completed", event.Status)
+			break
+		}
+		// До обработки команды отписки могли прийти события analysis-1
+		assert.Equal(t, "analysis-1", event.RequestId)
+	}
+}
+
+func TestWebSocket_InvalidCommandClosesConnection(t *testing.T) {
+	server, _ := setupWebSocketTestServer(t, repository.NewInMemoryStatusRepository())
+	conn := dialWebSocket(t, server, handlers.SubprotocolJSON)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"action":"ACTION_SUBSCRIBE"}`)))
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseUnsupportedData))
+}
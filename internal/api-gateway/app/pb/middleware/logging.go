@@ -65,7 +65,7 @@ func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		requestID := c.GetHeader("X-Request-ID")
 		if requestID == "" {
-			requestID = generateRequestID()
+			requestID = GenerateRequestID()
 		}
 
 		c.Header("X-Request-ID", requestID)
@@ -76,7 +76,8 @@ func RequestIDMiddleware() gin.HandlerFunc {
 	}
 }
 
-func generateRequestID() string {
+// GenerateRequestID создаёт идентификатор запроса, если клиент его не передал
+func GenerateRequestID() string {
 	bytes := make([]byte, 8)
 	rand.Read(bytes)
 	return fmt.Sprintf("%d-%s", time.Now().UnixNano(), hex.EncodeToString(bytes))
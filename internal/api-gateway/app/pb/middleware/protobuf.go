@@ -6,11 +6,20 @@ import (
 	"net/http"
 	"strings"
 
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
-// ProtobufMiddleware обрабатывает protobuf запросы
+const (
+	MIMEProtobuf    = "application/x-protobuf"
+	MIMEProtobufAlt = "application/protobuf"
+	MIMEJSON        = "application/json"
+)
+
+// ProtobufMiddleware обрабатывает protobuf и JSON запросы.
+// Тело protobuf сохраняется в protobuf_body, JSON (protojson) — в json_body.
 func ProtobufMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Проверяем только POST/PUT/PATCH запросы
@@ -23,27 +32,25 @@ func ProtobufMiddleware() gin.HandlerFunc {
 
 		contentType := c.GetHeader("Content-Type")
 
-		// Проверяем protobuf content-type
-		if strings.Contains(contentType, "application/x-protobuf") ||
-			strings.Contains(contentType, "application/protobuf") {
+		isProtobuf := isProtobufContentType(contentType)
+		isJSON := strings.Contains(contentType, MIMEJSON)
+
+		// Проверяем protobuf или JSON content-type
+		if isProtobuf || isJSON {
 
 			// Читаем тело запроса
 			body, err := io.ReadAll(c.Request.Body)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error": "Failed to read request body",
-					"code":  "INVALID_BODY",
-				})
+				SendProtobufError(c, http.StatusBadRequest,
+					pbapi.ErrorCode_ERROR_CODE_INVALID_BODY, "Failed to read request body")
 				c.Abort()
 				return
 			}
 
 			// Проверяем, что тело не пустое
 			if len(body) == 0 {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error": "Empty request body",
-					"code":  "EMPTY_BODY",
-				})
+				SendProtobufError(c, http.StatusBadRequest,
+					pbapi.ErrorCode_ERROR_CODE_EMPTY_BODY, "Empty request body")
 				c.Abort()
 				return
 			}
@@ -51,35 +58,94 @@ func ProtobufMiddleware() gin.HandlerFunc {
 			// Восстанавливаем тело для повторного чтения
 			c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
 
-			// Сохраняем protobuf данные в контекст
-			c.Set("protobuf_body", body)
-			c.Set("is_protobuf", true)
+			// Сохраняем данные в контекст
+			if isProtobuf {
+				c.Set("protobuf_body", body)
+				c.Set("is_protobuf", true)
+			} else {
+				c.Set("json_body", body)
+				c.Set("is_json", true)
+			}
 		}
 
 		c.Next()
 	}
 }
 
-// SendProtobufResponse отправляет protobuf ответ
+// SendProtobufResponse отправляет ответ в кодировке, выбранной по заголовку Accept
 func SendProtobufResponse(c *gin.Context, message proto.Message) {
-	data, err := proto.Marshal(message)
+	sendMessage(c, http.StatusOK, message)
+}
+
+// retryableErrors — ошибки инфраструктуры, после которых тот же запрос можно повторить
+var retryableErrors = map[pbapi.ErrorCode]bool{
+	pbapi.ErrorCode_ERROR_CODE_UUID_GENERATE_ERROR: true,
+	pbapi.ErrorCode_ERROR_CODE_KAFKA_PUBLISH_ERROR: true,
+	pbapi.ErrorCode_ERROR_CODE_STATUS_STORE_ERROR:  true,
+}
+
+// SendProtobufError отправляет ErrorResponse в согласованной кодировке
+func SendProtobufError(
+	c *gin.Context,
+	statusCode int,
+	code pbapi.ErrorCode,
+	message string,
+	violations ...*pbapi.ErrorResponse_FieldViolation,
+) {
+	sendMessage(c, statusCode, &pbapi.ErrorResponse{
+		Code:       code,
+		Message:    message,
+		Violations: violations,
+		RequestId:  c.GetString("request_id"),
+		Retryable:  retryableErrors[code],
+	})
+}
+
+func sendMessage(c *gin.Context, statusCode int, message proto.Message) {
+	var (
+		data        []byte
+		err         error
+		contentType string
+	)
+
+	switch negotiateEncoding(c) {
+	case MIMEJSON:
+		contentType = MIMEJSON
+		data, err = protojson.Marshal(message)
+	default:
+		contentType = MIMEProtobuf
+		data, err = proto.Marshal(message)
+	}
+
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to serialize response",
-			"code":  "SERIALIZATION_ERROR",
-		})
+		c.String(http.StatusInternalServerError, "Failed to serialize response")
 		return
 	}
 
-	c.Header("Content-Type", "application/x-protobuf")
-	c.Data(http.StatusOK, "application/x-protobuf", data)
+	c.Header("Content-Type", contentType)
+	c.Data(statusCode, contentType, data)
 }
 
-// SendProtobufError отправляет protobuf ошибку
-func SendProtobufError(c *gin.Context, statusCode int, message string, code string) {
-	c.Header("Content-Type", "application/json")
-	c.JSON(statusCode, gin.H{
-		"error": message,
-		"code":  code,
-	})
+// negotiateEncoding выбирает MIMEProtobuf или MIMEJSON по заголовку Accept.
+// Без Accept (или с */*) protobuf запросы получают protobuf, остальные — JSON.
+func negotiateEncoding(c *gin.Context) string {
+	offers := []string{MIMEJSON, MIMEProtobuf, MIMEProtobufAlt}
+	if c.GetBool("is_protobuf") || isProtobufContentType(c.GetHeader("Content-Type")) || c.GetHeader("Content-Type") == "" {
+		offers = []string{MIMEProtobuf, MIMEProtobufAlt, MIMEJSON}
+	}
+
+	switch c.NegotiateFormat(offers...) {
+	case MIMEJSON:
+		return MIMEJSON
+	case MIMEProtobuf, MIMEProtobufAlt:
+		return MIMEProtobuf
+	default:
+		// Неизвестный Accept: отвечаем в кодировке по умолчанию
+		return offers[0]
+	}
+}
+
+func isProtobufContentType(contentType string) bool {
+	return strings.Contains(contentType, MIMEProtobuf) ||
+		strings.Contains(contentType, MIMEProtobufAlt)
 }
@@ -10,6 +10,10 @@ import (
 
 func SetupRoutes(
 	analysisHandler *handlers.AnalysisHandler,
+	uploadHandler *handlers.UploadHandler,
+	statusHandler *handlers.StatusHandler,
+	eventsHandler *handlers.EventsHandler,
+	webSocketHandler *handlers.WebSocketHandler,
 	healthHandler *handlers.HealthHandler,
 	cfg *config.Config,
 	logger *logger.Logger,
@@ -27,17 +31,30 @@ func SetupRoutes(
 
 	v1 := router.Group("/api/v1")
 	{
-		setupAnalysisRoutes(v1, analysisHandler)
+		setupAnalysisRoutes(v1, analysisHandler, uploadHandler, statusHandler, eventsHandler, webSocketHandler)
 	}
 
 	return router
 }
 
-func setupAnalysisRoutes(group *gin.RouterGroup, analysisHandler *handlers.AnalysisHandler) {
+func setupAnalysisRoutes(
+	group *gin.RouterGroup,
+	analysisHandler *handlers.AnalysisHandler,
+	uploadHandler *handlers.UploadHandler,
+	statusHandler *handlers.StatusHandler,
+	eventsHandler *handlers.EventsHandler,
+	webSocketHandler *handlers.WebSocketHandler,
+) {
 	analysis := group.Group("/analysis")
 	{
 		analysis.POST("/start", analysisHandler.StartAnalysis)
 		analysis.POST("", analysisHandler.StartAnalysis)
+		analysis.POST("/requirements", uploadHandler.UploadRequirements)
+		analysis.POST("/pyproject", uploadHandler.UploadPyproject)
+		analysis.POST("/lockfile", uploadHandler.UploadLockfile)
+		analysis.GET("/:id/status", statusHandler.GetStatus)
+		analysis.GET("/:id/events", eventsHandler.StreamEvents)
+		analysis.GET("/subscribe", webSocketHandler.Subscribe)
 	}
 
 	group.POST("/analyze", analysisHandler.StartAnalysis)
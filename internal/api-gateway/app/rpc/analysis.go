@@ -0,0 +1,172 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/service"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/analysis"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AnalysisServer реализует gRPC AnalysisService поверх тех же сервисов, что и HTTP обработчики
+type AnalysisServer struct {
+	pbapi.UnimplementedAnalysisServiceServer
+
+	analysisService   *service.AnalysisService
+	statusRepository  repository.StatusRepository
+	historyRepository repository.StatusHistoryRepository
+	broker            *service.StatusBroker
+	logger            logger.LoggerInterface
+}
+
+// interface check
+var _ pbapi.AnalysisServiceServer = (*AnalysisServer)(nil)
+
+func NewAnalysisServer(
+	analysisService *service.AnalysisService,
+	statusRepository repository.StatusRepository,
+	historyRepository repository.StatusHistoryRepository,
+	broker *service.StatusBroker,
+	logger logger.LoggerInterface,
+) *AnalysisServer {
+	return &AnalysisServer{
+		analysisService:   analysisService,
+		statusRepository:  statusRepository,
+		historyRepository: historyRepository,
+		broker:            broker,
+		logger:            logger,
+	}
+}
+
+func (s *AnalysisServer) StartAnalysis(ctx context.Context, request *pbapi.AnalyzeRequest) (*pbapi.AnalyzeResponse, error) {
+	response, err := s.analysisService.StartAnalysis(ctx, RequestIDFromContext(ctx), request)
+	if err != nil {
+		var validationErr *service.ValidationError
+
+		switch {
+		case errors.As(err, &validationErr):
+			return nil, validationStatus(validationErr)
+		case errors.Is(err, service.ErrPublishFailed):
+			return nil, status.Error(codes.Unavailable, "failed to publish event")
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return response, nil
+}
+
+func (s *AnalysisServer) GetStatus(ctx context.Context, request *pbapi.StatusRequest) (*pbapi.StatusResponse, error) {
+	if request.RequestId == "" {
+		return nil, status.Error(codes.InvalidArgument, "request_id is required")
+	}
+
+	event, err := s.statusRepository.Get(ctx, request.RequestId)
+	if err != nil {
+		return nil, s.statusError(ctx, request.RequestId, err)
+	}
+
+	return statusResponseFromEvent(event), nil
+}
+
+// WatchStatus отдаёт накопленную историю и живые события до терминального статуса.
+// Для неизвестного анализа возвращает NotFound, как GetStatus
+func (s *AnalysisServer) WatchStatus(request *pbapi.StatusRequest, stream grpc.ServerStreamingServer[pbapi.StatusResponse]) error {
+	ctx := stream.Context()
+
+	if request.RequestId == "" {
+		return status.Error(codes.InvalidArgument, "request_id is required")
+	}
+
+	watch, err := service.OpenStatusStream(ctx, s.broker, s.statusRepository, s.historyRepository, request.RequestId, 0)
+	if err != nil {
+		return s.statusError(ctx, request.RequestId, err)
+	}
+	defer watch.Close()
+
+	for _, event := range watch.History {
+		if done, err := sendStatus(stream, watch, event); done || err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case event, ok := <-watch.Events():
+			if !ok {
+				return status.Error(codes.Unavailable, "subscriber is too slow, retry the call")
+			}
+			if !watch.IsNew(event) {
+				continue
+			}
+			if done, err := sendStatus(stream, watch, event); done || err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendStatus отправляет событие и сообщает, достигнут ли терминальный статус
+func sendStatus(
+	stream grpc.ServerStreamingServer[pbapi.StatusResponse],
+	watch *service.StatusStream,
+	event *eventspb.AnalysisStatusEvent,
+) (bool, error) {
+	if err := stream.Send(statusResponseFromEvent(event)); err != nil {
+		return true, err
+	}
+	watch.MarkSent(event)
+	return analysis.IsTerminalStatus(event.Status), nil
+}
+
+func (s *AnalysisServer) statusError(ctx context.Context, analysisID string, err error) error {
+	if errors.Is(err, repository.ErrStatusNotFound) {
+		return status.Error(codes.NotFound, "analysis not found")
+	}
+
+	s.logger.WithRequestID(RequestIDFromContext(ctx)).Error("Failed to load analysis status",
+		zap.String("analysis_id", analysisID),
+		zap.Error(err),
+	)
+	return status.Error(codes.Internal, "failed to load analysis status")
+}
+
+// validationStatus передаёт все нарушения в стандартной детали BadRequest
+func validationStatus(validationErr *service.ValidationError) error {
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range validationErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Description,
+		})
+	}
+
+	st, err := status.New(codes.InvalidArgument, validationErr.Error()).WithDetails(badRequest)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, validationErr.Error())
+	}
+	return st.Err()
+}
+
+func statusResponseFromEvent(event *eventspb.AnalysisStatusEvent) *pbapi.StatusResponse {
+	return &pbapi.StatusResponse{
+		RequestId:   event.RequestId,
+		Status:      event.Status,
+		Message:     event.Message,
+		Progress:    event.Progress,
+		ServiceName: event.ServiceName,
+		UpdatedAt:   event.Timestamp,
+		Explanation: event.Explanation,
+	}
+}
@@ -0,0 +1,256 @@
+package rpc_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/rpc"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/service"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/mocks"
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type testEnv struct {
+	client            pbapi.AnalysisServiceClient
+	conn              *grpc.ClientConn
+	producer          *mocks.MockKafkaProducer
+	statusRepository  *repository.InMemoryStatusRepository
+	historyRepository *repository.InMemoryStatusHistoryRepository
+	broker            *service.StatusBroker
+}
+
+func setupTestServer(t *testing.T) *testEnv {
+	log := &logger.Logger{Logger: zap.NewNop()}
+
+	env := &testEnv{
+		producer:          mocks.NewMockKafkaProducer(),
+		statusRepository:  repository.NewInMemoryStatusRepository(),
+		historyRepository: repository.NewInMemoryStatusHistoryRepository(),
+		broker:            service.NewStatusBroker(),
+	}
+
+	analysisServer := rpc.NewAnalysisServer(
+		service.NewAnalysisService(
+			env.producer,
+			service.NewStatusProjector(nil, env.statusRepository, env.historyRepository, log),
+			log,
+		),
+		env.statusRepository,
+		env.historyRepository,
+		env.broker,
+		log,
+	)
+	server, _ := rpc.NewServer(analysisServer, log)
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	env.conn = conn
+	env.client = pbapi.NewAnalysisServiceClient(conn)
+	return env
+}
+
+func TestStartAnalysis_PublishesEvent(t *testing.T) {
+	env := setupTestServer(t)
+	env.producer.On("PublishEvent", mock.Anything, mock.AnythingOfType("*kafka_message.AnalysisStartedEvent")).Return(nil)
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(t.Context(), "x-request-id", "grpc-request-1")
+	response, err := env.client.StartAnalysis(ctx, &pbapi.AnalyzeRequest{
+		UserId:        "user123",
+		PythonVersion: "3.11",
+		Packages: []*pbapi.AnalyzeRequest_RequiredPackage{
+			{PackageName: "requests", PackageVersion: "2.31.0"},
+		},
+	}, grpc.Header(&header))
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.RequestId)
+	assert.Equal(t, "pending", response.Status)
+	assert.Equal(t, []string{"grpc-request-1"}, header.Get("x-request-id"))
+	env.producer.AssertExpectations(t)
+
+	// Принятый анализ сразу виден в статусе, не дожидаясь событий резолвера
+	pending, err := env.client.GetStatus(t.Context(), &pbapi.StatusRequest{RequestId: response.RequestId})
+	require.NoError(t, err)
+	assert.Equal(t, "pending", pending.Status)
+	assert.Equal(t, "api-gateway", pending.ServiceName)
+
+	history, err := env.historyRepository.ListSince(t.Context(), response.RequestId, 0)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+func TestStartAnalysis_ErrorCodes(t *testing.T) {
+	env := setupTestServer(t)
+
+	_, err := env.client.StartAnalysis(t.Context(), &pbapi.AnalyzeRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	badRequest, ok := details[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	assert.Len(t, badRequest.FieldViolations, 3)
+
+	env.producer.On("PublishEvent", mock.Anything, mock.Anything).Return(assert.AnError)
+	_, err = env.client.StartAnalysis(t.Context(), &pbapi.AnalyzeRequest{
+		UserId:        "user123",
+		PythonVersion: "3.11",
+		Packages:      []*pbapi.AnalyzeRequest_RequiredPackage{{PackageName: "requests"}},
+	})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestGetStatus(t *testing.T) {
+	env := setupTestServer(t)
+
+	_, err := env.client.GetStatus(t.Context(), &pbapi.StatusRequest{RequestId: "analysis-1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	require.NoError(t, env.statusRepository.Save(t.Context(), &eventspb.AnalysisStatusEvent{
+		RequestId:   "analysis-1",
+		Status:      "resolving",
+		Progress:    50,
+		ServiceName: "dependency-resolver",
+		Timestamp:   timestamppb.Now(),
+	}))
+
+	response, err := env.client.GetStatus(t.Context(), &pbapi.StatusRequest{RequestId: "analysis-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "resolving", response.Status)
+	assert.Equal(t, int64(50), response.Progress)
+	assert.Equal(t, "dependency-resolver", response.ServiceName)
+}
+
+func TestWatchStatus_HistoryThenLiveUntilTerminal(t *testing.T) {
+	env := setupTestServer(t)
+	base := time.Now()
+
+	require.NoError(t, env.historyRepository.Append(t.Context(), &eventspb.AnalysisStatusEvent{
+		RequestId: "analysis-1",
+		Status:    "resolving",
+		Progress:  40,
+		Timestamp: timestamppb.New(base),
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	stream, err := env.client.WatchStatus(ctx, &pbapi.StatusRequest{RequestId: "analysis-1"})
+	require.NoError(t, err)
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "resolving", first.Status)
+
+	// История получена, значит подписка уже оформлена
+	env.broker.Publish(ctx, &eventspb.AnalysisStatusEvent{
+		RequestId: "analysis-1",
+		Status:    "completed",
+		Progress:  100,
+		Timestamp: timestamppb.New(base.Add(time.Second)),
+	})
+
+	second, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "completed", second.Status)
+
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestWatchStatus_KeepsEventsWithSameTimestamp(t *testing.T) {
+	env := setupTestServer(t)
+	at := timestamppb.Now()
+
+	require.NoError(t, env.historyRepository.Append(t.Context(), &eventspb.AnalysisStatusEvent{
+		RequestId: "analysis-1",
+		Status:    "resolving",
+		Progress:  40,
+		Timestamp: at,
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	stream, err := env.client.WatchStatus(ctx, &pbapi.StatusRequest{RequestId: "analysis-1"})
+	require.NoError(t, err)
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), first.Progress)
+
+	// Повтор события из истории отбрасывается, события с тем же временем — нет
+	for _, event := range []*eventspb.AnalysisStatusEvent{
+		{RequestId: "analysis-1", Status: "resolving", Progress: 40, Timestamp: at},
+		{RequestId: "analysis-1", Status: "resolving", Progress: 60, Timestamp: at},
+		{RequestId: "analysis-1", Status: "completed", Progress: 100, Timestamp: at},
+	} {
+		env.broker.Publish(ctx, event)
+	}
+
+	second, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(60), second.Progress)
+
+	third, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "completed", third.Status)
+
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestWatchStatus_UnknownAnalysis(t *testing.T) {
+	env := setupTestServer(t)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	stream, err := env.client.WatchStatus(ctx, &pbapi.StatusRequest{RequestId: "unknown"})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	require.NoError(t, ctx.Err())
+}
+
+func TestHealthCheck(t *testing.T) {
+	env := setupTestServer(t)
+
+	response, err := healthpb.NewHealthClient(env.conn).Check(t.Context(), &healthpb.HealthCheckRequest{
+		Service: pbapi.AnalysisService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+}
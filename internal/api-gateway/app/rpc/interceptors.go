@@ -0,0 +1,113 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/app/pb/middleware"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const requestIDMetadataKey = "x-request-id"
+
+type requestIDKey struct{}
+
+// RequestIDFromContext возвращает идентификатор запроса, выставленный интерсептором
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// withRequestID берёт x-request-id из метаданных или генерирует новый и возвращает его в заголовке ответа
+func withRequestID(ctx context.Context) context.Context {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDMetadataKey); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	if requestID == "" {
+		requestID = middleware.GenerateRequestID()
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, requestID))
+
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func UnaryServerInterceptor(logger logger.LoggerInterface) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		ctx = withRequestID(ctx)
+		start := time.Now()
+
+		defer func() {
+			if rec := recover(); rec != nil {
+				logger.Error("Panic recovered",
+					zap.Any("error", rec),
+					zap.String("method", info.FullMethod),
+				)
+				err = status.Error(codes.Internal, "internal error")
+			}
+			logRPC(logger, RequestIDFromContext(ctx), info.FullMethod, start, err)
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerInterceptor(logger logger.LoggerInterface) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx := withRequestID(stream.Context())
+		start := time.Now()
+
+		defer func() {
+			if rec := recover(); rec != nil {
+				logger.Error("Panic recovered",
+					zap.Any("error", rec),
+					zap.String("method", info.FullMethod),
+				)
+				err = status.Error(codes.Internal, "internal error")
+			}
+			logRPC(logger, RequestIDFromContext(ctx), info.FullMethod, start, err)
+		}()
+
+		return handler(srv, &contextServerStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func logRPC(logger logger.LoggerInterface, requestID string, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("request_id", requestID),
+		zap.String("method", method),
+		zap.String("code", code.String()),
+		zap.Duration("latency", time.Since(start)),
+	}
+
+	if err != nil {
+		fields = append(fields, zap.String("error", err.Error()))
+	}
+
+	switch code {
+	case codes.OK, codes.Canceled:
+		logger.Info("gRPC Request", fields...)
+	case codes.Internal, codes.Unavailable, codes.Unknown, codes.DataLoss:
+		logger.Error("gRPC Request", fields...)
+	default:
+		logger.Warn("gRPC Request", fields...)
+	}
+}
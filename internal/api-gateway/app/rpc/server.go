@@ -0,0 +1,29 @@
+package rpc
+
+import (
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer собирает gRPC сервер с AnalysisService, health checking и reflection (для grpcurl)
+func NewServer(analysisServer *AnalysisServer, logger logger.LoggerInterface) (*grpc.Server, *health.Server) {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(logger)),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(logger)),
+	)
+
+	pbapi.RegisterAnalysisServiceServer(server, analysisServer)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(pbapi.AnalysisService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return server, healthServer
+}
@@ -0,0 +1,61 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/kafka"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// StatusEventHandler обрабатывает десериализованное событие статуса
+type StatusEventHandler func(ctx context.Context, event *eventspb.AnalysisStatusEvent) error
+
+type StatusConsumer struct {
+	consumer kafka.Consumer
+	topic    string
+}
+
+func NewStatusConsumer(brokers []string, groupID string, topic string, initialOffset string) (*StatusConsumer, error) {
+	baseConsumer, err := kafka.NewBaseConsumer(&kafka.ConsumerConfig{
+		Brokers:       brokers,
+		GroupID:       groupID,
+		AutoCommit:    true,
+		InitialOffset: kafka.ParseInitialOffset(initialOffset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatusConsumer{
+		consumer: baseConsumer,
+		topic:    topic,
+	}, nil
+}
+
+// Run блокируется до отмены контекста, передавая каждое AnalysisStatusEvent в handler
+func (c *StatusConsumer) Run(ctx context.Context, handler StatusEventHandler) error {
+	return c.consumer.Subscribe(ctx, []string{c.topic}, func(ctx context.Context, message *kafka.Message) error {
+		var event eventspb.AnalysisStatusEvent
+		if err := proto.Unmarshal(message.Value, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal status event: %w", err)
+		}
+
+		if event.RequestId == "" {
+			event.RequestId = message.Key
+		}
+
+		// Время записи Kafka одинаково для всех консьюмеров, поэтому годится как запасное время события
+		if event.Timestamp == nil && !message.Timestamp.IsZero() {
+			event.Timestamp = timestamppb.New(message.Timestamp)
+		}
+
+		return handler(ctx, &event)
+	})
+}
+
+func (c *StatusConsumer) Close() error {
+	return c.consumer.Close()
+}
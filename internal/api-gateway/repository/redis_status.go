@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const statusKeyPrefix = "analysis:status:"
+
+// saveStatusScript атомарно применяет обновление, только если оно не старше сохранённого.
+// Время хранится в микросекундах, чтобы сравнение в Lua не теряло точность.
+var saveStatusScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'timestamp')
+if current and tonumber(current) > tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('HSET', KEYS[1],
+	'timestamp', ARGV[1],
+	'request_id', ARGV[2],
+	'status', ARGV[3],
+	'message', ARGV[4],
+	'progress', ARGV[5],
+	'service_name', ARGV[6],
+	'explanation', ARGV[7])
+redis.call('PEXPIRE', KEYS[1], ARGV[8])
+return 1
+`)
+
+type RedisStatusRepository struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// interface check
+var _ StatusRepository = (*RedisStatusRepository)(nil)
+
+func NewRedisStatusRepository(client *redis.Client, ttl time.Duration) *RedisStatusRepository {
+	return &RedisStatusRepository{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func (r *RedisStatusRepository) Save(ctx context.Context, event *eventspb.AnalysisStatusEvent) error {
+	timestamp := time.Now()
+	if event.Timestamp != nil {
+		timestamp = event.Timestamp.AsTime()
+	}
+
+	// Строки объяснения могут сами содержать переводы строк, поэтому список хранится как JSON
+	var explanation []byte
+	if len(event.Explanation) > 0 {
+		var err error
+		if explanation, err = json.Marshal(event.Explanation); err != nil {
+			return fmt.Errorf("failed to encode explanation for %s: %w", event.RequestId, err)
+		}
+	}
+
+	applied, err := saveStatusScript.Run(ctx, r.client,
+		[]string{statusKey(event.RequestId)},
+		timestamp.UnixMicro(),
+		event.RequestId,
+		event.Status,
+		event.Message,
+		event.Progress,
+		event.ServiceName,
+		explanation,
+		r.ttl.Milliseconds(),
+	).Int()
+	if err != nil {
+		return fmt.Errorf("failed to save status for %s: %w", event.RequestId, err)
+	}
+
+	if applied == 0 {
+		return ErrStaleStatus
+	}
+
+	return nil
+}
+
+func (r *RedisStatusRepository) Get(ctx context.Context, requestID string) (*eventspb.AnalysisStatusEvent, error) {
+	fields, err := r.client.HGetAll(ctx, statusKey(requestID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load status for %s: %w", requestID, err)
+	}
+
+	if len(fields) == 0 {
+		return nil, ErrStatusNotFound
+	}
+
+	progress, err := strconv.ParseInt(fields["progress"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid progress stored for %s: %w", requestID, err)
+	}
+
+	micros, err := strconv.ParseInt(fields["timestamp"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp stored for %s: %w", requestID, err)
+	}
+
+	var explanation []string
+	if fields["explanation"] != "" {
+		if err := json.Unmarshal([]byte(fields["explanation"]), &explanation); err != nil {
+			return nil, fmt.Errorf("invalid explanation stored for %s: %w", requestID, err)
+		}
+	}
+
+	return &eventspb.AnalysisStatusEvent{
+		RequestId:   fields["request_id"],
+		Status:      fields["status"],
+		Message:     fields["message"],
+		Progress:    progress,
+		ServiceName: fields["service_name"],
+		Timestamp:   timestamppb.New(time.UnixMicro(micros)),
+		Explanation: explanation,
+	}, nil
+}
+
+func statusKey(requestID string) string {
+	return statusKeyPrefix + requestID
+}
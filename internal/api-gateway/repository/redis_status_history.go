@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+const statusHistoryKeyPrefix = "analysis:events:"
+
+// RedisStatusHistoryRepository хранит историю в sorted set, где score — StatusEventID
+type RedisStatusHistoryRepository struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// interface check
+var _ StatusHistoryRepository = (*RedisStatusHistoryRepository)(nil)
+
+func NewRedisStatusHistoryRepository(client *redis.Client, ttl time.Duration) *RedisStatusHistoryRepository {
+	return &RedisStatusHistoryRepository{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func (r *RedisStatusHistoryRepository) Append(ctx context.Context, event *eventspb.AnalysisStatusEvent) error {
+	data, err := proto.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal status event: %w", err)
+	}
+
+	key := statusHistoryKey(event.RequestId)
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{
+			Score:  float64(StatusEventID(event)),
+			Member: data,
+		})
+		pipe.PExpire(ctx, key, r.ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to append status event for %s: %w", event.RequestId, err)
+	}
+
+	return nil
+}
+
+func (r *RedisStatusHistoryRepository) ListSince(ctx context.Context, requestID string, afterID int64) ([]*eventspb.AnalysisStatusEvent, error) {
+	members, err := r.client.ZRangeByScore(ctx, statusHistoryKey(requestID), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(afterID, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load status history for %s: %w", requestID, err)
+	}
+
+	events := make([]*eventspb.AnalysisStatusEvent, 0, len(members))
+	for _, member := range members {
+		var event eventspb.AnalysisStatusEvent
+		if err := proto.Unmarshal([]byte(member), &event); err != nil {
+			return nil, fmt.Errorf("invalid status event stored for %s: %w", requestID, err)
+		}
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
+
+func statusHistoryKey(requestID string) string {
+	return statusHistoryKeyPrefix + requestID
+}
@@ -0,0 +1,106 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func setupRedisStatusRepository(t *testing.T, ttl time.Duration) (*repository.RedisStatusRepository, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return repository.NewRedisStatusRepository(client, ttl), server
+}
+
+func statusEvent(status string, progress int64, at time.Time) *eventspb.AnalysisStatusEvent {
+	return &eventspb.AnalysisStatusEvent{
+		RequestId:   "analysis-1",
+		Status:      status,
+		Message:     status + " message",
+		Progress:    progress,
+		ServiceName: "dependency-resolver",
+		Timestamp:   timestamppb.New(at),
+	}
+}
+
+func TestRedisStatusRepository_SaveAndGet(t *testing.T) {
+	repo, server := setupRedisStatusRepository(t, time.Hour)
+	at := time.Date(2025, 1, 1, 12, 0, 0, 123456000, time.UTC)
+
+	require.NoError(t, repo.Save(t.Context(), statusEvent("resolving", 40, at)))
+
+	got, err := repo.Get(t.Context(), "analysis-1")
+	require.NoError(t, err)
+	assert.Equal(t, "analysis-1", got.RequestId)
+	assert.Equal(t, "resolving", got.Status)
+	assert.Equal(t, "resolving message", got.Message)
+	assert.Equal(t, int64(40), got.Progress)
+	assert.Equal(t, "dependency-resolver", got.ServiceName)
+	assert.True(t, at.Equal(got.Timestamp.AsTime()))
+
+	assert.Equal(t, time.Hour, server.TTL("analysis:status:analysis-1"))
+}
+
+func TestRedisStatusRepository_IgnoresOutOfOrderUpdates(t *testing.T) {
+	repo, _ := setupRedisStatusRepository(t, time.Hour)
+	at := time.Now()
+
+	require.NoError(t, repo.Save(t.Context(), statusEvent("completed", 100, at)))
+
+	err := repo.Save(t.Context(), statusEvent("resolving", 40, at.Add(-time.Second)))
+	assert.ErrorIs(t, err, repository.ErrStaleStatus)
+
+	got, err := repo.Get(t.Context(), "analysis-1")
+	require.NoError(t, err)
+	assert.Equal(t, "completed", got.Status)
+	assert.Equal(t, int64(100), got.Progress)
+}
+
+func TestRedisStatusRepository_Expires(t *testing.T) {
+	repo, server := setupRedisStatusRepository(t, time.Minute)
+
+	require.NoError(t, repo.Save(t.Context(), statusEvent("resolving", 40, time.Now())))
+	server.FastForward(2 * time.Minute)
+
+	_, err := repo.Get(t.Context(), "analysis-1")
+	assert.ErrorIs(t, err, repository.ErrStatusNotFound)
+}
+
+func TestRedisStatusRepository_NotFound(t *testing.T) {
+	repo, _ := setupRedisStatusRepository(t, time.Hour)
+
+	_, err := repo.Get(t.Context(), "unknown")
+	assert.ErrorIs(t, err, repository.ErrStatusNotFound)
+}
+
+func TestRedisStatusRepository_StoresExplanation(t *testing.T) {
+	repo, _ := setupRedisStatusRepository(t, time.Hour)
+	at := time.Now()
+
+	failed := statusEvent("failed", 0, at)
+	failed.Explanation = []string{
+		"Because a 2.0 depends on b<3 and c 1.4 depends on b>=3, a 2.0 and c 1.4 are incompatible. (1)",
+		"",
+		"And because the request requires a==2.0, the requirements are unsatisfiable.",
+		"Because metadata of d 1.0 lists\nRequires-Dist: e>=2, d 1.0 cannot be used.",
+	}
+	require.NoError(t, repo.Save(t.Context(), failed))
+
+	got, err := repo.Get(t.Context(), "analysis-1")
+	require.NoError(t, err)
+	assert.Equal(t, failed.Explanation, got.Explanation)
+
+	require.NoError(t, repo.Save(t.Context(), statusEvent("running", 0, at.Add(time.Second))))
+	got, err = repo.Get(t.Context(), "analysis-1")
+	require.NoError(t, err)
+	assert.Empty(t, got.Explanation)
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrStatusNotFound = errors.New("analysis status not found")
+	// ErrStaleStatus возвращается из Save, если уже сохранено более новое обновление
+	ErrStaleStatus = errors.New("analysis status update is older than stored one")
+)
+
+// StatusRepository хранит последний известный статус анализа
+type StatusRepository interface {
+	Save(ctx context.Context, event *eventspb.AnalysisStatusEvent) error
+	Get(ctx context.Context, requestID string) (*eventspb.AnalysisStatusEvent, error)
+}
+
+type InMemoryStatusRepository struct {
+	mu       sync.RWMutex
+	statuses map[string]*eventspb.AnalysisStatusEvent
+}
+
+// interface check
+var _ StatusRepository = (*InMemoryStatusRepository)(nil)
+
+func NewInMemoryStatusRepository() *InMemoryStatusRepository {
+	return &InMemoryStatusRepository{
+		statuses: make(map[string]*eventspb.AnalysisStatusEvent),
+	}
+}
+
+func (r *InMemoryStatusRepository) Save(ctx context.Context, event *eventspb.AnalysisStatusEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if current, ok := r.statuses[event.RequestId]; ok && isOlder(event, current) {
+		return ErrStaleStatus
+	}
+
+	r.statuses[event.RequestId] = proto.Clone(event).(*eventspb.AnalysisStatusEvent)
+	return nil
+}
+
+func (r *InMemoryStatusRepository) Get(ctx context.Context, requestID string) (*eventspb.AnalysisStatusEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	event, ok := r.statuses[requestID]
+	if !ok {
+		return nil, ErrStatusNotFound
+	}
+
+	return proto.Clone(event).(*eventspb.AnalysisStatusEvent), nil
+}
+
+// isOlder сообщает, что event выпущен раньше current. События без времени считаются свежими.
+func isOlder(event, current *eventspb.AnalysisStatusEvent) bool {
+	if event.Timestamp == nil || current.Timestamp == nil {
+		return false
+	}
+	return event.Timestamp.AsTime().Before(current.Timestamp.AsTime())
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"google.golang.org/protobuf/proto"
+)
+
+// StatusHistoryRepository хранит все события статуса анализа, упорядоченные по StatusEventID
+type StatusHistoryRepository interface {
+	Append(ctx context.Context, event *eventspb.AnalysisStatusEvent) error
+	// ListSince возвращает события с идентификатором строго больше afterID
+	ListSince(ctx context.Context, requestID string, afterID int64) ([]*eventspb.AnalysisStatusEvent, error)
+}
+
+// StatusEventID возвращает монотонный идентификатор события в рамках одного анализа.
+// Используется как id SSE события и для возобновления по Last-Event-ID.
+func StatusEventID(event *eventspb.AnalysisStatusEvent) int64 {
+	if event.Timestamp == nil {
+		return 0
+	}
+	return event.Timestamp.AsTime().UnixMicro()
+}
+
+type InMemoryStatusHistoryRepository struct {
+	mu     sync.RWMutex
+	events map[string][]*eventspb.AnalysisStatusEvent
+}
+
+// interface check
+var _ StatusHistoryRepository = (*InMemoryStatusHistoryRepository)(nil)
+
+func NewInMemoryStatusHistoryRepository() *InMemoryStatusHistoryRepository {
+	return &InMemoryStatusHistoryRepository{
+		events: make(map[string][]*eventspb.AnalysisStatusEvent),
+	}
+}
+
+func (r *InMemoryStatusHistoryRepository) Append(ctx context.Context, event *eventspb.AnalysisStatusEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := r.events[event.RequestId]
+	id := StatusEventID(event)
+	i := sort.Search(len(events), func(i int) bool {
+		return StatusEventID(events[i]) > id
+	})
+
+	events = append(events, nil)
+	copy(events[i+1:], events[i:])
+	events[i] = proto.Clone(event).(*eventspb.AnalysisStatusEvent)
+	r.events[event.RequestId] = events
+
+	return nil
+}
+
+func (r *InMemoryStatusHistoryRepository) ListSince(ctx context.Context, requestID string, afterID int64) ([]*eventspb.AnalysisStatusEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*eventspb.AnalysisStatusEvent
+	for _, event := range r.events[requestID] {
+		if StatusEventID(event) > afterID {
+			result = append(result, proto.Clone(event).(*eventspb.AnalysisStatusEvent))
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,476 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/kafka"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/analysis"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep425"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep508"
+	pbapi "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/hashicorp/go-uuid"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	publishTimeout = 10 * time.Second
+	serviceName    = "api-gateway"
+)
+
+// maxUniversalEnvironments ограничивает универсальное разрешение: каждое окружение разрешается отдельно
+const maxUniversalEnvironments = 32
+
+// defaultUniversalPlatforms — платформы универсального разрешения, если запрос их не перечислил
+var defaultUniversalPlatforms = []*pbapi.TargetPlatform{
+	{Os: pep425.OSLinux, Arch: "x86_64"},
+	{Os: pep425.OSMacOS, Arch: "arm64"},
+	{Os: pep425.OSWindows, Arch: "x86_64"},
+}
+
+var (
+	ErrValidation    = errors.New("validation failed")
+	ErrIDGeneration  = errors.New("failed to generate analysis id")
+	ErrPublishFailed = errors.New("failed to publish event")
+)
+
+// ValidationError содержит все нарушения запроса сразу, а не только первое
+type ValidationError struct {
+	Violations []*pbapi.ErrorResponse_FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = fmt.Sprintf("%s: %s", violation.Field, violation.Description)
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func (e *ValidationError) add(field string, format string, args ...any) {
+	e.Violations = append(e.Violations, &pbapi.ErrorResponse_FieldViolation{
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+// StatusRecorder записывает событие статуса в хранилище статусов и историю, как StatusProjector.Apply
+type StatusRecorder interface {
+	Apply(ctx context.Context, event *eventspb.AnalysisStatusEvent) error
+}
+
+// AnalysisService содержит логику запуска анализа, общую для HTTP и gRPC
+type AnalysisService struct {
+	kafkaProducer  kafka.Producer
+	statusRecorder StatusRecorder
+	logger         logger.LoggerInterface
+}
+
+// NewAnalysisService создаёт сервис; без statusRecorder статус pending не записывается
+func NewAnalysisService(kafkaProducer kafka.Producer, statusRecorder StatusRecorder, logger logger.LoggerInterface) *AnalysisService {
+	return &AnalysisService{
+		kafkaProducer:  kafkaProducer,
+		statusRecorder: statusRecorder,
+		logger:         logger,
+	}
+}
+
+// StartAnalysis валидирует запрос, публикует AnalysisStartedEvent и записывает статус pending,
+// чтобы статус, SSE и WebSocket сразу находили принятый анализ.
+// Ошибки оборачивают ErrValidation, ErrIDGeneration или ErrPublishFailed.
+func (s *AnalysisService) StartAnalysis(
+	ctx context.Context,
+	requestID string,
+	request *pbapi.AnalyzeRequest,
+) (*pbapi.AnalyzeResponse, error) {
+	contextLogger := s.logger.WithRequestID(requestID)
+
+	if err := s.validateRequest(request); err != nil {
+		contextLogger.Warn("Request validation failed", zap.Error(err))
+		return nil, err
+	}
+
+	analysisID, err := uuid.GenerateUUID()
+	if err != nil {
+		contextLogger.Warn("UUID generate failed", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", ErrIDGeneration, err)
+	}
+
+	contextLogger.Info("Analysis request validated",
+		zap.String("analysis_id", analysisID),
+		zap.String("user_id", request.UserId),
+		zap.String("python_version", request.PythonVersion),
+		zap.Int("packages_count", len(request.Packages)),
+	)
+
+	event := &eventspb.AnalysisStartedEvent{
+		RequestId:      analysisID,
+		UserId:         request.UserId,
+		PythonVersion:  request.PythonVersion,
+		RepositoryUrl:  request.RepositoryUrl,
+		Packages:       s.convertPackages(request.Packages),
+		Timestamp:      timestamppb.Now(),
+		Locked:         request.Locked,
+		TargetPlatform: s.convertPlatform(request),
+		PinnedVersions: s.convertPinnedVersions(request.PinnedVersions),
+		Universal:      s.convertUniversal(request),
+		Constraints:    s.convertRequirements(request.Constraints),
+		Overrides:      s.convertRequirements(request.Overrides),
+	}
+	event.ResolutionStrategy, _ = analysis.NormalizeStrategy(request.ResolutionStrategy)
+
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	if err := s.kafkaProducer.PublishEvent(ctx, event); err != nil {
+		contextLogger.Error("Failed to publish event to Kafka",
+			zap.String("analysis_id", analysisID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("%w: %w", ErrPublishFailed, err)
+	}
+
+	contextLogger.Info("Event published to Kafka successfully",
+		zap.String("analysis_id", analysisID),
+	)
+
+	s.recordPending(ctx, event, contextLogger)
+
+	return &pbapi.AnalyzeResponse{
+		RequestId: analysisID,
+		Status:    analysis.StatusPending,
+		Message:   "Analysis request received and queued for processing",
+		CreatedAt: timestamppb.Now(),
+	}, nil
+}
+
+// requirementVersion приводит package_version к набору условий PEP 440 (v1.0 → ==1.0);
+// запрос к этому моменту уже проверен validateRequest
+func requirementVersion(version string) string {
+	specifiers, err := pep440.ParseRequirementVersion(version)
+	if err != nil {
+		return version
+	}
+	return specifiers.String()
+}
+
+// recordPending записывает статус pending со временем запроса: события резолвера новее,
+// поэтому pending не перезапишет их, даже если они дойдут до хранилища раньше.
+// Ошибка записи не отменяет уже опубликованный анализ
+func (s *AnalysisService) recordPending(ctx context.Context, event *eventspb.AnalysisStartedEvent, contextLogger logger.LoggerInterface) {
+	if s.statusRecorder == nil {
+		return
+	}
+
+	err := s.statusRecorder.Apply(ctx, &eventspb.AnalysisStatusEvent{
+		RequestId:   event.RequestId,
+		Status:      analysis.StatusPending,
+		Message:     "Analysis request received and queued for processing",
+		ServiceName: serviceName,
+		Timestamp:   event.Timestamp,
+	})
+	if err != nil {
+		contextLogger.Warn("Failed to record pending status",
+			zap.String("analysis_id", event.RequestId),
+			zap.Error(err),
+		)
+	}
+}
+
+// convertPackages нормализует имена пакетов и extras по PEP 503, а package_version —
+// через pep440.ParseRequirementVersion; исходное написание имени сохраняется в display_name
+func (s *AnalysisService) convertPackages(apiPackages []*pbapi.AnalyzeRequest_RequiredPackage) []*eventspb.AnalysisStartedEvent_RequiredPackage {
+	eventPackages := make([]*eventspb.AnalysisStartedEvent_RequiredPackage, len(apiPackages))
+	for i, pkg := range apiPackages {
+		extras := make([]string, len(pkg.Extras))
+		for j, extra := range pkg.Extras {
+			extras[j] = pep503.Normalize(extra)
+		}
+
+		eventPackages[i] = &eventspb.AnalysisStartedEvent_RequiredPackage{
+			PackageName:    pep503.Normalize(pkg.PackageName),
+			DisplayName:    pkg.PackageName,
+			PackageVersion: requirementVersion(pkg.PackageVersion),
+			Extras:         extras,
+			Marker:         pkg.Marker,
+			Url:            pkg.Url,
+			Hashes:         pkg.Hashes,
+		}
+	}
+	return eventPackages
+}
+
+// convertPinnedVersions нормализует имена закреплённых пакетов по PEP 503
+func (s *AnalysisService) convertPinnedVersions(pinned map[string]string) map[string]string {
+	if len(pinned) == 0 {
+		return nil
+	}
+	normalized := make(map[string]string, len(pinned))
+	for name, version := range pinned {
+		normalized[pep503.Normalize(name)] = version
+	}
+	return normalized
+}
+
+// convertRequirements нормализует имена в constraints и overrides по PEP 503
+func (s *AnalysisService) convertRequirements(lines []string) []string {
+	var normalized []string
+	for _, line := range lines {
+		requirement, err := pep508.ParseRequirement(line)
+		if err != nil {
+			continue
+		}
+		requirement.Name = pep503.Normalize(requirement.Name)
+		normalized = append(normalized, requirement.String())
+	}
+	return normalized
+}
+
+// targetPlatform собирает платформу из запроса, nil — платформа не задана
+func targetPlatform(req *pbapi.AnalyzeRequest) *pep425.Platform {
+	if req.TargetPlatform == nil {
+		return nil
+	}
+	return &pep425.Platform{
+		PythonVersion: req.PythonVersion,
+		OS:            req.TargetPlatform.Os,
+		Arch:          req.TargetPlatform.Arch,
+		Libc:          req.TargetPlatform.Libc,
+		LibcVersion:   req.TargetPlatform.LibcVersion,
+		MacOSVersion:  req.TargetPlatform.MacosVersion,
+	}
+}
+
+// convertPlatform передаёт платформу дальше уже нормализованной, с подставленными значениями по умолчанию
+func (s *AnalysisService) convertPlatform(req *pbapi.AnalyzeRequest) *eventspb.TargetPlatform {
+	platform := targetPlatform(req)
+	if platform == nil {
+		return nil
+	}
+	normalized, err := platform.Normalize()
+	if err != nil {
+		return nil
+	}
+	return &eventspb.TargetPlatform{
+		Os:           normalized.OS,
+		Arch:         normalized.Arch,
+		Libc:         normalized.Libc,
+		LibcVersion:  normalized.LibcVersion,
+		MacosVersion: normalized.MacOSVersion,
+	}
+}
+
+// universalEnvironments нормализует версии Python и платформы универсального разрешения,
+// подставляя python_version и платформы по умолчанию. Нарушения добавляются в validationErr
+func universalEnvironments(req *pbapi.AnalyzeRequest, validationErr *ValidationError) ([]string, []pep425.Platform) {
+	versions := req.Universal.PythonVersions
+	if len(versions) == 0 && req.PythonVersion != "" {
+		versions = []string{req.PythonVersion}
+	}
+	var pythonVersions []string
+	seenVersions := make(map[string]bool)
+	for i, version := range versions {
+		if _, err := (pep425.Platform{PythonVersion: version}).Normalize(); err != nil {
+			validationErr.add(fmt.Sprintf("universal.python_versions[%d]", i), "%s", err)
+			continue
+		}
+		// Маркеры различают окружения по python_version, то есть по major.minor
+		short := pep508.NewEnvironment(version).PythonVersion
+		if seenVersions[short] {
+			validationErr.add(fmt.Sprintf("universal.python_versions[%d]", i), "python %s is listed twice", short)
+			continue
+		}
+		seenVersions[short] = true
+		pythonVersions = append(pythonVersions, version)
+	}
+	if len(pythonVersions) == 0 {
+		return nil, nil
+	}
+
+	requested := req.Universal.Platforms
+	if len(requested) == 0 {
+		requested = defaultUniversalPlatforms
+	}
+	var platforms []pep425.Platform
+	seenPlatforms := make(map[pep425.Platform]bool)
+	for i, target := range requested {
+		platform, err := pep425.Platform{
+			PythonVersion: pythonVersions[0],
+			OS:            target.Os,
+			Arch:          target.Arch,
+			Libc:          target.Libc,
+			LibcVersion:   target.LibcVersion,
+			MacOSVersion:  target.MacosVersion,
+		}.Normalize()
+		if err != nil {
+			validationErr.add(fmt.Sprintf("universal.platforms[%d]", i), "%s", err)
+			continue
+		}
+		platform.PythonVersion = ""
+		if seenPlatforms[platform] {
+			validationErr.add(fmt.Sprintf("universal.platforms[%d]", i), "platform %s %s is listed twice",
+				platform.OS, platform.Arch)
+			continue
+		}
+		seenPlatforms[platform] = true
+		platforms = append(platforms, platform)
+	}
+	return pythonVersions, platforms
+}
+
+// convertUniversal передаёт окружения универсального разрешения нормализованными, nil — запрос для одного окружения
+func (s *AnalysisService) convertUniversal(req *pbapi.AnalyzeRequest) *eventspb.UniversalResolution {
+	if req.Universal == nil {
+		return nil
+	}
+	pythonVersions, platforms := universalEnvironments(req, &ValidationError{})
+	universal := &eventspb.UniversalResolution{PythonVersions: pythonVersions}
+	for _, platform := range platforms {
+		universal.Platforms = append(universal.Platforms, &eventspb.TargetPlatform{
+			Os:           platform.OS,
+			Arch:         platform.Arch,
+			Libc:         platform.Libc,
+			LibcVersion:  platform.LibcVersion,
+			MacosVersion: platform.MacOSVersion,
+		})
+	}
+	return universal
+}
+
+func (s *AnalysisService) validateRequest(req *pbapi.AnalyzeRequest) error {
+	validationErr := &ValidationError{}
+
+	if req.UserId == "" {
+		validationErr.add("user_id", "user_id is required")
+	}
+	if req.PythonVersion == "" && (req.Universal == nil || len(req.Universal.PythonVersions) == 0) {
+		validationErr.add("python_version", "python_version is required")
+	}
+	if len(req.Packages) == 0 {
+		validationErr.add("packages", "at least one package is required")
+	}
+	if platform := targetPlatform(req); platform != nil && req.PythonVersion != "" {
+		if _, err := platform.Normalize(); err != nil {
+			validationErr.add("target_platform", "%s", err)
+		}
+	}
+
+	if req.Universal != nil {
+		validateUniversal(req, validationErr)
+	}
+
+	strategy, ok := analysis.NormalizeStrategy(req.ResolutionStrategy)
+	if !ok {
+		validationErr.add("resolution_strategy", "unknown strategy %q, expected one of %s",
+			req.ResolutionStrategy, strings.Join(analysis.Strategies, ", "))
+	} else if len(req.PinnedVersions) > 0 && strategy != analysis.StrategyPreferPinned {
+		validationErr.add("pinned_versions", "pinned_versions are only used by the %s strategy",
+			analysis.StrategyPreferPinned)
+	}
+	for _, name := range slices.Sorted(maps.Keys(req.PinnedVersions)) {
+		field := fmt.Sprintf("pinned_versions[%s]", name)
+		if err := pep503.Validate(name); err != nil {
+			validationErr.add(field, "%s", err)
+		}
+		if _, err := pep440.Parse(req.PinnedVersions[name]); err != nil {
+			validationErr.add(field, "%s", err)
+		}
+	}
+
+	validateRequirements("constraints", req.Constraints, false, validationErr)
+	validateRequirements("overrides", req.Overrides, true, validationErr)
+
+	for i, pkg := range req.Packages {
+		if pkg.PackageName == "" {
+			validationErr.add(fmt.Sprintf("packages[%d].package_name", i),
+				"package_name is required for package %d", i)
+		} else if err := pep503.Validate(pkg.PackageName); err != nil {
+			validationErr.add(fmt.Sprintf("packages[%d].package_name", i), "%s", err)
+		}
+		for j, extra := range pkg.Extras {
+			if err := pep503.Validate(extra); err != nil {
+				validationErr.add(fmt.Sprintf("packages[%d].extras[%d]", i, j), "%s", err)
+			}
+		}
+		if pkg.Marker != "" {
+			if _, err := pep508.ParseMarker(pkg.Marker); err != nil {
+				validationErr.add(fmt.Sprintf("packages[%d].marker", i), "%s", err)
+			}
+		}
+
+		versionField := fmt.Sprintf("packages[%d].package_version", i)
+		specifiers, err := pep440.ParseRequirementVersion(pkg.PackageVersion)
+		if err != nil {
+			validationErr.add(versionField, "%s", err)
+			continue
+		}
+		if req.Locked && pkg.Url == "" && !specifiers.IsPinned() {
+			validationErr.add(versionField,
+				"locked package %q must be pinned with == or a direct URL", pkg.PackageName)
+		}
+	}
+
+	if len(validationErr.Violations) > 0 {
+		return validationErr
+	}
+	return nil
+}
+
+// validateRequirements проверяет constraints или overrides: они задают только версии,
+// поэтому extras и прямые ссылки не допускаются. unique — у пакета может быть только одна
+// строка без маркера: constraints пересекаются, а из двух overrides выбрать нельзя
+func validateRequirements(field string, lines []string, unique bool, validationErr *ValidationError) {
+	unconditional := make(map[string]bool)
+	for i, line := range lines {
+		itemField := fmt.Sprintf("%s[%d]", field, i)
+		requirement, err := pep508.ParseRequirement(line)
+		if err != nil {
+			validationErr.add(itemField, "%s", err)
+			continue
+		}
+		if len(requirement.Extras) > 0 {
+			validationErr.add(itemField, "%s cannot request extras", field)
+		}
+		if requirement.URL != "" {
+			validationErr.add(itemField, "%s cannot use direct URLs", field)
+		}
+
+		name := pep503.Normalize(requirement.Name)
+		if unique && requirement.Marker.IsEmpty() {
+			if unconditional[name] {
+				validationErr.add(itemField, "%s is listed twice without a marker", name)
+			}
+			unconditional[name] = true
+		}
+	}
+}
+
+func validateUniversal(req *pbapi.AnalyzeRequest, validationErr *ValidationError) {
+	if req.TargetPlatform != nil {
+		validationErr.add("target_platform",
+			"target_platform cannot be combined with universal, list platforms in universal.platforms")
+	}
+	if req.Locked {
+		validationErr.add("universal", "locked packages are not resolved, universal resolution does not apply")
+	}
+
+	pythonVersions, platforms := universalEnvironments(req, validationErr)
+	if count := len(pythonVersions) * len(platforms); count > maxUniversalEnvironments {
+		validationErr.add("universal", "universal resolution covers %d environments, at most %d are supported",
+			count, maxUniversalEnvironments)
+	}
+}
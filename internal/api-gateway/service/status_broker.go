@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+)
+
+const defaultSubscriptionBuffer = 64
+
+// StatusBroker раздаёт события статуса локальным подписчикам (SSE, WebSocket).
+// Один экземпляр на gateway, наполняется единственным консьюмером со своей consumer group.
+type StatusBroker struct {
+	mu            sync.RWMutex
+	subscriptions map[string]map[*StatusSubscription]struct{}
+	bufferSize    int
+}
+
+func NewStatusBroker() *StatusBroker {
+	return &StatusBroker{
+		subscriptions: make(map[string]map[*StatusSubscription]struct{}),
+		bufferSize:    defaultSubscriptionBuffer,
+	}
+}
+
+// StatusSubscription получает события набора анализов через общий канал.
+// Канал Events закрывается при Close или если подписчик не успевает читать события.
+type StatusSubscription struct {
+	broker     *StatusBroker
+	requestIDs map[string]struct{} // защищено broker.mu
+	closed     bool                // защищено broker.mu
+	events     chan *eventspb.AnalysisStatusEvent
+}
+
+func (b *StatusBroker) Subscribe(requestIDs ...string) *StatusSubscription {
+	subscription := &StatusSubscription{
+		broker:     b,
+		requestIDs: make(map[string]struct{}),
+		events:     make(chan *eventspb.AnalysisStatusEvent, b.bufferSize),
+	}
+	subscription.Add(requestIDs...)
+
+	return subscription
+}
+
+// Publish рассылает событие подписчикам анализа. Сигнатура совпадает с kafka.StatusEventHandler.
+func (b *StatusBroker) Publish(ctx context.Context, event *eventspb.AnalysisStatusEvent) error {
+	b.mu.RLock()
+	var lagging []*StatusSubscription
+	for subscription := range b.subscriptions[event.RequestId] {
+		select {
+		case subscription.events <- event:
+		default:
+			lagging = append(lagging, subscription)
+		}
+	}
+	b.mu.RUnlock()
+
+	// Отстающих подписчиков отключаем: клиент переподключится и догонит историю
+	for _, subscription := range lagging {
+		subscription.Close()
+	}
+
+	return nil
+}
+
+// Add подписывает на события дополнительных анализов
+func (s *StatusSubscription) Add(requestIDs ...string) {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	for _, requestID := range requestIDs {
+		if s.broker.subscriptions[requestID] == nil {
+			s.broker.subscriptions[requestID] = make(map[*StatusSubscription]struct{})
+		}
+		s.broker.subscriptions[requestID][s] = struct{}{}
+		s.requestIDs[requestID] = struct{}{}
+	}
+}
+
+// Remove отписывает от событий анализов; уже поставленные в канал события остаются
+func (s *StatusSubscription) Remove(requestIDs ...string) {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	for _, requestID := range requestIDs {
+		s.removeLocked(requestID)
+	}
+}
+
+// Has сообщает, подписан ли подписчик на анализ
+func (s *StatusSubscription) Has(requestID string) bool {
+	s.broker.mu.RLock()
+	defer s.broker.mu.RUnlock()
+
+	_, ok := s.requestIDs[requestID]
+	return ok
+}
+
+func (s *StatusSubscription) Events() <-chan *eventspb.AnalysisStatusEvent {
+	return s.events
+}
+
+func (s *StatusSubscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	for requestID := range s.requestIDs {
+		s.removeLocked(requestID)
+	}
+	s.closed = true
+	close(s.events)
+}
+
+func (s *StatusSubscription) removeLocked(requestID string) {
+	delete(s.requestIDs, requestID)
+	delete(s.broker.subscriptions[requestID], s)
+	if len(s.broker.subscriptions[requestID]) == 0 {
+		delete(s.broker.subscriptions, requestID)
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/kafka"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"go.uber.org/zap"
+)
+
+// StatusProjector проецирует поток AnalysisStatusEvent в хранилище статусов
+// и историю событий, из которых gateway отвечает на запросы статуса
+type StatusProjector struct {
+	consumer          *kafka.StatusConsumer
+	statusRepository  repository.StatusRepository
+	historyRepository repository.StatusHistoryRepository
+	logger            logger.LoggerInterface
+}
+
+// interface check
+var _ StatusRecorder = (*StatusProjector)(nil)
+
+func NewStatusProjector(
+	consumer *kafka.StatusConsumer,
+	statusRepository repository.StatusRepository,
+	historyRepository repository.StatusHistoryRepository,
+	logger logger.LoggerInterface,
+) *StatusProjector {
+	return &StatusProjector{
+		consumer:          consumer,
+		statusRepository:  statusRepository,
+		historyRepository: historyRepository,
+		logger:            logger,
+	}
+}
+
+// Run блокируется до отмены контекста
+func (p *StatusProjector) Run(ctx context.Context) error {
+	err := p.consumer.Run(ctx, p.Apply)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// Apply применяет одно событие статуса, пропуская устаревшие обновления
+func (p *StatusProjector) Apply(ctx context.Context, event *eventspb.AnalysisStatusEvent) error {
+	contextLogger := p.logger.WithRequestID(event.RequestId)
+
+	if event.RequestId == "" {
+		contextLogger.Warn("Status event without request_id skipped")
+		return nil
+	}
+
+	// История хранит все события, включая пришедшие не по порядку
+	if err := p.historyRepository.Append(ctx, event); err != nil {
+		contextLogger.Error("Failed to append status event to history", zap.Error(err))
+		return err
+	}
+
+	if err := p.statusRepository.Save(ctx, event); err != nil {
+		if errors.Is(err, repository.ErrStaleStatus) {
+			contextLogger.Debug("Out-of-order status event ignored",
+				zap.String("status", event.Status),
+				zap.String("service", event.ServiceName),
+			)
+			return nil
+		}
+
+		contextLogger.Error("Failed to project status event", zap.Error(err))
+		return err
+	}
+
+	contextLogger.Debug("Status event projected",
+		zap.String("status", event.Status),
+		zap.Int64("progress", event.Progress),
+		zap.String("service", event.ServiceName),
+	)
+
+	return nil
+}
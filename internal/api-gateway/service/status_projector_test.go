@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/service"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var projectorBaseTime = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func setupStatusProjector(t *testing.T) (*service.StatusProjector, *repository.RedisStatusRepository, *repository.RedisStatusHistoryRepository) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	statusRepository := repository.NewRedisStatusRepository(client, time.Hour)
+	historyRepository := repository.NewRedisStatusHistoryRepository(client, time.Hour)
+	projector := service.NewStatusProjector(nil, statusRepository, historyRepository, &logger.Logger{Logger: zap.NewNop()})
+
+	return projector, statusRepository, historyRepository
+}
+
+func projectedEvent(status string, progress int64, offset time.Duration) *eventspb.AnalysisStatusEvent {
+	return &eventspb.AnalysisStatusEvent{
+		RequestId:   "analysis-1",
+		Status:      status,
+		Message:     status,
+		Progress:    progress,
+		ServiceName: "dependency-resolver",
+		Timestamp:   timestamppb.New(projectorBaseTime.Add(offset)),
+	}
+}
+
+func TestStatusProjector_SavesStatusAndAppendsHistory(t *testing.T) {
+	projector, statusRepository, historyRepository := setupStatusProjector(t)
+
+	require.NoError(t, projector.Apply(t.Context(), projectedEvent("running", 40, 0)))
+	require.NoError(t, projector.Apply(t.Context(), projectedEvent("completed", 100, time.Second)))
+
+	status, err := statusRepository.Get(t.Context(), "analysis-1")
+	require.NoError(t, err)
+	assert.Equal(t, "completed", status.Status)
+	assert.Equal(t, int64(100), status.Progress)
+
+	history, err := historyRepository.ListSince(t.Context(), "analysis-1", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "running", history[0].Status)
+	assert.Equal(t, "completed", history[1].Status)
+}
+
+func TestStatusProjector_IgnoresStaleStatus(t *testing.T) {
+	projector, statusRepository, historyRepository := setupStatusProjector(t)
+
+	require.NoError(t, projector.Apply(t.Context(), projectedEvent("completed", 100, time.Second)))
+	// Устаревшее событие не ошибка: оно попадает в историю, но не перезаписывает статус
+	require.NoError(t, projector.Apply(t.Context(), projectedEvent("running", 40, 0)))
+
+	status, err := statusRepository.Get(t.Context(), "analysis-1")
+	require.NoError(t, err)
+	assert.Equal(t, "completed", status.Status)
+	assert.Equal(t, int64(100), status.Progress)
+
+	history, err := historyRepository.ListSince(t.Context(), "analysis-1", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "running", history[0].Status)
+	assert.Equal(t, "completed", history[1].Status)
+}
+
+func TestStatusProjector_SkipsEventWithoutRequestID(t *testing.T) {
+	projector, statusRepository, _ := setupStatusProjector(t)
+
+	event := projectedEvent("running", 40, 0)
+	event.RequestId = ""
+	require.NoError(t, projector.Apply(t.Context(), event))
+
+	_, err := statusRepository.Get(t.Context(), "")
+	assert.ErrorIs(t, err, repository.ErrStatusNotFound)
+}
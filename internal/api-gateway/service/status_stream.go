@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"google.golang.org/protobuf/proto"
+)
+
+// StatusStream — история событий одного анализа и живые события из StatusBroker без повторов.
+// Общая часть SSE и gRPC WatchStatus.
+type StatusStream struct {
+	// History — события после lastEventID в порядке StatusEventID
+	History []*eventspb.AnalysisStatusEvent
+
+	subscription *StatusSubscription
+	lastEventID  int64
+	// StatusEventID не уникален (совпадает у событий одной микросекунды и равен 0 без timestamp),
+	// поэтому отправленные события запоминаются по содержимому, как члены sorted set истории
+	sent map[string]struct{}
+}
+
+// OpenStatusStream подписывается на события анализа и читает историю после lastEventID.
+// История после Last-Event-ID может быть пустой и у известного анализа, поэтому тогда проверяется
+// сохранённый статус: если нет и его, возвращается repository.ErrStatusNotFound
+func OpenStatusStream(
+	ctx context.Context,
+	broker *StatusBroker,
+	statusRepository repository.StatusRepository,
+	historyRepository repository.StatusHistoryRepository,
+	analysisID string,
+	lastEventID int64,
+) (*StatusStream, error) {
+	// Подписываемся до чтения истории, чтобы не потерять события между ними
+	subscription := broker.Subscribe(analysisID)
+
+	history, err := historyRepository.ListSince(ctx, analysisID, lastEventID)
+	if err != nil {
+		subscription.Close()
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		if _, err := statusRepository.Get(ctx, analysisID); err != nil {
+			subscription.Close()
+			return nil, err
+		}
+	}
+
+	return &StatusStream{
+		History:      history,
+		subscription: subscription,
+		lastEventID:  lastEventID,
+		sent:         make(map[string]struct{}),
+	}, nil
+}
+
+// Events — живые события; канал закрывается, если подписчик не успевает их читать
+func (s *StatusStream) Events() <-chan *eventspb.AnalysisStatusEvent {
+	return s.subscription.Events()
+}
+
+// IsNew отбрасывает уже отправленные события и события, полученные клиентом до Last-Event-ID
+func (s *StatusStream) IsNew(event *eventspb.AnalysisStatusEvent) bool {
+	if s.lastEventID > 0 && repository.StatusEventID(event) <= s.lastEventID {
+		return false
+	}
+	_, ok := s.sent[statusEventKey(event)]
+	return !ok
+}
+
+// MarkSent запоминает отправленное клиенту событие
+func (s *StatusStream) MarkSent(event *eventspb.AnalysisStatusEvent) {
+	s.sent[statusEventKey(event)] = struct{}{}
+}
+
+func (s *StatusStream) Close() {
+	s.subscription.Close()
+}
+
+func statusEventKey(event *eventspb.AnalysisStatusEvent) string {
+	data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(event)
+	return string(data)
+}
@@ -0,0 +1,46 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrUnknownEnvironment = errors.New("unknown environment")
+
+// SelectEnvironment возвращает из универсального результата пакеты, которые ставятся в окружение
+// с идентификатором environment, вместе с рёбрами графа этого окружения — так, будто результат
+// разрешался для одного окружения. Результат одного окружения возвращается целиком
+func SelectEnvironment(result *eventspb.AnalysisResultEvent, environment string) ([]*eventspb.AnalysisResultEvent_ResolvedPackage, error) {
+	if len(result.Environments) == 0 {
+		return result.Packages, nil
+	}
+	known := slices.ContainsFunc(result.Environments, func(e *eventspb.AnalysisResultEvent_Environment) bool {
+		return e.Id == environment
+	})
+	if !known {
+		return nil, fmt.Errorf("%w %q", ErrUnknownEnvironment, environment)
+	}
+
+	var packages []*eventspb.AnalysisResultEvent_ResolvedPackage
+	for _, pkg := range result.Packages {
+		if !slices.Contains(pkg.Environments, environment) {
+			continue
+		}
+		selected := proto.CloneOf(pkg)
+		for _, dependency := range pkg.ConditionalDependencies {
+			if slices.Contains(dependency.Environments, environment) {
+				selected.Dependencies = append(selected.Dependencies, dependency.PackageName)
+			}
+		}
+		slices.Sort(selected.Dependencies)
+		selected.Marker = ""
+		selected.Environments = nil
+		selected.ConditionalDependencies = nil
+		packages = append(packages, selected)
+	}
+	return packages, nil
+}
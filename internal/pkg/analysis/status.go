@@ -0,0 +1,20 @@
+package analysis
+
+// Статусы анализа, которые сервисы публикуют в AnalysisStatusEvent
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// IsTerminalStatus сообщает, что после этого статуса обновлений по анализу не будет
+func IsTerminalStatus(status string) bool {
+	switch status {
+	case StatusCompleted, StatusFailed, StatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
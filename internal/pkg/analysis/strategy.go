@@ -0,0 +1,32 @@
+package analysis
+
+import "strings"
+
+// Стратегии выбора версии, когда требованиям подходит несколько
+const (
+	// StrategyHighest — наибольшая подходящая версия каждого пакета
+	StrategyHighest = "highest"
+	// StrategyLowest — наименьшая подходящая версия, проверка нижних границ
+	StrategyLowest = "lowest"
+	// StrategyLowestDirect — наименьшие версии прямых зависимостей и наибольшие транзитивных
+	StrategyLowestDirect = "lowest-direct"
+	// StrategyPreferPinned — версии из предыдущего lock, пока требования их допускают
+	StrategyPreferPinned = "prefer-pinned"
+)
+
+// Strategies перечисляет допустимые стратегии
+var Strategies = []string{StrategyHighest, StrategyLowest, StrategyLowestDirect, StrategyPreferPinned}
+
+// NormalizeStrategy приводит написание стратегии (регистр, "_" вместо "-") к каноническому,
+// пустое значение — StrategyHighest. ok == false для неизвестной стратегии
+func NormalizeStrategy(strategy string) (string, bool) {
+	strategy = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(strategy)), "_", "-")
+	switch strategy {
+	case "":
+		return StrategyHighest, true
+	case StrategyHighest, StrategyLowest, StrategyLowestDirect, StrategyPreferPinned:
+		return strategy, true
+	default:
+		return "", false
+	}
+}
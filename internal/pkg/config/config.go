@@ -15,6 +15,7 @@ type Config struct {
 	Redis    RedisConfig    `mapstructure:"redis"`
 	Database DatabaseConfig `mapstructure:"database"`
 	PyPI     PyPIConfig     `mapstructure:"pypi"`
+	Resolver ResolverConfig `mapstructure:"resolver"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -67,6 +68,7 @@ func setDefaults() {
 		l LoggerConfig
 		r RedisConfig
 		p PyPIConfig
+		v ResolverConfig
 	)
 
 	// Init defaults ServerConfig
@@ -89,6 +91,9 @@ func setDefaults() {
 
 	// Init defaults PyPIConfig
 	p.SetDefaults()
+
+	// Init defaults ResolverConfig
+	v.SetDefaults()
 }
 
 func bindEnvironmentVars() {
@@ -99,6 +104,7 @@ func bindEnvironmentVars() {
 		l LoggerConfig
 		r RedisConfig
 		p PyPIConfig
+		v ResolverConfig
 	)
 
 	// Bind ServerConfig vars
@@ -118,6 +124,9 @@ func bindEnvironmentVars() {
 
 	// Bind PyPIConfig vars
 	p.BindEnvironmentVars()
+
+	// Bind ResolverConfig vars
+	v.BindEnvironmentVars()
 }
 
 func postProcessConfig(config *Config) error {
@@ -127,6 +136,8 @@ func postProcessConfig(config *Config) error {
 
 	config.Kafka.ParseBrokers()
 
+	config.PyPI.ParseIndexes()
+
 	return nil
 }
 
@@ -139,6 +150,10 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("kafka topic is required")
 	}
 
+	if config.Kafka.StatusTopic == "" {
+		return fmt.Errorf("kafka status topic is required")
+	}
+
 	if config.Database.Host == "" {
 		return fmt.Errorf("database host is required")
 	}
@@ -149,20 +164,61 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("database name is required")
 	}
 
+	if config.Kafka.ResultTopic == "" {
+		return fmt.Errorf("kafka result topic is required")
+	}
+
+	if config.Redis.StatusTTL <= 0 {
+		return fmt.Errorf("redis status TTL must be positive")
+	}
+
 	if config.PyPI.APIURL == "" {
 		return fmt.Errorf("PyPI API URL is required")
 	}
 
+	if config.PyPI.MaxRetries < 0 {
+		return fmt.Errorf("PyPI max retries must not be negative")
+	}
+
+	if config.PyPI.RateLimit < 0 {
+		return fmt.Errorf("PyPI rate limit must not be negative")
+	}
+
+	if config.PyPI.Offline && config.PyPI.SnapshotPath == "" {
+		return fmt.Errorf("PyPI snapshot path is required in offline mode")
+	}
+
+	if err := config.PyPI.ValidateIndexes(); err != nil {
+		return err
+	}
+
+	if config.Resolver.Timeout <= 0 {
+		return fmt.Errorf("resolver timeout must be positive")
+	}
+
+	if config.Resolver.MaxPackages <= 0 {
+		return fmt.Errorf("resolver max packages must be positive")
+	}
+
 	return nil
 }
 
 func (c *Config) LogConfig() {
 	fmt.Printf("Configuration loaded:\n")
 	fmt.Printf("\tServer: %s:%s (mode: %s)\n", c.Server.Host, c.Server.Port, c.Server.Mode)
+	fmt.Printf("\tgRPC Server: %s\n", c.Server.GetGRPCAddress())
 	fmt.Printf("\tKafka Brokers: %v\n", c.Kafka.Brokers)
 	fmt.Printf("\tKafka Topic: %s\n", c.Kafka.Topic)
+	fmt.Printf("\tKafka Status Topic: %s\n", c.Kafka.StatusTopic)
+	fmt.Printf("\tKafka Result Topic: %s\n", c.Kafka.ResultTopic)
 	fmt.Printf("\tDatabase: %s:%s/%s\n", c.Database.Host, c.Database.Port, c.Database.DBName)
 	fmt.Printf("\tRedis: %s:%s\n", c.Redis.Host, c.Redis.Port)
 	fmt.Printf("\tPyPI API: %s\n", c.PyPI.APIURL)
+	if c.PyPI.Offline {
+		fmt.Printf("\tPyPI Offline Snapshot: %s\n", c.PyPI.SnapshotPath)
+	}
+	for _, index := range c.PyPI.Indexes {
+		fmt.Printf("\tPyPI Index: %s %s (%s, priority %d)\n", index.Name, index.URL, index.API, index.Priority)
+	}
 	fmt.Printf("\tLog Level: %s (%s)\n", c.Logger.Level, c.Logger.Encoding)
 }
\ No newline at end of file
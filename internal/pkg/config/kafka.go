@@ -9,6 +9,8 @@ import (
 type KafkaConfig struct {
 	Brokers       []string       `mapstructure:"brokers"`
 	Topic         string         `mapstructure:"topic"`
+	StatusTopic   string         `mapstructure:"status_topic"`
+	ResultTopic   string         `mapstructure:"result_topic"`
 	ConsumerGroup string         `mapstructure:"consumer_group"`
 	Producer      ProducerConfig `mapstructure:"producer"`
 	Consumer      ConsumerConfig `mapstructure:"consumer"`
@@ -17,6 +19,8 @@ type KafkaConfig struct {
 func (k *KafkaConfig) SetDefaults() {
 	// Base Kafka architecture defaults
 	viper.SetDefault("kafka.topic", "dependency.analysis.request")
+	viper.SetDefault("kafka.status_topic", "dependency.status.response")
+	viper.SetDefault("kafka.result_topic", "dependency.analysis.response")
 	viper.SetDefault("kafka.consumer_group", "api-gateway-consumer")
 	viper.SetDefault("kafka.brokers", []string{"localhost:9092"})
 
@@ -38,6 +42,8 @@ func (k *KafkaConfig) BindEnvironmentVars() {
 	// Kafka
 	viper.BindEnv("kafka.brokers", "API_GATEWAY_KAFKA_BROKERS")
 	viper.BindEnv("kafka.topic", "API_GATEWAY_KAFKA_TOPIC")
+	viper.BindEnv("kafka.status_topic", "API_GATEWAY_KAFKA_STATUS_TOPIC")
+	viper.BindEnv("kafka.result_topic", "API_GATEWAY_KAFKA_RESULT_TOPIC")
 	viper.BindEnv("kafka.consumer_group", "API_GATEWAY_KAFKA_CONSUMER_GROUP")
 }
 
@@ -1,17 +1,55 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
+// Протоколы индексов пакетов
+const (
+	IndexAPIJSON   = "json"
+	IndexAPISimple = "simple"
+)
+
 type PyPIConfig struct {
 	APIURL         string        `mapstructure:"api_url"`
 	RequestTimeout time.Duration `mapstructure:"request_timeout"`
 	MaxRetries     int           `mapstructure:"max_retries"`
 	RateLimit      int           `mapstructure:"rate_limit"`
 	CacheTimeout   time.Duration `mapstructure:"cache_timeout"`
+	// Indexes — индексы пакетов; если список пуст, используется один JSON индекс api_url
+	Indexes []IndexConfig `mapstructure:"indexes"`
+	// Routes закрепляют пакеты за индексами, первое совпавшее правило побеждает
+	Routes []RouteConfig `mapstructure:"routes"`
+	// Offline отключает сетевые индексы: пакеты берутся только из снимка snapshot_path
+	Offline bool `mapstructure:"offline"`
+	// SnapshotPath — файл снимка метаданных или каталог с wheel и sdist для импорта
+	SnapshotPath string `mapstructure:"snapshot_path"`
+}
+
+// IndexConfig описывает один индекс пакетов
+type IndexConfig struct {
+	Name string `mapstructure:"name"`
+	URL  string `mapstructure:"url"`
+	// API — json для JSON API PyPI или simple для Simple API
+	API      string `mapstructure:"api"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// Priority — порядок опроса: индекс с меньшим значением опрашивается раньше
+	Priority int `mapstructure:"priority"`
+}
+
+// RouteConfig разрешает пакетам, подходящим под шаблоны, приходить только из указанных индексов.
+// Шаблоны в синтаксисе path.Match сравниваются с нормализованным по PEP 503 именем, например acme-*
+type RouteConfig struct {
+	Packages []string `mapstructure:"packages"`
+	Indexes  []string `mapstructure:"indexes"`
 }
 
 func (p *PyPIConfig) SetDefaults() {
@@ -21,9 +59,95 @@ func (p *PyPIConfig) SetDefaults() {
 	viper.SetDefault("pypi.max_retries", 3)
 	viper.SetDefault("pypi.rate_limit", 100)
 	viper.SetDefault("pypi.cache_timeout", "1h")
+	viper.SetDefault("pypi.offline", false)
 }
 
 func (p *PyPIConfig) BindEnvironmentVars() {
 	// PyPI
 	viper.BindEnv("pypi.api_url", "PYPI_API_URL")
+	viper.BindEnv("pypi.request_timeout", "PYPI_REQUEST_TIMEOUT")
+	viper.BindEnv("pypi.max_retries", "PYPI_MAX_RETRIES")
+	viper.BindEnv("pypi.rate_limit", "PYPI_RATE_LIMIT")
+	viper.BindEnv("pypi.cache_timeout", "PYPI_CACHE_TIMEOUT")
+	viper.BindEnv("pypi.offline", "PYPI_OFFLINE")
+	viper.BindEnv("pypi.snapshot_path", "PYPI_SNAPSHOT_PATH")
+}
+
+// ParseIndexes подставляет индекс по умолчанию, берёт учётные данные
+// из PYPI_INDEX_<NAME>_USERNAME и PYPI_INDEX_<NAME>_PASSWORD
+// и упорядочивает индексы по приоритету
+func (p *PyPIConfig) ParseIndexes() {
+	if len(p.Indexes) == 0 {
+		p.Indexes = []IndexConfig{{Name: "pypi", URL: p.APIURL, API: IndexAPIJSON}}
+	}
+
+	for i := range p.Indexes {
+		index := &p.Indexes[i]
+		if index.API == "" {
+			index.API = IndexAPISimple
+		}
+
+		prefix := "PYPI_INDEX_" + envName(index.Name) + "_"
+		if username, ok := os.LookupEnv(prefix + "USERNAME"); ok {
+			index.Username = username
+		}
+		if password, ok := os.LookupEnv(prefix + "PASSWORD"); ok {
+			index.Password = password
+		}
+	}
+
+	slices.SortStableFunc(p.Indexes, func(a, b IndexConfig) int {
+		return a.Priority - b.Priority
+	})
+}
+
+// ValidateIndexes проверяет индексы и ссылки на них из правил маршрутизации
+func (p *PyPIConfig) ValidateIndexes() error {
+	names := make(map[string]bool, len(p.Indexes))
+	for _, index := range p.Indexes {
+		if index.Name == "" {
+			return fmt.Errorf("PyPI index name is required")
+		}
+		if names[index.Name] {
+			return fmt.Errorf("PyPI index %q is defined more than once", index.Name)
+		}
+		names[index.Name] = true
+
+		if index.URL == "" {
+			return fmt.Errorf("PyPI index %q URL is required", index.Name)
+		}
+		if index.API != IndexAPIJSON && index.API != IndexAPISimple {
+			return fmt.Errorf("PyPI index %q has unknown api %q", index.Name, index.API)
+		}
+	}
+
+	for i, route := range p.Routes {
+		if len(route.Packages) == 0 || len(route.Indexes) == 0 {
+			return fmt.Errorf("PyPI route %d must list packages and indexes", i)
+		}
+		for _, pattern := range route.Packages {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("PyPI route %d has invalid package pattern %q: %w", i, pattern, err)
+			}
+		}
+		for _, name := range route.Indexes {
+			if !names[name] {
+				return fmt.Errorf("PyPI route %d refers to unknown index %q", i, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
 }
@@ -19,22 +19,27 @@ type RedisConfig struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	PoolSize     int           `mapstructure:"pool_size"`
+	StatusTTL    time.Duration `mapstructure:"status_ttl"`
 }
 
 func (r *RedisConfig) SetDefaults() {
 	// Redis defaults
+	viper.SetDefault("redis.host", "localhost")
+	viper.SetDefault("redis.port", "6379")
 	viper.SetDefault("redis.db", 0)
 	viper.SetDefault("redis.max_retries", 3)
 	viper.SetDefault("redis.dial_timeout", "5s")
 	viper.SetDefault("redis.read_timeout", "3s")
 	viper.SetDefault("redis.write_timeout", "3s")
 	viper.SetDefault("redis.pool_size", 10)
+	viper.SetDefault("redis.status_ttl", "24h")
 }
 
 func (r *RedisConfig) BindEnvironmentVars() {
 	// Redis
 	viper.BindEnv("redis.url", "REDIS_URL")
 	viper.BindEnv("redis.password", "REDIS_PASSWORD")
+	viper.BindEnv("redis.status_ttl", "REDIS_STATUS_TTL")
 }
 
 // Existing helper methods remain the same
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ResolverConfig — настройки сервиса dependency-resolver
+type ResolverConfig struct {
+	Host string `mapstructure:"host"`
+	// Port — HTTP порт с /metrics и /health
+	Port          string `mapstructure:"port"`
+	ConsumerGroup string `mapstructure:"consumer_group"`
+	// Timeout ограничивает разрешение одного запроса
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxPackages — предел размера дерева зависимостей одного запроса
+	MaxPackages int `mapstructure:"max_packages"`
+	// AdminToken включает DELETE /admin/cache/:package на порту Port; запрос должен передать
+	// Authorization: Bearer <AdminToken>. Пустой токен (по умолчанию) отключает эндпоинт
+	AdminToken string `mapstructure:"admin_token"`
+}
+
+func (r *ResolverConfig) SetDefaults() {
+	// Resolver defaults
+	viper.SetDefault("resolver.host", "0.0.0.0")
+	viper.SetDefault("resolver.port", "8082")
+	viper.SetDefault("resolver.consumer_group", "dependency-resolver")
+	viper.SetDefault("resolver.timeout", "5m")
+	viper.SetDefault("resolver.max_packages", 1000)
+}
+
+func (r *ResolverConfig) BindEnvironmentVars() {
+	// Resolver
+	viper.BindEnv("resolver.host", "RESOLVER_HOST")
+	viper.BindEnv("resolver.port", "RESOLVER_PORT")
+	viper.BindEnv("resolver.consumer_group", "RESOLVER_CONSUMER_GROUP")
+	viper.BindEnv("resolver.timeout", "RESOLVER_TIMEOUT")
+	viper.BindEnv("resolver.max_packages", "RESOLVER_MAX_PACKAGES")
+	viper.BindEnv("resolver.admin_token", "RESOLVER_ADMIN_TOKEN")
+}
+
+func (r ResolverConfig) GetAddress() string {
+	return fmt.Sprintf("%s:%s", r.Host, r.Port)
+}
@@ -10,6 +10,7 @@ import (
 type ServerConfig struct {
 	Host            string        `mapstructure:"host"`
 	Port            string        `mapstructure:"port"`
+	GRPCPort        string        `mapstructure:"grpc_port"`
 	Mode            string        `mapstructure:"mode"`
 	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
@@ -24,6 +25,7 @@ func (s *ServerConfig) SetDefaults() {
 	// Server defaults with graceful shutdown
 	viper.SetDefault("server.host", "0.0.0.0")
 	viper.SetDefault("server.port", "8080")
+	viper.SetDefault("server.grpc_port", "50051")
 	viper.SetDefault("server.mode", "release")
 	viper.SetDefault("server.shutdown_timeout", "30s")
 	viper.SetDefault("server.read_timeout", "10s")
@@ -35,6 +37,7 @@ func (s *ServerConfig) SetDefaults() {
 func (s *ServerConfig) BindEnvironmentVars() {
 	// Server
 	viper.BindEnv("server.port", "API_GATEWAY_SERVER_PORT")
+	viper.BindEnv("server.grpc_port", "API_GATEWAY_GRPC_PORT")
 	viper.BindEnv("server.host", "API_GATEWAY_SERVER_HOST")
 	viper.BindEnv("server.mode", "API_GATEWAY_SERVER_MODE")
 }
@@ -42,3 +45,7 @@ func (s *ServerConfig) BindEnvironmentVars() {
 func (s ServerConfig) GetConfig() string {
 	return fmt.Sprintf("%s:%s", s.Host, s.Port)
 }
+
+func (s ServerConfig) GetGRPCAddress() string {
+	return fmt.Sprintf("%s:%s", s.Host, s.GRPCPort)
+}
@@ -0,0 +1,233 @@
+package distmeta_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/distmeta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const requestsMetadata = `Metadata-Version: 2.1
+Name: requests
+Version: 2.32.3
+Summary: Python HTTP for Humans.
+License: Apache-2.0
+        Copyright 2019 Kenneth Reitz
+Classifier: Development Status :: 5 - Production/Stable
+Classifier: License :: OSI Approved :: Apache Software License
+Requires-Python: >=3.8
+Requires-Dist: charset-normalizer<4,>=2
+Requires-Dist: idna<4,>=2.5
+Provides-Extra: socks
+Requires-Dist: PySocks!=1.5.7,>=1.5.6;
+  extra == "socks"
+
+# Requests
+License: not-a-header
+`
+
+// payload — несжимаемые данные, делающие архив заметно больше одного Range запроса
+func payload(t *testing.T, size int) []byte {
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	return data
+}
+
+func buildWheel(t *testing.T, big []byte) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	member, err := w.CreateHeader(&zip.FileHeader{Name: "requests/_blob.bin", Method: zip.Store})
+	require.NoError(t, err)
+	_, err = member.Write(big)
+	require.NoError(t, err)
+
+	for name, content := range map[string]string{
+		"requests-2.32.3.dist-info/METADATA": requestsMetadata,
+		"requests-2.32.3.dist-info/WHEEL":    "Wheel-Version: 1.0\nGenerator: bdist_wheel (0.43.0)\nRoot-Is-Purelib: true\nTag: py3-none-any\n",
+		"requests-2.32.3.dist-info/RECORD":   "",
+	} {
+		member, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = member.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func buildSdist(t *testing.T, big []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+
+	for _, member := range []struct {
+		name string
+		data []byte
+	}{
+		{"requests-2.32.3/PKG-INFO", []byte(requestsMetadata)},
+		{"requests-2.32.3/tests/PKG-INFO", []byte("broken")},
+		{"requests-2.32.3/blob.bin", big},
+	} {
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: member.name, Mode: 0o644, Size: int64(len(member.data)), Typeflag: tar.TypeReg}))
+		_, err := w.Write(member.data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// newFileServer отдаёт файл и считает переданные байты; ranges=false имитирует сервер без поддержки Range
+func newFileServer(t *testing.T, data []byte, ranges bool) (*httptest.Server, *atomic.Int64) {
+	var sent atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter := &countingWriter{ResponseWriter: w, sent: &sent}
+		if !ranges {
+			_, _ = counter.Write(data)
+			return
+		}
+		http.ServeContent(counter, r, "", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server, &sent
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	sent *atomic.Int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.sent.Add(int64(len(p)))
+	return w.ResponseWriter.Write(p)
+}
+
+func TestParseCoreMetadata(t *testing.T) {
+	metadata, err := distmeta.ParseCoreMetadata([]byte(requestsMetadata))
+	require.NoError(t, err)
+
+	assert.Equal(t, &distmeta.CoreMetadata{
+		MetadataVersion: "2.1",
+		Name:            "requests",
+		Version:         "2.32.3",
+		Summary:         "Python HTTP for Humans.",
+		License:         "Apache-2.0\nCopyright 2019 Kenneth Reitz",
+		Classifiers: []string{
+			"Development Status :: 5 - Production/Stable",
+			"License :: OSI Approved :: Apache Software License",
+		},
+		RequiresPython: ">=3.8",
+		RequiresDist:   []string{"charset-normalizer<4,>=2", "idna<4,>=2.5", `PySocks!=1.5.7,>=1.5.6; extra == "socks"`},
+		ProvidesExtra:  []string{"socks"},
+	}, metadata)
+
+	_, err = distmeta.ParseCoreMetadata([]byte("Metadata-Version: 2.1\nName: requests\n"))
+	assert.ErrorIs(t, err, distmeta.ErrInvalidMetadata)
+}
+
+func TestReadWheel(t *testing.T) {
+	data := buildWheel(t, nil)
+
+	wheel, err := distmeta.ReadWheel(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, "requests", wheel.Metadata.Name)
+	assert.Equal(t, &distmeta.WheelInfo{
+		WheelVersion:  "1.0",
+		Generator:     "bdist_wheel (0.43.0)",
+		RootIsPurelib: true,
+		Tags:          []string{"py3-none-any"},
+	}, wheel.Info)
+
+	var empty bytes.Buffer
+	w := zip.NewWriter(&empty)
+	_, err = w.Create("requests/__init__.py")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	_, err = distmeta.ReadWheel(bytes.NewReader(empty.Bytes()), int64(empty.Len()))
+	assert.ErrorIs(t, err, distmeta.ErrMetadataNotFound)
+}
+
+func TestReadSdist(t *testing.T) {
+	data := buildSdist(t, []byte("tail"))
+
+	metadata, err := distmeta.ReadSdist(bytes.NewReader(data), int64(len(data)), "requests-2.32.3.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "2.32.3", metadata.Version)
+	assert.Equal(t, ">=3.8", metadata.RequiresPython)
+}
+
+func TestExtract_ReadsOnlyNeededRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+	}{
+		{"wheel", "requests-2.32.3-py3-none-any.whl", buildWheel(t, payload(t, 4<<20))},
+		{"sdist", "requests-2.32.3.tar.gz", buildSdist(t, payload(t, 4<<20))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, sent := newFileServer(t, tt.data, true)
+
+			metadata, err := distmeta.Extract(context.Background(), server.Client(), server.URL, nil, tt.filename)
+			require.NoError(t, err)
+			assert.Equal(t, "requests", metadata.Name)
+			assert.Equal(t, []string{"socks"}, metadata.ProvidesExtra)
+
+			assert.Less(t, sent.Load(), int64(len(tt.data)/8))
+		})
+	}
+}
+
+func TestExtract_WithoutRangeSupport(t *testing.T) {
+	data := buildWheel(t, payload(t, 256<<10))
+	server, sent := newFileServer(t, data, false)
+
+	metadata, err := distmeta.Extract(context.Background(), server.Client(), server.URL, nil, "requests-2.32.3-py3-none-any.whl")
+	require.NoError(t, err)
+	assert.Equal(t, "2.32.3", metadata.Version)
+	assert.Equal(t, int64(len(data)), sent.Load())
+}
+
+func TestRangeReader_ReusesLoadedSegments(t *testing.T) {
+	data := payload(t, 1<<20)
+	server, _ := newFileServer(t, data, true)
+
+	r, err := distmeta.NewRangeReader(context.Background(), server.Client(), server.URL, nil, 4096)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), r.Size())
+	assert.Equal(t, 1, r.Requests())
+
+	buf := make([]byte, 100)
+	n, err := r.ReadAt(buf, int64(len(data))-100)
+	require.NoError(t, err)
+	assert.Equal(t, data[len(data)-100:], buf[:n])
+	assert.Equal(t, 1, r.Requests())
+
+	n, err = r.ReadAt(buf, 10)
+	require.NoError(t, err)
+	assert.Equal(t, data[10:110], buf[:n])
+	_, err = r.ReadAt(buf, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 2, r.Requests())
+
+	n, err = r.ReadAt(buf, int64(len(data))-10)
+	assert.Equal(t, 10, n)
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,109 @@
+// Package distmeta извлекает core metadata из дистрибутивов: METADATA и WHEEL
+// из wheel, PKG-INFO из sdist. Архивы читаются через io.ReaderAt, поэтому
+// с RangeReader по сети скачиваются только нужные части файла
+package distmeta
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrMetadataNotFound = errors.New("core metadata not found in distribution")
+	ErrInvalidMetadata  = errors.New("invalid core metadata")
+)
+
+// CoreMetadata — поля core metadata, общие для METADATA и PKG-INFO
+type CoreMetadata struct {
+	MetadataVersion string
+	Name            string
+	Version         string
+	Summary         string
+	// License — свободный текст лицензии, LicenseExpression — SPDX выражение из Metadata 2.4
+	License           string
+	LicenseExpression string
+	Classifiers       []string
+	RequiresPython    string
+	RequiresDist      []string
+	ProvidesExtra     []string
+}
+
+// ParseCoreMetadata разбирает core metadata в формате заголовков письма.
+// Читаются только заголовки до первой пустой строки, описание пропускается
+func ParseCoreMetadata(data []byte) (*CoreMetadata, error) {
+	var (
+		metadata CoreMetadata
+		key      string
+		lines    []string
+	)
+
+	flush := func() {
+		if key == "" {
+			return
+		}
+		v := strings.Join(lines, " ")
+		switch strings.ToLower(key) {
+		case "metadata-version":
+			metadata.MetadataVersion = v
+		case "name":
+			metadata.Name = v
+		case "version":
+			metadata.Version = v
+		case "summary":
+			metadata.Summary = v
+		case "license":
+			// Текст лицензии часто многострочный, переводы строк сохраняются
+			metadata.License = strings.Join(lines, "\n")
+		case "license-expression":
+			metadata.LicenseExpression = v
+		case "classifier":
+			metadata.Classifiers = append(metadata.Classifiers, v)
+		case "requires-python":
+			metadata.RequiresPython = v
+		case "requires-dist":
+			metadata.RequiresDist = append(metadata.RequiresDist, v)
+		case "provides-extra":
+			metadata.ProvidesExtra = append(metadata.ProvidesExtra, v)
+		}
+		key, lines = "", nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+
+		// Строка, начинающаяся с пробела, продолжает предыдущий заголовок
+		if line[0] == ' ' || line[0] == '\t' {
+			if key != "" {
+				lines = append(lines, strings.TrimSpace(line))
+			}
+			continue
+		}
+
+		flush()
+		name, rest, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(name)
+		if value := strings.TrimSpace(rest); value != "" {
+			lines = append(lines, value)
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMetadata, err)
+	}
+	if metadata.Name == "" || metadata.Version == "" {
+		return nil, fmt.Errorf("%w: Name and Version are required", ErrInvalidMetadata)
+	}
+	return &metadata, nil
+}
@@ -0,0 +1,231 @@
+package distmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultChunkSize — минимальный размер одного Range запроса. Его хватает,
+// чтобы первым же запросом получить центральный каталог zip большинства wheel
+const DefaultChunkSize = 64 << 10
+
+var ErrRangeNotSupported = errors.New("server does not support range requests")
+
+// RangeReader — удалённый файл как io.ReaderAt. Каждое чтение незагруженного
+// участка превращается в запрос Range, загруженные участки переиспользуются
+type RangeReader struct {
+	ctx       context.Context
+	client    *http.Client
+	url       string
+	header    http.Header
+	size      int64
+	chunkSize int64
+
+	mu       sync.Mutex
+	segments []segment
+	requests int
+}
+
+type segment struct {
+	offset int64
+	data   []byte
+}
+
+// NewRangeReader узнаёт размер файла, сразу загружая его последние chunkSize байт,
+// с которых начинается чтение zip. Если сервер игнорирует Range и отдаёт файл
+// целиком, он сохраняется в памяти и дальнейших запросов нет.
+// header добавляется к каждому запросу, например для авторизации
+func NewRangeReader(ctx context.Context, client *http.Client, url string, header http.Header, chunkSize int64) (*RangeReader, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	r := &RangeReader{
+		ctx:       ctx,
+		client:    client,
+		url:       url,
+		header:    header,
+		chunkSize: chunkSize,
+	}
+
+	resp, err := r.do("bytes=-" + strconv.FormatInt(chunkSize, 10))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", url, err)
+		}
+		r.size = int64(len(data))
+		r.segments = []segment{{offset: 0, data: data}}
+
+	case http.StatusPartialContent:
+		start, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", url, err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", url, err)
+		}
+		r.size = size
+		r.segments = []segment{{offset: start, data: data}}
+
+	default:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	return r, nil
+}
+
+// Size возвращает размер файла
+func (r *RangeReader) Size() int64 {
+	return r.size
+}
+
+// Requests возвращает число выполненных HTTP запросов
+func (r *RangeReader) Requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.requests
+}
+
+// ReadAt реализует io.ReaderAt
+func (r *RangeReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset %d", off)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= r.size {
+			return n, io.EOF
+		}
+
+		seg, ok := r.segmentAt(pos)
+		if !ok {
+			end := min(pos+max(int64(len(p)-n), r.chunkSize), r.size)
+			var err error
+			if seg, err = r.fetch(pos, end); err != nil {
+				return n, err
+			}
+		}
+		n += copy(p[n:], seg.data[pos-seg.offset:])
+	}
+	return n, nil
+}
+
+func (r *RangeReader) segmentAt(pos int64) (segment, bool) {
+	for _, seg := range r.segments {
+		if pos >= seg.offset && pos < seg.offset+int64(len(seg.data)) {
+			return seg, true
+		}
+	}
+	return segment{}, false
+}
+
+// fetch загружает участок [start, end)
+func (r *RangeReader) fetch(start int64, end int64) (segment, error) {
+	resp, err := r.do(fmt.Sprintf("bytes=%d-%d", start, end-1))
+	if err != nil {
+		return segment{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return segment{}, fmt.Errorf("%w: %s returned %s", ErrRangeNotSupported, r.url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, end-start))
+	if err != nil {
+		return segment{}, fmt.Errorf("failed to download %s: %w", r.url, err)
+	}
+	if len(data) == 0 {
+		return segment{}, io.ErrUnexpectedEOF
+	}
+
+	seg := segment{offset: start, data: data}
+	r.segments = append(r.segments, seg)
+	return seg, nil
+}
+
+func (r *RangeReader) do(rangeHeader string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", r.url, err)
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Range", rangeHeader)
+	// Сжатие в транспорте сделало бы смещения бессмысленными
+	req.Header.Set("Accept-Encoding", "identity")
+
+	r.requests++
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", r.url, err)
+	}
+	return resp, nil
+}
+
+// parseContentRange разбирает "bytes <start>-<end>/<size>"
+func parseContentRange(value string) (int64, int64, error) {
+	spec, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+	}
+	span, total, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+	}
+	startValue, _, _ := strings.Cut(span, "-")
+
+	start, err := strconv.ParseInt(startValue, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+	}
+	size, err := strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q: unknown size", value)
+	}
+	return start, size, nil
+}
+
+// Extract загружает core metadata wheel или sdist по адресу, читая только нужные части архива
+func Extract(ctx context.Context, client *http.Client, url string, header http.Header, filename string) (*CoreMetadata, error) {
+	r, err := NewRangeReader(ctx, client, url, header, DefaultChunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(filename, ".whl") {
+		wheel, err := ReadWheel(r, r.Size())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		return wheel.Metadata, nil
+	}
+
+	metadata, err := ReadSdist(r, r.Size(), filename)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,90 @@
+package distmeta
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ReadSdist читает PKG-INFO из корневого каталога sdist: <name>-<version>/PKG-INFO.
+// zip читается выборочно, tar — потоком до нужного файла, хвост архива не загружается
+func ReadSdist(r io.ReaderAt, size int64, filename string) (*CoreMetadata, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch {
+	case strings.HasSuffix(filename, ".zip"):
+		data, err = zipPKGInfo(r, size)
+	case strings.HasSuffix(filename, ".tar.xz"):
+		return nil, fmt.Errorf("xz compressed sdist %s is not supported", filename)
+	default:
+		data, err = tarPKGInfo(io.NewSectionReader(r, 0, size))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseCoreMetadata(data)
+}
+
+func isPKGInfo(name string) bool {
+	dir, file := path.Split(strings.TrimPrefix(name, "./"))
+	return file == "PKG-INFO" && strings.Count(dir, "/") == 1
+}
+
+func zipPKGInfo(r io.ReaderAt, size int64) ([]byte, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sdist archive: %w", err)
+	}
+	for _, f := range archive.File {
+		if isPKGInfo(f.Name) {
+			return readZipMember(archive, f.Name)
+		}
+	}
+	return nil, fmt.Errorf("%w: no PKG-INFO in sdist", ErrMetadataNotFound)
+}
+
+func tarPKGInfo(r io.Reader) ([]byte, error) {
+	buffered := bufio.NewReader(r)
+	magic, _ := buffered.Peek(3)
+
+	var stream io.Reader = buffered
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip archive: %w", err)
+		}
+		defer gz.Close()
+		stream = gz
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		stream = bzip2.NewReader(buffered)
+	}
+
+	archive := tar.NewReader(stream)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: no PKG-INFO in sdist", ErrMetadataNotFound)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid sdist archive: %w", err)
+		}
+		if header.Typeflag == tar.TypeReg && isPKGInfo(header.Name) {
+			data, err := io.ReadAll(io.LimitReader(archive, maxMemberSize))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+			}
+			return data, nil
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package distmeta
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// maxMemberSize ограничивает размер читаемого файла метаданных внутри архива
+const maxMemberSize = 16 << 20
+
+// WheelInfo — содержимое файла WHEEL из каталога .dist-info
+type WheelInfo struct {
+	WheelVersion  string
+	Generator     string
+	RootIsPurelib bool
+	Tags          []string
+	Build         string
+}
+
+// Wheel — метаданные, извлечённые из wheel
+type Wheel struct {
+	Metadata *CoreMetadata
+	Info     *WheelInfo
+}
+
+// ReadWheel читает METADATA и WHEEL из каталога <name>-<version>.dist-info.
+// zip читается с конца: центральный каталог и два нужных файла, остальное не загружается
+func ReadWheel(r io.ReaderAt, size int64) (*Wheel, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wheel archive: %w", err)
+	}
+
+	var distInfo string
+	for _, f := range archive.File {
+		dir, file := path.Split(f.Name)
+		if file == "METADATA" && strings.Count(dir, "/") == 1 && strings.HasSuffix(dir, ".dist-info/") {
+			distInfo = dir
+			break
+		}
+	}
+	if distInfo == "" {
+		return nil, fmt.Errorf("%w: no .dist-info/METADATA in wheel", ErrMetadataNotFound)
+	}
+
+	data, err := readZipMember(archive, distInfo+"METADATA")
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := ParseCoreMetadata(data)
+	if err != nil {
+		return nil, err
+	}
+
+	wheel := &Wheel{Metadata: metadata}
+	if data, err := readZipMember(archive, distInfo+"WHEEL"); err == nil {
+		wheel.Info = parseWheelInfo(data)
+	}
+	return wheel, nil
+}
+
+func parseWheelInfo(data []byte) *WheelInfo {
+	var info WheelInfo
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimRight(line, "\r"), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "wheel-version":
+			info.WheelVersion = value
+		case "generator":
+			info.Generator = value
+		case "root-is-purelib":
+			info.RootIsPurelib = strings.EqualFold(value, "true")
+		case "tag":
+			info.Tags = append(info.Tags, value)
+		case "build":
+			info.Build = value
+		}
+	}
+	return &info
+}
+
+func readZipMember(archive *zip.Reader, name string) ([]byte, error) {
+	for _, f := range archive.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		defer rc.Close()
+
+		data, err := io.ReadAll(io.LimitReader(rc, maxMemberSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrMetadataNotFound, name)
+}
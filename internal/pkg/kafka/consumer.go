@@ -63,9 +63,17 @@ func (
 			Headers:   h.convertHeaders(message.Headers),
 			Partition: message.Partition,
 			Offset:    message.Offset,
+			Timestamp: message.Timestamp,
 		}
 
-		if err := h.handler(context.Background(), msg); err != nil {
+		if err := h.handler(session.Context(), msg); err != nil {
+			// Сессия закрывается при ребалансировке или остановке: не подтверждаем сообщение,
+			// чтобы его получил следующий владелец партиции
+			if session.Context().Err() != nil {
+				return nil
+			}
+			// Иначе сообщение не может быть обработано, его пропускаем. Временные ошибки
+			// handler должен повторять сам, иначе следующий MarkMessage подтвердит и это сообщение
 			log.Printf("Error handling message: %v", err)
 			continue
 		}
@@ -96,7 +104,20 @@ func (
 		if err := c.consumer.Consume(ctx, topics, consumerHandler); err != nil {
 			return fmt.Errorf("error from consumer: %w", err)
 		}
+
+		// Consume возвращается при каждой ребалансировке, выходим только по отмене контекста
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// ParseInitialOffset переводит строковое значение из конфигурации в offset sarama
+func ParseInitialOffset(offset string) int64 {
+	if offset == "earliest" || offset == "oldest" {
+		return sarama.OffsetOldest
 	}
+	return sarama.OffsetNewest
 }
 
 func (c *BaseConsumer) Close() error {
@@ -2,6 +2,7 @@ package kafka
 
 import (
 	"context"
+	"time"
 )
 
 type Producer interface {
@@ -24,6 +25,7 @@ type Message struct {
 	Headers   map[string]string
 	Partition int32
 	Offset    int64
+	Timestamp time.Time
 }
 
 type Consumer interface {
@@ -0,0 +1,109 @@
+package lockfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// pythonJSON сериализует значение так же, как json.dumps(value, sort_keys=True)
+// в Python: от этого зависят content-hash в poetry.lock, pdm.lock и Pipfile.lock
+func pythonJSON(value any, compact bool) string {
+	itemSep, keySep := ", ", ": "
+	if compact {
+		itemSep, keySep = ",", ":"
+	}
+
+	var b strings.Builder
+	var write func(v any)
+	write = func(v any) {
+		switch v := v.(type) {
+		case nil:
+			b.WriteString("null")
+		case bool:
+			b.WriteString(strconv.FormatBool(v))
+		case int64:
+			b.WriteString(strconv.FormatInt(v, 10))
+		case float64:
+			s := strconv.FormatFloat(v, 'g', -1, 64)
+			if !strings.ContainsAny(s, ".eEn") {
+				s += ".0"
+			}
+			b.WriteString(s)
+		case string:
+			writePythonString(&b, v)
+		case []any:
+			b.WriteString("[")
+			for i, item := range v {
+				if i > 0 {
+					b.WriteString(itemSep)
+				}
+				write(item)
+			}
+			b.WriteString("]")
+		case []map[string]any:
+			items := make([]any, len(v))
+			for i, item := range v {
+				items[i] = item
+			}
+			write(items)
+		case map[string]any:
+			b.WriteString("{")
+			for i, key := range slices.Sorted(maps.Keys(v)) {
+				if i > 0 {
+					b.WriteString(itemSep)
+				}
+				writePythonString(&b, key)
+				b.WriteString(keySep)
+				write(v[key])
+			}
+			b.WriteString("}")
+		default:
+			writePythonString(&b, fmt.Sprint(v))
+		}
+	}
+	write(value)
+
+	return b.String()
+}
+
+// writePythonString экранирует строку как json.dumps с ensure_ascii=True
+func writePythonString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\b':
+			b.WriteString(`\b`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r < 0x20 || (r > 0x7e && r <= 0xffff):
+			fmt.Fprintf(b, `\u%04x`, r)
+		case r > 0xffff:
+			high, low := utf16.EncodeRune(r)
+			fmt.Fprintf(b, `\u%04x\u%04x`, high, low)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
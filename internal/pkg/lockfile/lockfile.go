@@ -0,0 +1,218 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/requirements"
+)
+
+// Format — формат lock-файла
+type Format string
+
+const (
+	FormatPoetry Format = "poetry.lock"
+	FormatPipenv Format = "Pipfile.lock"
+	FormatUV     Format = "uv.lock"
+	FormatPDM    Format = "pdm.lock"
+)
+
+var ErrUnknownFormat = errors.New("unknown lock file format")
+
+var formatAliases = map[string]Format{
+	"poetry":       FormatPoetry,
+	"poetry.lock":  FormatPoetry,
+	"pipenv":       FormatPipenv,
+	"pipfile.lock": FormatPipenv,
+	"uv":           FormatUV,
+	"uv.lock":      FormatUV,
+	"pdm":          FormatPDM,
+	"pdm.lock":     FormatPDM,
+}
+
+// ParseFormat определяет формат по имени инструмента или имени файла
+func ParseFormat(name string) (Format, error) {
+	format, ok := formatAliases[strings.ToLower(path.Base(name))]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, name)
+	}
+	return format, nil
+}
+
+// Lock — зафиксированный список пакетов из lock-файла
+type Lock struct {
+	Format         Format
+	RequiresPython string // спецификатор PEP 440
+	// Packages закреплены точной версией (==) или прямой ссылкой
+	Packages []*requirements.Requirement
+	Warnings []string
+
+	contentHash string
+	// rootDependencies — зависимости корневого проекта из uv.lock, у которого нет content-hash
+	rootDependencies []string
+}
+
+// ParseError — ошибка разбора lock-файла
+type ParseError struct {
+	Field   string
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Parse разбирает lock-файл указанного формата
+func Parse(format Format, data []byte) (*Lock, error) {
+	var (
+		lock *Lock
+		err  error
+	)
+
+	switch format {
+	case FormatPoetry:
+		lock, err = parsePoetry(data)
+	case FormatPipenv:
+		lock, err = parsePipenv(data)
+	case FormatUV:
+		lock, err = parseUV(data)
+	case FormatPDM:
+		lock, err = parsePDM(data)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lock.Format = format
+	return lock, nil
+}
+
+// CheckManifest сверяет lock-файл с манифестом, из которого он был создан
+// (pyproject.toml или Pipfile), и возвращает предупреждение, если lock устарел
+func (l *Lock) CheckManifest(manifest []byte) (string, error) {
+	var (
+		expected string
+		err      error
+	)
+
+	switch l.Format {
+	case FormatPoetry:
+		expected, err = poetryContentHash(manifest)
+	case FormatPDM:
+		expected, err = pdmContentHash(manifest)
+	case FormatPipenv:
+		expected, err = pipfileHash(manifest)
+	case FormatUV:
+		return uvCheckManifest(l.rootDependencies, manifest)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if l.contentHash == "" {
+		return fmt.Sprintf("%s has no content hash, freshness was not verified", l.Format), nil
+	}
+	if expected != l.contentHash {
+		return fmt.Sprintf("%s content hash %s does not match the manifest (%s), the lock file is stale",
+			l.Format, shortHash(l.contentHash), shortHash(expected)), nil
+	}
+	return "", nil
+}
+
+func shortHash(hash string) string {
+	return hash[:min(12, len(hash))]
+}
+
+// lockBuilder собирает пакеты, пропуская повторы одного и того же имени
+type lockBuilder struct {
+	lock *Lock
+	seen map[string]*requirements.Requirement
+}
+
+func newLockBuilder() *lockBuilder {
+	return &lockBuilder{
+		lock: &Lock{},
+		seen: make(map[string]*requirements.Requirement),
+	}
+}
+
+func (b *lockBuilder) warn(format string, args ...any) {
+	b.lock.Warnings = append(b.lock.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (b *lockBuilder) add(field string, req *requirements.Requirement) error {
+	if _, err := requirements.ParseRequirement(req.Name); err != nil {
+		return &ParseError{Field: field, Message: fmt.Sprintf("invalid package name %q", req.Name)}
+	}
+	if req.URL == "" && req.Specifier == "" {
+		return &ParseError{Field: field, Message: fmt.Sprintf("package %q has no pinned version", req.Name)}
+	}
+
+	// Один пакет может быть закреплён несколько раз под разными маркерами
+	key := pep503.Normalize(req.Name) + ";" + req.Marker
+	if existing, ok := b.seen[key]; ok {
+		if existing.Specifier != req.Specifier || existing.URL != req.URL {
+			b.warn("%s: %s is locked more than once, keeping %s", field, req.Name, existing.String())
+			return nil
+		}
+		for _, extra := range req.Extras {
+			if !slices.Contains(existing.Extras, extra) {
+				existing.Extras = append(existing.Extras, extra)
+			}
+		}
+		return nil
+	}
+
+	b.seen[key] = req
+	b.lock.Packages = append(b.lock.Packages, req)
+	return nil
+}
+
+func pinned(version string) string {
+	if version == "" {
+		return ""
+	}
+	return "==" + version
+}
+
+func gitURL(repository string, ref string) string {
+	url := repository
+	if !strings.HasPrefix(url, "git+") {
+		url = "git+" + url
+	}
+	if ref != "" {
+		url += "@" + ref
+	}
+	return url
+}
+
+// markerUnion объединяет маркеры через or; пустой маркер означает «всегда»
+func markerUnion(markers []string) string {
+	var unique []string
+	for _, marker := range markers {
+		if marker == "" {
+			return ""
+		}
+		if !slices.Contains(unique, marker) {
+			unique = append(unique, marker)
+		}
+	}
+	if len(unique) == 1 {
+		return unique[0]
+	}
+	for i, marker := range unique {
+		unique[i] = "(" + marker + ")"
+	}
+	return strings.Join(unique, " or ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -0,0 +1,132 @@
+package lockfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/lockfile"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/requirements"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return data
+}
+
+func parseTestdata(t *testing.T, name string) *lockfile.Lock {
+	t.Helper()
+	format, err := lockfile.ParseFormat(name)
+	require.NoError(t, err)
+	lock, err := lockfile.Parse(format, readTestdata(t, name))
+	require.NoError(t, err)
+	return lock
+}
+
+func byName(lock *lockfile.Lock) map[string]*requirements.Requirement {
+	packages := make(map[string]*requirements.Requirement)
+	for _, pkg := range lock.Packages {
+		packages[pkg.Name] = pkg
+	}
+	return packages
+}
+
+func TestParse_Poetry(t *testing.T) {
+	lock := parseTestdata(t, "poetry.lock")
+
+	assert.Equal(t, lockfile.FormatPoetry, lock.Format)
+	assert.Equal(t, ">=3.9,<4", lock.RequiresPython)
+	require.Len(t, lock.Packages, 4)
+
+	packages := byName(lock)
+	assert.Equal(t, "==0.4.6", packages["colorama"].Specifier)
+	assert.Len(t, packages["colorama"].Hashes, 2)
+	assert.Equal(t, `python_version < "3.11"`, packages["tomli"].Marker)
+	assert.Equal(t, "git+https://github.com/sdispater/pendulum.git@7b6e2c1a", packages["pendulum"].URL)
+	assert.Empty(t, packages["pendulum"].Specifier)
+
+	require.Len(t, lock.Warnings, 1)
+	assert.Contains(t, lock.Warnings[0], "local-lib")
+
+	warning, err := lock.CheckManifest(readTestdata(t, "poetry.pyproject.toml"))
+	require.NoError(t, err)
+	assert.Empty(t, warning)
+
+	warning, err = lock.CheckManifest([]byte("[tool.poetry.dependencies]\npython = \"^3.9\"\n"))
+	require.NoError(t, err)
+	assert.Contains(t, warning, "stale")
+}
+
+func TestParse_Pipenv(t *testing.T) {
+	lock := parseTestdata(t, "Pipfile.lock")
+
+	assert.Equal(t, "==3.11.*", lock.RequiresPython)
+	require.Len(t, lock.Packages, 3)
+
+	packages := byName(lock)
+	assert.Equal(t, "==2.32.3", packages["requests"].Specifier)
+	assert.Equal(t, "python_version >= '3.8'", packages["requests"].Marker)
+	assert.Equal(t, "git+https://github.com/encode/httpx.git@326b9431c761e1ef1e00b9f760d1f654c8db48c6", packages["httpx"].URL)
+	assert.Equal(t, "==8.3.3", packages["pytest"].Specifier)
+
+	warning, err := lock.CheckManifest(readTestdata(t, "Pipfile"))
+	require.NoError(t, err)
+	assert.Empty(t, warning)
+}
+
+func TestParse_PDM(t *testing.T) {
+	lock := parseTestdata(t, "pdm.lock")
+
+	require.Len(t, lock.Packages, 3)
+	packages := byName(lock)
+	assert.Equal(t, []string{"socks"}, packages["requests"].Extras)
+	assert.Equal(t, `python_version < "3.11"`, packages["exceptiongroup"].Marker)
+
+	warning, err := lock.CheckManifest(readTestdata(t, "pdm.pyproject.toml"))
+	require.NoError(t, err)
+	assert.Empty(t, warning)
+}
+
+func TestParse_UV(t *testing.T) {
+	lock := parseTestdata(t, "uv.lock")
+
+	assert.Equal(t, ">=3.12", lock.RequiresPython)
+	require.Len(t, lock.Packages, 4, "the editable project itself is not a locked package")
+
+	packages := byName(lock)
+	assert.Empty(t, packages["click"].Marker)
+	assert.Len(t, packages["click"].Hashes, 2)
+	assert.Equal(t, "platform_system == 'Windows'", packages["colorama"].Marker)
+	assert.Equal(t, "extra == 'docs'", packages["sphinx"].Marker)
+	assert.Equal(t, "git+https://github.com/encode/httpx@326b9431c761e1ef1e00b9f760d1f654c8db48c6", packages["httpx"].URL)
+
+	warning, err := lock.CheckManifest(readTestdata(t, "uv.pyproject.toml"))
+	require.NoError(t, err)
+	assert.Empty(t, warning)
+
+	warning, err = lock.CheckManifest([]byte("[project]\nname = \"demo\"\ndependencies = [\"click>=8.2\"]\n"))
+	require.NoError(t, err)
+	assert.Contains(t, warning, "not locked: click>=8.2")
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := lockfile.ParseFormat("requirements.txt")
+	assert.ErrorIs(t, err, lockfile.ErrUnknownFormat)
+
+	_, err = lockfile.Parse(lockfile.FormatPoetry, []byte("[[package]]\nname = \"x\"\n"))
+	var parseErr *lockfile.ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "package[0]", parseErr.Field)
+
+	_, err = lockfile.Parse(lockfile.FormatPipenv, []byte(`{"default": {"x": {"version": ">=1"}}}`))
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "default.x", parseErr.Field)
+
+	_, err = lockfile.Parse(lockfile.FormatUV, []byte("version = "))
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "line 1", parseErr.Field)
+}
@@ -0,0 +1,157 @@
+package lockfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/requirements"
+)
+
+type pipenvMeta struct {
+	Hash struct {
+		SHA256 string `json:"sha256"`
+	} `json:"hash"`
+	Requires struct {
+		PythonVersion     string `json:"python_version"`
+		PythonFullVersion string `json:"python_full_version"`
+	} `json:"requires"`
+}
+
+type pipenvEntry struct {
+	Version      string   `json:"version"`
+	Hashes       []string `json:"hashes"`
+	Markers      string   `json:"markers"`
+	Extras       []string `json:"extras"`
+	Git          string   `json:"git"`
+	Ref          string   `json:"ref"`
+	File         string   `json:"file"`
+	Path         string   `json:"path"`
+	Subdirectory string   `json:"subdirectory"`
+}
+
+// pipfileSections — разделы Pipfile, которые не являются категориями пакетов
+var pipfileSections = []string{"source", "packages", "dev-packages", "requires", "scripts", "pipfile", "pipenv", "default", "develop"}
+
+func parsePipenv(data []byte) (*Lock, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, &ParseError{Field: "document", Message: err.Error()}
+	}
+
+	var meta pipenvMeta
+	if raw, ok := doc["_meta"]; ok {
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return nil, &ParseError{Field: "_meta", Message: err.Error()}
+		}
+	}
+
+	b := newLockBuilder()
+	b.lock.contentHash = meta.Hash.SHA256
+
+	switch {
+	case meta.Requires.PythonFullVersion != "":
+		b.lock.RequiresPython = "==" + meta.Requires.PythonFullVersion
+	case meta.Requires.PythonVersion != "":
+		b.lock.RequiresPython = "==" + meta.Requires.PythonVersion + ".*"
+	}
+
+	// default и develop идут первыми, затем пользовательские категории
+	categories := []string{"default", "develop"}
+	for _, category := range sortedKeys(doc) {
+		if category != "_meta" && !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+
+	for _, category := range categories {
+		raw, ok := doc[category]
+		if !ok {
+			continue
+		}
+
+		var entries map[string]pipenvEntry
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, &ParseError{Field: category, Message: err.Error()}
+		}
+
+		for _, name := range sortedKeys(entries) {
+			field := fmt.Sprintf("%s.%s", category, name)
+			entry := entries[name]
+
+			req := &requirements.Requirement{
+				Name:      name,
+				Specifier: entry.Version,
+				Extras:    entry.Extras,
+				Marker:    entry.Markers,
+				Hashes:    entry.Hashes,
+			}
+
+			switch {
+			case entry.Git != "":
+				req.URL = gitURL(entry.Git, entry.Ref)
+				if entry.Subdirectory != "" {
+					req.URL += "#subdirectory=" + entry.Subdirectory
+				}
+				req.Specifier = ""
+			case entry.File != "":
+				req.URL = entry.File
+				req.Specifier = ""
+			case entry.Path != "":
+				b.warn("%s: local path dependency is skipped", field)
+				continue
+			}
+
+			if req.Specifier != "" && !strings.HasPrefix(req.Specifier, "==") {
+				return nil, &ParseError{Field: field, Message: fmt.Sprintf("version %q is not pinned", req.Specifier)}
+			}
+
+			if err := b.add(field, req); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return b.lock, nil
+}
+
+// pipfileHash повторяет Pipfile.get_hash из plette, которым пользуется pipenv
+func pipfileHash(manifest []byte) (string, error) {
+	var doc map[string]any
+	if err := decodeTOML(manifest, &doc); err != nil {
+		return "", err
+	}
+
+	sources, ok := doc["source"]
+	if !ok {
+		sources = []any{map[string]any{
+			"name":       "pypi",
+			"url":        "https://pypi.org/simple",
+			"verify_ssl": true,
+		}}
+	}
+
+	data := map[string]any{
+		"_meta": map[string]any{
+			"sources":  sources,
+			"requires": valueOr(doc["requires"], map[string]any{}),
+		},
+		"default": valueOr(doc["packages"], map[string]any{}),
+		"develop": valueOr(doc["dev-packages"], map[string]any{}),
+	}
+	for category, values := range doc {
+		if !slices.Contains(pipfileSections, category) {
+			data[category] = values
+		}
+	}
+
+	return sha256Hex(pythonJSON(data, true)), nil
+}
+
+func valueOr(value any, fallback any) any {
+	if value == nil {
+		return fallback
+	}
+	return value
+}
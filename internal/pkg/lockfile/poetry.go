@@ -0,0 +1,186 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pyproject"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/requirements"
+	"github.com/pelletier/go-toml/v2"
+)
+
+type lockedFile struct {
+	File string `toml:"file"`
+	Hash string `toml:"hash"`
+}
+
+type poetryLock struct {
+	Package []struct {
+		Name    string       `toml:"name"`
+		Version string       `toml:"version"`
+		Markers any          `toml:"markers"`
+		Files   []lockedFile `toml:"files"`
+		Source  *struct {
+			Type              string `toml:"type"`
+			URL               string `toml:"url"`
+			Reference         string `toml:"reference"`
+			ResolvedReference string `toml:"resolved_reference"`
+			Subdirectory      string `toml:"subdirectory"`
+		} `toml:"source"`
+	} `toml:"package"`
+	Metadata struct {
+		PythonVersions string `toml:"python-versions"`
+		ContentHash    string `toml:"content-hash"`
+		// В lock-version 1.x хеши файлов хранились отдельно от пакетов
+		Files map[string][]lockedFile `toml:"files"`
+	} `toml:"metadata"`
+}
+
+func parsePoetry(data []byte) (*Lock, error) {
+	var doc poetryLock
+	if err := decodeTOML(data, &doc); err != nil {
+		return nil, err
+	}
+
+	b := newLockBuilder()
+	b.lock.contentHash = doc.Metadata.ContentHash
+
+	if doc.Metadata.PythonVersions != "" {
+		specifier, err := pyproject.TranslatePoetryConstraint(doc.Metadata.PythonVersions)
+		if err != nil {
+			b.warn("metadata.python-versions: %s", err)
+		}
+		b.lock.RequiresPython = specifier
+	}
+
+	for i, pkg := range doc.Package {
+		field := fmt.Sprintf("package[%d]", i)
+		req := &requirements.Requirement{
+			Name:      pkg.Name,
+			Specifier: pinned(pkg.Version),
+			Marker:    poetryMarker(pkg.Markers),
+		}
+
+		if pkg.Source != nil {
+			switch pkg.Source.Type {
+			case "git":
+				req.URL = gitURL(pkg.Source.URL, firstNonEmpty(pkg.Source.ResolvedReference, pkg.Source.Reference))
+				if pkg.Source.Subdirectory != "" {
+					req.URL += "#subdirectory=" + pkg.Source.Subdirectory
+				}
+				req.Specifier = ""
+			case "url":
+				req.URL = pkg.Source.URL
+				req.Specifier = ""
+			case "directory", "file":
+				b.warn("%s: local %s dependency %s is skipped", field, pkg.Source.Type, pkg.Name)
+				continue
+			}
+		}
+
+		files := pkg.Files
+		if len(files) == 0 {
+			files = doc.Metadata.Files[pkg.Name]
+		}
+		for _, file := range files {
+			req.Hashes = append(req.Hashes, file.Hash)
+		}
+
+		if err := b.add(field, req); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.lock, nil
+}
+
+// poetryMarker поддерживает маркеры строкой и таблицей маркеров по группам
+func poetryMarker(markers any) string {
+	switch m := markers.(type) {
+	case string:
+		return m
+	case map[string]any:
+		var values []string
+		for _, group := range sortedKeys(m) {
+			value, _ := m[group].(string)
+			values = append(values, value)
+		}
+		return markerUnion(values)
+	default:
+		return ""
+	}
+}
+
+var (
+	poetryLegacyKeys    = []string{"dependencies", "source", "extras", "dev-dependencies"}
+	poetryRelevantKeys  = append(slices.Clone(poetryLegacyKeys), "group")
+	projectRelevantKeys = []string{"requires-python", "dependencies", "optional-dependencies"}
+)
+
+// poetryContentHash повторяет Locker._get_content_hash из Poetry
+func poetryContentHash(manifest []byte) (string, error) {
+	var doc map[string]any
+	if err := decodeTOML(manifest, &doc); err != nil {
+		return "", err
+	}
+
+	project := table(doc, "project")
+	poetry := table(table(doc, "tool"), "poetry")
+
+	relevantProject := make(map[string]any)
+	for _, key := range projectRelevantKeys {
+		if value, ok := project[key]; ok {
+			relevantProject[key] = value
+		}
+	}
+
+	relevantPoetry := make(map[string]any)
+	for _, key := range poetryRelevantKeys {
+		value, ok := poetry[key]
+		if !ok && (!slices.Contains(poetryLegacyKeys, key) || len(relevantProject) > 0) {
+			continue
+		}
+		relevantPoetry[key] = value
+	}
+
+	// Для совместимости Poetry кладёт [tool.poetry] на верхний уровень, если [project] пуст
+	relevant := relevantPoetry
+	if len(relevantProject) > 0 {
+		relevant = map[string]any{
+			"project": relevantProject,
+			"tool":    map[string]any{"poetry": relevantPoetry},
+		}
+	}
+	if groups, ok := doc["dependency-groups"]; ok {
+		relevant["dependency-groups"] = groups
+	}
+
+	return sha256Hex(pythonJSON(relevant, false)), nil
+}
+
+func decodeTOML(data []byte, v any) error {
+	if err := toml.Unmarshal(data, v); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			row, _ := decodeErr.Position()
+			return &ParseError{Field: fmt.Sprintf("line %d", row), Message: decodeErr.Error()}
+		}
+		return &ParseError{Field: "document", Message: err.Error()}
+	}
+	return nil
+}
+
+func table(doc map[string]any, key string) map[string]any {
+	value, _ := doc[key].(map[string]any)
+	return value
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,333 @@
+package lockfile
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pyproject"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/requirements"
+)
+
+// maxUVMarkers ограничивает число альтернативных маркеров пакета,
+// после которого пакет считается безусловным
+const maxUVMarkers = 8
+
+type uvDependency struct {
+	Name    string   `toml:"name"`
+	Version string   `toml:"version"`
+	Marker  string   `toml:"marker"`
+	Extra   []string `toml:"extra"`
+}
+
+type uvArtifact struct {
+	URL  string `toml:"url"`
+	Hash string `toml:"hash"`
+}
+
+type uvRequirement struct {
+	Name      string   `toml:"name"`
+	Extras    []string `toml:"extras"`
+	Specifier string   `toml:"specifier"`
+}
+
+type uvPackage struct {
+	Name    string `toml:"name"`
+	Version string `toml:"version"`
+	Source  struct {
+		Registry  string `toml:"registry"`
+		Git       string `toml:"git"`
+		URL       string `toml:"url"`
+		Path      string `toml:"path"`
+		Directory string `toml:"directory"`
+		Editable  string `toml:"editable"`
+		Virtual   string `toml:"virtual"`
+	} `toml:"source"`
+	Dependencies         []uvDependency            `toml:"dependencies"`
+	OptionalDependencies map[string][]uvDependency `toml:"optional-dependencies"`
+	DevDependencies      map[string][]uvDependency `toml:"dev-dependencies"`
+	Sdist                *uvArtifact               `toml:"sdist"`
+	Wheels               []uvArtifact              `toml:"wheels"`
+	Metadata             struct {
+		RequiresDist []uvRequirement `toml:"requires-dist"`
+	} `toml:"metadata"`
+}
+
+type uvLock struct {
+	RequiresPython string      `toml:"requires-python"`
+	Package        []uvPackage `toml:"package"`
+}
+
+// isRoot сообщает, что пакет — сам проект или участник workspace
+func (p *uvPackage) isRoot() bool {
+	return p.Source.Editable != "" || p.Source.Virtual != ""
+}
+
+// edges возвращает рёбра графа: основные зависимости, зависимости запрошенных extras
+// и dev-группы. Зависимости extras корневого проекта получают маркер extra == '...'
+func (p *uvPackage) edges(requestedExtras []string) []uvDependency {
+	edges := slices.Clone(p.Dependencies)
+
+	for _, extra := range sortedKeys(p.OptionalDependencies) {
+		if !p.isRoot() && !slices.Contains(requestedExtras, extra) {
+			continue
+		}
+		for _, dep := range p.OptionalDependencies[extra] {
+			if p.isRoot() {
+				dep.Marker = joinAnd(fmt.Sprintf("extra == '%s'", extra), dep.Marker)
+			}
+			edges = append(edges, dep)
+		}
+	}
+
+	for _, group := range sortedKeys(p.DevDependencies) {
+		edges = append(edges, p.DevDependencies[group]...)
+	}
+	return edges
+}
+
+func parseUV(data []byte) (*Lock, error) {
+	var doc uvLock
+	if err := decodeTOML(data, &doc); err != nil {
+		return nil, err
+	}
+
+	b := newLockBuilder()
+	if doc.RequiresPython != "" {
+		specifier, err := requirements.NormalizeSpecifier(doc.RequiresPython)
+		if err != nil {
+			return nil, &ParseError{Field: "requires-python", Message: err.Error()}
+		}
+		b.lock.RequiresPython = specifier
+	}
+
+	markers := uvMarkers(doc.Package)
+
+	for i, pkg := range doc.Package {
+		field := fmt.Sprintf("package[%d]", i)
+
+		if pkg.isRoot() {
+			for _, dep := range pkg.Metadata.RequiresDist {
+				b.lock.rootDependencies = append(b.lock.rootDependencies,
+					canonicalRequirement(dep.Name, dep.Extras, dep.Specifier))
+			}
+			continue
+		}
+
+		req := &requirements.Requirement{
+			Name:      pkg.Name,
+			Specifier: pinned(pkg.Version),
+			Marker:    markers[uvKey(pkg.Name, pkg.Version)],
+		}
+
+		switch {
+		case pkg.Source.Git != "":
+			req.URL = uvGitURL(pkg.Source.Git)
+			req.Specifier = ""
+		case pkg.Source.URL != "":
+			req.URL = pkg.Source.URL
+			req.Specifier = ""
+		case pkg.Source.Path != "" || pkg.Source.Directory != "":
+			b.warn("%s: local dependency %s is skipped", field, pkg.Name)
+			continue
+		}
+
+		if pkg.Sdist != nil && pkg.Sdist.Hash != "" {
+			req.Hashes = append(req.Hashes, pkg.Sdist.Hash)
+		}
+		for _, wheel := range pkg.Wheels {
+			if wheel.Hash != "" {
+				req.Hashes = append(req.Hashes, wheel.Hash)
+			}
+		}
+
+		if err := b.add(field, req); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.lock, nil
+}
+
+func uvKey(name string, version string) string {
+	return pep503.Normalize(name) + "==" + version
+}
+
+// uvMarkers вычисляет маркер каждого пакета: в uv.lock маркеры записаны на рёбрах
+// графа, поэтому маркер пакета — это or по всем путям от корней проекта
+func uvMarkers(packages []uvPackage) map[string]string {
+	versions := make(map[string][]string)
+	byKey := make(map[string]*uvPackage)
+	for i := range packages {
+		pkg := &packages[i]
+		key := uvKey(pkg.Name, pkg.Version)
+		byKey[key] = pkg
+		versions[pep503.Normalize(pkg.Name)] = append(versions[pep503.Normalize(pkg.Name)], key)
+	}
+
+	targetsOf := func(dep uvDependency) []string {
+		if dep.Version != "" {
+			return []string{uvKey(dep.Name, dep.Version)}
+		}
+		return versions[pep503.Normalize(dep.Name)]
+	}
+
+	// Extras пакета, запрошенные хотя бы одним ребром графа
+	requestedExtras := make(map[string][]string)
+	for i := range packages {
+		for _, dep := range packages[i].edges(nil) {
+			for _, target := range targetsOf(dep) {
+				requestedExtras[target] = append(requestedExtras[target], dep.Extra...)
+			}
+		}
+	}
+
+	reached := make(map[string][]string)
+	unconditional := make(map[string]bool)
+
+	var queue []string
+	visit := func(key string, marker string) {
+		switch {
+		case unconditional[key]:
+			return
+		case marker == "" || len(reached[key]) >= maxUVMarkers:
+			unconditional[key] = true
+			reached[key] = nil
+		case slices.Contains(reached[key], marker):
+			return
+		default:
+			reached[key] = append(reached[key], marker)
+		}
+		queue = append(queue, key)
+	}
+
+	hasRoot := false
+	for i := range packages {
+		if packages[i].isRoot() {
+			hasRoot = true
+			visit(uvKey(packages[i].Name, packages[i].Version), "")
+		}
+	}
+	if !hasRoot {
+		return nil
+	}
+
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+
+		parentMarker := markerUnion(reached[key])
+		if unconditional[key] {
+			parentMarker = ""
+		}
+
+		for _, dep := range byKey[key].edges(requestedExtras[key]) {
+			marker := joinAnd(parentMarker, dep.Marker)
+
+			for _, target := range targetsOf(dep) {
+				if _, ok := byKey[target]; ok {
+					visit(target, marker)
+				}
+			}
+		}
+	}
+
+	markers := make(map[string]string, len(reached))
+	for key, values := range reached {
+		if !unconditional[key] {
+			markers[key] = markerUnion(values)
+		}
+	}
+	return markers
+}
+
+func joinAnd(a string, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return fmt.Sprintf("(%s) and (%s)", a, b)
+	}
+}
+
+// uvGitURL переводит git+https://host/repo?tag=v1#<commit> в git+https://host/repo@<commit>
+func uvGitURL(source string) string {
+	parsed, err := url.Parse(source)
+	if err != nil {
+		return gitURL(source, "")
+	}
+
+	commit := parsed.Fragment
+	subdirectory := parsed.Query().Get("subdirectory")
+	parsed.Fragment = ""
+	parsed.RawQuery = ""
+
+	result := gitURL(parsed.String(), commit)
+	if subdirectory != "" {
+		result += "#subdirectory=" + subdirectory
+	}
+	return result
+}
+
+func canonicalRequirement(name string, extras []string, specifier string) string {
+	extras = slices.Clone(extras)
+	slices.Sort(extras)
+
+	clauses := strings.Split(strings.ReplaceAll(specifier, " ", ""), ",")
+	slices.Sort(clauses)
+
+	result := pep503.Normalize(name)
+	if len(extras) > 0 {
+		result += "[" + strings.Join(extras, ",") + "]"
+	}
+	return result + strings.Join(clauses, ",")
+}
+
+// uvCheckManifest сравнивает requires-dist корневого проекта в uv.lock
+// с зависимостями pyproject.toml, так как content-hash в uv.lock нет
+func uvCheckManifest(rootDependencies []string, manifest []byte) (string, error) {
+	project, err := pyproject.Parse(manifest)
+	if err != nil {
+		return "", err
+	}
+
+	reqs := slices.Clone(project.Dependencies)
+	for _, extra := range sortedKeys(project.OptionalDependencies) {
+		reqs = append(reqs, project.OptionalDependencies[extra]...)
+	}
+
+	var declared []string
+	for _, req := range reqs {
+		declared = append(declared, canonicalRequirement(req.Name, req.Extras, req.Specifier))
+	}
+
+	var added, removed []string
+	for _, dep := range declared {
+		if !slices.Contains(rootDependencies, dep) {
+			added = append(added, dep)
+		}
+	}
+	for _, dep := range rootDependencies {
+		if !slices.Contains(declared, dep) {
+			removed = append(removed, dep)
+		}
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		return "", nil
+	}
+
+	return fmt.Sprintf("uv.lock does not match pyproject.toml dependencies (not locked: %s; no longer declared: %s), the lock file is stale",
+		listOrNone(added), listOrNone(removed)), nil
+}
+
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/kafka"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockResolverProducer struct {
+	mock.Mock
+}
+
+var _ kafka.Producer = (*MockResolverProducer)(nil) // Compile-time check
+
+func NewMockResolverProducer() *MockResolverProducer {
+	return &MockResolverProducer{}
+}
+
+func (m *MockResolverProducer) PublishStatus(ctx context.Context, event *eventspb.AnalysisStatusEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockResolverProducer) PublishResult(ctx context.Context, event *eventspb.AnalysisResultEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockResolverProducer) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/0hJonny/python-deps-crawler/internal/api-gateway/repository"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStatusRepository struct {
+	mock.Mock
+}
+
+var _ repository.StatusRepository = (*MockStatusRepository)(nil) // Compile-time check
+
+func NewMockStatusRepository() *MockStatusRepository {
+	return &MockStatusRepository{}
+}
+
+func (m *MockStatusRepository) Save(ctx context.Context, event *eventspb.AnalysisStatusEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockStatusRepository) Get(ctx context.Context, requestID string) (*eventspb.AnalysisStatusEvent, error) {
+	args := m.Called(ctx, requestID)
+	if event, ok := args.Get(0).(*eventspb.AnalysisStatusEvent); ok {
+		return event, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,49 @@
+package pep425
+
+// Compatibility ранжирует wheel по тегам целевой платформы
+type Compatibility struct {
+	platform Platform
+	priority map[Tag]int
+}
+
+// NewCompatibility вычисляет поддерживаемые платформой теги
+func NewCompatibility(platform Platform) (*Compatibility, error) {
+	platform, err := platform.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	tags, err := platform.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	priority := make(map[Tag]int, len(tags))
+	for i, tag := range tags {
+		if _, ok := priority[tag]; !ok {
+			priority[tag] = i
+		}
+	}
+	return &Compatibility{platform: platform, priority: priority}, nil
+}
+
+// Platform возвращает нормализованную целевую платформу
+func (c *Compatibility) Platform() Platform {
+	return c.platform
+}
+
+// Supports сообщает, что тег подходит платформе
+func (c *Compatibility) Supports(tag Tag) bool {
+	_, ok := c.priority[tag]
+	return ok
+}
+
+// Rank возвращает приоритет wheel с набором тегов: меньше — лучше.
+// ok == false, если ни один тег не подходит платформе
+func (c *Compatibility) Rank(tags []Tag) (rank int, ok bool) {
+	for _, tag := range tags {
+		if priority, found := c.priority[tag]; found && (!ok || priority < rank) {
+			rank, ok = priority, true
+		}
+	}
+	return rank, ok
+}
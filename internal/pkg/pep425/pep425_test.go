@@ -0,0 +1,140 @@
+package pep425_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep425"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// golden — эталонные теги, полученные из packaging.tags
+type golden struct {
+	Cases []struct {
+		Name     string            `json:"name"`
+		Platform map[string]string `json:"platform"`
+		Tags     []string          `json:"tags"`
+	} `json:"cases"`
+}
+
+func TestPlatform_TagsMatchPackaging(t *testing.T) {
+	data, err := os.ReadFile("testdata/packaging.json")
+	require.NoError(t, err)
+
+	var g golden
+	require.NoError(t, json.Unmarshal(data, &g))
+	require.NotEmpty(t, g.Cases)
+
+	for _, tc := range g.Cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			platform := pep425.Platform{
+				PythonVersion: tc.Platform["python_version"],
+				OS:            tc.Platform["os"],
+				Arch:          tc.Platform["arch"],
+				Libc:          tc.Platform["libc"],
+				LibcVersion:   tc.Platform["libc_version"],
+				MacOSVersion:  tc.Platform["macos_version"],
+			}
+			tags, err := platform.Tags()
+			require.NoError(t, err)
+
+			actual := make([]string, len(tags))
+			for i, tag := range tags {
+				actual[i] = tag.String()
+			}
+			assert.Equal(t, tc.Tags, actual)
+		})
+	}
+}
+
+func TestPlatform_Normalize(t *testing.T) {
+	platform, err := pep425.Platform{PythonVersion: "3.12", OS: "Darwin", Arch: "aarch64"}.Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, pep425.Platform{PythonVersion: "3.12", OS: "macos", Arch: "arm64", MacOSVersion: "14.0"}, platform)
+
+	platform, err = pep425.Platform{PythonVersion: "3.11", Arch: "amd64", Libc: "musllinux"}.Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, pep425.Platform{PythonVersion: "3.11", OS: "linux", Arch: "x86_64", Libc: "musl", LibcVersion: "1.2"}, platform)
+
+	for _, invalid := range []pep425.Platform{
+		{PythonVersion: ""},
+		{PythonVersion: "2.7"},
+		{PythonVersion: "3.12", OS: "freebsd"},
+		{PythonVersion: "3.12", OS: "macos", Arch: "ppc64le"},
+		{PythonVersion: "3.12", OS: "windows", Libc: "glibc"},
+		{PythonVersion: "3.12", Libc: "bionic"},
+		{PythonVersion: "3.12", LibcVersion: "2.x"},
+		{PythonVersion: "3.12", MacOSVersion: "14.0"},
+	} {
+		_, err := invalid.Normalize()
+		assert.ErrorIs(t, err, pep425.ErrInvalidPlatform, "%+v", invalid)
+	}
+}
+
+func TestPlatform_MarkerEnvironment(t *testing.T) {
+	env, err := pep425.Platform{PythonVersion: "3.12", OS: "windows"}.MarkerEnvironment()
+	require.NoError(t, err)
+	assert.Equal(t, "win32", env.SysPlatform)
+	assert.Equal(t, "AMD64", env.PlatformMachine)
+	assert.Equal(t, "3.12", env.PythonVersion)
+}
+
+func TestParseWheelFilename(t *testing.T) {
+	wheel, err := pep425.ParseWheelFilename("numpy-1.26.4-1-cp312-cp312-manylinux_2_17_x86_64.manylinux2014_x86_64.whl")
+	require.NoError(t, err)
+	assert.Equal(t, "numpy", wheel.Name)
+	assert.Equal(t, "1.26.4", wheel.Version)
+	assert.Equal(t, "1", wheel.BuildTag)
+	assert.Equal(t, []pep425.Tag{
+		{Interpreter: "cp312", ABI: "cp312", Platform: "manylinux_2_17_x86_64"},
+		{Interpreter: "cp312", ABI: "cp312", Platform: "manylinux2014_x86_64"},
+	}, wheel.Tags)
+
+	wheel, err = pep425.ParseWheelFilename("six-1.16.0-py2.py3-none-any.whl")
+	require.NoError(t, err)
+	assert.Len(t, wheel.Tags, 2)
+	assert.Equal(t, "py2.py3-none-any", wheel.TagSet)
+
+	for _, invalid := range []string{
+		"six-1.16.0.tar.gz",
+		"six-1.16.0-py3-none.whl",
+		"six-one-py3-none-any.whl",
+		"six-1.16.0-build-py3-none-any.whl",
+		"six-1.16.0-py3..py2-none-any.whl",
+	} {
+		_, err := pep425.ParseWheelFilename(invalid)
+		assert.ErrorIs(t, err, pep425.ErrInvalidWheelFilename, invalid)
+	}
+}
+
+func TestCompatibility_Rank(t *testing.T) {
+	compat, err := pep425.NewCompatibility(pep425.Platform{PythonVersion: "3.11"})
+	require.NoError(t, err)
+
+	rank := func(filename string) (int, bool) {
+		wheel, err := pep425.ParseWheelFilename(filename)
+		require.NoError(t, err)
+		return compat.Rank(wheel.Tags)
+	}
+
+	native, ok := rank("numpy-1.26.4-cp311-cp311-manylinux_2_17_x86_64.manylinux2014_x86_64.whl")
+	require.True(t, ok)
+	abi3, ok := rank("cryptography-43.0.0-cp39-abi3-manylinux_2_28_x86_64.whl")
+	require.True(t, ok)
+	pure, ok := rank("six-1.16.0-py2.py3-none-any.whl")
+	require.True(t, ok)
+	assert.Less(t, native, abi3)
+	assert.Less(t, abi3, pure)
+
+	for _, incompatible := range []string{
+		"numpy-1.26.4-cp312-cp312-manylinux_2_17_x86_64.whl",
+		"numpy-1.26.4-cp311-cp311-macosx_11_0_arm64.whl",
+		"numpy-1.26.4-cp311-cp311-manylinux_2_34_x86_64.whl",
+		"numpy-1.26.4-cp311-cp311-musllinux_1_1_x86_64.whl",
+	} {
+		_, ok := rank(incompatible)
+		assert.False(t, ok, incompatible)
+	}
+}
@@ -0,0 +1,318 @@
+package pep425
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep508"
+)
+
+// Операционные системы и C библиотеки Linux
+const (
+	OSLinux   = "linux"
+	OSMacOS   = "macos"
+	OSWindows = "windows"
+
+	LibcGlibc = "glibc"
+	LibcMusl  = "musl"
+)
+
+// Версии по умолчанию: glibc 2.28 покрывает все актуальные дистрибутивы, кроме CentOS 7
+const (
+	defaultGlibcVersion = "2.28"
+	defaultMuslVersion  = "1.2"
+	defaultMacOSVersion = "14.0"
+)
+
+// Архитектуры, для которых публикуются wheel, в написании тегов платформы
+var supportedArchs = map[string][]string{
+	OSLinux:   {"x86_64", "i686", "aarch64", "armv7l", "ppc64le", "s390x"},
+	OSMacOS:   {"x86_64", "arm64"},
+	OSWindows: {"x86_64", "x86", "arm64"},
+}
+
+// Старые имена manylinux по PEP 513, 571 и 599
+var legacyManylinux = map[int]string{
+	17: "manylinux2014",
+	12: "manylinux2010",
+	5:  "manylinux1",
+}
+
+// Platform описывает целевое окружение CPython. Пустые поля получают
+// значения по умолчанию: Linux x86_64 с glibc 2.28
+type Platform struct {
+	// PythonVersion — версия интерпретатора, "3.12" или "3.12.4"
+	PythonVersion string
+	// OS — linux, macos или windows
+	OS   string
+	Arch string
+	// Libc — glibc (manylinux) или musl (musllinux), только для Linux
+	Libc string
+	// LibcVersion — максимальная версия glibc или musl на целевой системе
+	LibcVersion string
+	// MacOSVersion — версия macOS на целевой системе
+	MacOSVersion string
+}
+
+// Normalize проверяет платформу, приводит синонимы к написанию тегов
+// (darwin — macos, amd64 — x86_64) и подставляет значения по умолчанию
+func (p Platform) Normalize() (Platform, error) {
+	if major, _, err := parseVersion(p.PythonVersion); err != nil {
+		return Platform{}, fmt.Errorf("%w: python version: %w", ErrInvalidPlatform, err)
+	} else if major != 3 {
+		return Platform{}, fmt.Errorf("%w: only Python 3 is supported, got %q", ErrInvalidPlatform, p.PythonVersion)
+	}
+
+	p.OS = normalizeOS(p.OS)
+	archs, ok := supportedArchs[p.OS]
+	if !ok {
+		return Platform{}, fmt.Errorf("%w: unsupported os %q, expected linux, macos or windows", ErrInvalidPlatform, p.OS)
+	}
+	p.Arch = normalizeArch(p.OS, p.Arch)
+	if !slices.Contains(archs, p.Arch) {
+		return Platform{}, fmt.Errorf("%w: unsupported %s architecture %q, expected one of %s",
+			ErrInvalidPlatform, p.OS, p.Arch, strings.Join(archs, ", "))
+	}
+
+	if p.OS != OSLinux {
+		if p.Libc != "" || p.LibcVersion != "" {
+			return Platform{}, fmt.Errorf("%w: libc can only be set for linux", ErrInvalidPlatform)
+		}
+	} else {
+		p.Libc = strings.ToLower(p.Libc)
+		switch p.Libc {
+		case "", LibcGlibc, "manylinux":
+			p.Libc = LibcGlibc
+			if p.LibcVersion == "" {
+				p.LibcVersion = defaultGlibcVersion
+			}
+		case LibcMusl, "musllinux":
+			p.Libc = LibcMusl
+			if p.LibcVersion == "" {
+				p.LibcVersion = defaultMuslVersion
+			}
+		default:
+			return Platform{}, fmt.Errorf("%w: unsupported libc %q, expected glibc or musl", ErrInvalidPlatform, p.Libc)
+		}
+		if _, _, err := parseVersion(p.LibcVersion); err != nil {
+			return Platform{}, fmt.Errorf("%w: %s version: %w", ErrInvalidPlatform, p.Libc, err)
+		}
+	}
+
+	if p.OS != OSMacOS {
+		if p.MacOSVersion != "" {
+			return Platform{}, fmt.Errorf("%w: macos version can only be set for macos", ErrInvalidPlatform)
+		}
+	} else {
+		if p.MacOSVersion == "" {
+			p.MacOSVersion = defaultMacOSVersion
+		}
+		if _, _, err := parseVersion(p.MacOSVersion); err != nil {
+			return Platform{}, fmt.Errorf("%w: macos version: %w", ErrInvalidPlatform, err)
+		}
+	}
+
+	return p, nil
+}
+
+// Tags возвращает поддерживаемые платформой теги от наиболее к наименее предпочтительному,
+// как packaging.tags.sys_tags для CPython
+func (p Platform) Tags() ([]Tag, error) {
+	p, err := p.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	major, minor, _ := parseVersion(p.PythonVersion)
+	platforms := p.platformTags()
+
+	interpreter := fmt.Sprintf("cp%d%d", major, minor)
+	abi := interpreter
+	if minor < 8 {
+		// До 3.8 ABI CPython по умолчанию собирался с pymalloc
+		abi += "m"
+	}
+	useABI3 := minor >= 2
+
+	var tags []Tag
+	add := func(interpreter string, abi string, platforms ...string) {
+		for _, platform := range platforms {
+			tags = append(tags, Tag{Interpreter: interpreter, ABI: abi, Platform: platform})
+		}
+	}
+
+	add(interpreter, abi, platforms...)
+	if useABI3 {
+		add(interpreter, "abi3", platforms...)
+	}
+	add(interpreter, "none", platforms...)
+	if useABI3 {
+		for older := minor - 1; older > 1; older-- {
+			add(fmt.Sprintf("cp%d%d", major, older), "abi3", platforms...)
+		}
+	}
+
+	pythons := pythonRange(major, minor)
+	for _, python := range pythons {
+		add(python, "none", platforms...)
+	}
+	add(interpreter, "none", "any")
+	for _, python := range pythons {
+		add(python, "none", "any")
+	}
+	return tags, nil
+}
+
+// MarkerEnvironment описывает платформу для вычисления маркеров PEP 508
+func (p Platform) MarkerEnvironment() (pep508.Environment, error) {
+	p, err := p.Normalize()
+	if err != nil {
+		return pep508.Environment{}, err
+	}
+
+	env := pep508.NewEnvironment(p.PythonVersion)
+	switch p.OS {
+	case OSMacOS:
+		env.SysPlatform, env.PlatformSystem, env.OSName = "darwin", "Darwin", "posix"
+		env.PlatformMachine = p.Arch
+	case OSWindows:
+		env.SysPlatform, env.PlatformSystem, env.OSName = "win32", "Windows", "nt"
+		env.PlatformMachine = map[string]string{"x86_64": "AMD64", "x86": "x86", "arm64": "ARM64"}[p.Arch]
+	default:
+		env.PlatformMachine = p.Arch
+	}
+	return env, nil
+}
+
+func (p Platform) platformTags() []string {
+	switch p.OS {
+	case OSMacOS:
+		major, minor, _ := parseVersion(p.MacOSVersion)
+		return macPlatforms(major, minor, p.Arch)
+	case OSWindows:
+		return []string{map[string]string{"x86_64": "win_amd64", "x86": "win32", "arm64": "win_arm64"}[p.Arch]}
+	}
+
+	var platforms []string
+	major, minor, _ := parseVersion(p.LibcVersion)
+	if p.Libc == LibcMusl {
+		for m := minor; m >= 0; m-- {
+			platforms = append(platforms, fmt.Sprintf("musllinux_%d_%d_%s", major, m, p.Arch))
+		}
+	} else if major == 2 {
+		// manylinux для x86 начинается с glibc 2.5, для остальных архитектур — с 2.17
+		oldest := 17
+		if p.Arch == "x86_64" || p.Arch == "i686" {
+			oldest = 5
+		}
+		for m := minor; m >= oldest; m-- {
+			platforms = append(platforms, fmt.Sprintf("manylinux_2_%d_%s", m, p.Arch))
+			if legacy, ok := legacyManylinux[m]; ok {
+				platforms = append(platforms, legacy+"_"+p.Arch)
+			}
+		}
+	}
+	return append(platforms, "linux_"+p.Arch)
+}
+
+// macPlatforms повторяет packaging.tags.mac_platforms
+func macPlatforms(major int, minor int, arch string) []string {
+	var platforms []string
+	add := func(major int, minor int, formats ...string) {
+		for _, format := range formats {
+			platforms = append(platforms, fmt.Sprintf("macosx_%d_%d_%s", major, minor, format))
+		}
+	}
+
+	if major == 10 {
+		for m := minor; m >= 0; m-- {
+			add(10, m, macBinaryFormats(10, m, arch)...)
+		}
+		return platforms
+	}
+
+	// С macOS 11 каждый выпуск увеличивает major, minor — промежуточные обновления
+	for m := major; m > 10; m-- {
+		add(m, 0, macBinaryFormats(m, 0, arch)...)
+	}
+	// x86_64 совместим с выпусками 10.x, arm64 — только через universal2
+	for m := 16; m > 3; m-- {
+		if arch == "x86_64" {
+			add(10, m, macBinaryFormats(10, m, arch)...)
+		} else {
+			add(10, m, "universal2")
+		}
+	}
+	return platforms
+}
+
+func macBinaryFormats(major int, minor int, arch string) []string {
+	if arch != "x86_64" {
+		return []string{arch, "universal2"}
+	}
+	if major == 10 && minor < 4 {
+		return nil
+	}
+	return []string{arch, "intel", "fat64", "fat32", "universal2", "universal"}
+}
+
+// pythonRange — py312, py3, py311 ... py30
+func pythonRange(major int, minor int) []string {
+	versions := []string{fmt.Sprintf("py%d%d", major, minor), fmt.Sprintf("py%d", major)}
+	for m := minor - 1; m >= 0; m-- {
+		versions = append(versions, fmt.Sprintf("py%d%d", major, m))
+	}
+	return versions
+}
+
+func normalizeOS(os string) string {
+	switch os = strings.ToLower(os); os {
+	case "":
+		return OSLinux
+	case "darwin", "macosx", "osx":
+		return OSMacOS
+	case "win", "win32", "win64":
+		return OSWindows
+	}
+	return os
+}
+
+func normalizeArch(os string, arch string) string {
+	arch = strings.ToLower(arch)
+	switch arch {
+	case "", "amd64", "x64":
+		return "x86_64"
+	case "aarch64", "arm64":
+		if os == OSLinux {
+			return "aarch64"
+		}
+		return "arm64"
+	case "x86", "i386", "i686", "win32":
+		if os == OSWindows {
+			return "x86"
+		}
+		return "i686"
+	}
+	return arch
+}
+
+// parseVersion разбирает версию вида major.minor[.patch]
+func parseVersion(version string) (int, int, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return 0, 0, fmt.Errorf("invalid version %q", version)
+	}
+
+	numbers := make([]int, 2)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, 0, fmt.Errorf("invalid version %q", version)
+		}
+		if i < 2 {
+			numbers[i] = n
+		}
+	}
+	return numbers[0], numbers[1], nil
+}
@@ -0,0 +1,97 @@
+// Package pep425 — теги совместимости wheel по PEP 425 и их подбор
+// для целевой платформы в том же порядке, что и у pip
+package pep425
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+)
+
+var (
+	ErrInvalidTag           = errors.New("invalid compatibility tag")
+	ErrInvalidWheelFilename = errors.New("invalid wheel filename")
+	ErrInvalidPlatform      = errors.New("invalid target platform")
+)
+
+// Tag — тройка interpreter-abi-platform, например cp312-cp312-manylinux_2_17_x86_64
+type Tag struct {
+	Interpreter string
+	ABI         string
+	Platform    string
+}
+
+func (t Tag) String() string {
+	return t.Interpreter + "-" + t.ABI + "-" + t.Platform
+}
+
+// ParseTagSet разворачивает сжатый набор тегов: py2.py3-none-any — это py2-none-any и py3-none-any
+func ParseTagSet(s string) ([]Tag, error) {
+	parts := strings.Split(strings.ToLower(s), "-")
+	if len(parts) != 3 || slices.Contains(parts, "") {
+		return nil, fmt.Errorf("%w %q: expected <python>-<abi>-<platform>", ErrInvalidTag, s)
+	}
+
+	var tags []Tag
+	for _, interpreter := range strings.Split(parts[0], ".") {
+		for _, abi := range strings.Split(parts[1], ".") {
+			for _, platform := range strings.Split(parts[2], ".") {
+				if interpreter == "" || abi == "" || platform == "" {
+					return nil, fmt.Errorf("%w %q: empty tag component", ErrInvalidTag, s)
+				}
+				tags = append(tags, Tag{Interpreter: interpreter, ABI: abi, Platform: platform})
+			}
+		}
+	}
+	return tags, nil
+}
+
+// WheelFilename — разобранное имя wheel по PEP 427
+type WheelFilename struct {
+	Name     string
+	Version  string
+	BuildTag string
+	// Tags — все теги, которые объявляет wheel
+	Tags []Tag
+	// TagSet — исходная сжатая запись тегов, например py2.py3-none-any
+	TagSet string
+}
+
+// ParseWheelFilename разбирает {name}-{version}(-{build})?-{python}-{abi}-{platform}.whl
+func ParseWheelFilename(filename string) (WheelFilename, error) {
+	stem, ok := strings.CutSuffix(filename, ".whl")
+	if !ok {
+		return WheelFilename{}, fmt.Errorf("%w %q: missing .whl extension", ErrInvalidWheelFilename, filename)
+	}
+
+	parts := strings.Split(stem, "-")
+	if len(parts) != 5 && len(parts) != 6 {
+		return WheelFilename{}, fmt.Errorf("%w %q", ErrInvalidWheelFilename, filename)
+	}
+
+	wheel := WheelFilename{
+		Name:    parts[0],
+		Version: parts[1],
+		TagSet:  strings.Join(parts[len(parts)-3:], "-"),
+	}
+	if _, err := pep440.Parse(wheel.Version); err != nil {
+		return WheelFilename{}, fmt.Errorf("%w %q: invalid version", ErrInvalidWheelFilename, filename)
+	}
+	if len(parts) == 6 {
+		// Build tag обязан начинаться с цифры
+		wheel.BuildTag = parts[2]
+		if wheel.BuildTag[0] < '0' || wheel.BuildTag[0] > '9' {
+			return WheelFilename{}, fmt.Errorf("%w %q: build tag must start with a digit", ErrInvalidWheelFilename, filename)
+		}
+	}
+
+	tags, err := ParseTagSet(wheel.TagSet)
+	if err != nil {
+		return WheelFilename{}, fmt.Errorf("%w %q: %w", ErrInvalidWheelFilename, filename, err)
+	}
+	wheel.Tags = tags
+	return wheel, nil
+}
@@ -0,0 +1,216 @@
+package pep440
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidSpecifier = errors.New("invalid PEP 440 specifier")
+
+// operators упорядочены так, чтобы более длинные операторы проверялись первыми
+var operators = []string{"===", "~=", "==", "!=", "<=", ">=", "<", ">"}
+
+var wildcardPrefix = regexp.MustCompile(`(?i)^v?(?:[0-9]+!)?[0-9]+(?:\.[0-9]+)*$`)
+
+// Specifier — одно условие на версию, например >=1.0 или ==2.*
+type Specifier struct {
+	Operator string
+	Version  string
+}
+
+// ParseSpecifier разбирает одно условие и объясняет, чем оно неверно
+func ParseSpecifier(s string) (Specifier, error) {
+	s = strings.TrimSpace(s)
+
+	var spec Specifier
+	for _, op := range operators {
+		if rest, ok := strings.CutPrefix(s, op); ok {
+			spec = Specifier{Operator: op, Version: strings.TrimSpace(rest)}
+			break
+		}
+	}
+
+	invalid := func(format string, args ...any) (Specifier, error) {
+		return Specifier{}, fmt.Errorf("%w %q: %s", ErrInvalidSpecifier, s, fmt.Sprintf(format, args...))
+	}
+
+	switch {
+	case spec.Operator == "":
+		return invalid("missing comparison operator (one of %s)", strings.Join(operators, ", "))
+	case spec.Version == "":
+		return invalid("missing version after %s", spec.Operator)
+	case spec.Operator == "===":
+		if strings.ContainsAny(spec.Version, " \t;)") {
+			return invalid("arbitrary equality version must not contain whitespace, ';' or ')'")
+		}
+		return spec, nil
+	}
+
+	if prefix, ok := strings.CutSuffix(spec.Version, ".*"); ok {
+		if spec.Operator != "==" && spec.Operator != "!=" {
+			return invalid("wildcard .* is only allowed with == and !=")
+		}
+		if !wildcardPrefix.MatchString(prefix) {
+			return invalid("wildcard .* may only follow a release segment, not a pre, post, dev or local label")
+		}
+		return spec, nil
+	}
+
+	version, err := Parse(spec.Version)
+	if err != nil {
+		return invalid("%q is not a valid PEP 440 version", spec.Version)
+	}
+	if version.local != nil && spec.Operator != "==" && spec.Operator != "!=" {
+		return invalid("local version label is only allowed with == and !=")
+	}
+	if spec.Operator == "~=" && len(version.release) < 2 {
+		return invalid("~= requires at least two release segments, e.g. ~=%s.0", spec.Version)
+	}
+
+	return spec, nil
+}
+
+func (s Specifier) String() string {
+	return s.Operator + s.Version
+}
+
+// AllowsPrereleases сообщает, что условие явно упоминает pre-релиз (>=1.0a1)
+func (s Specifier) AllowsPrereleases() bool {
+	switch s.Operator {
+	case "==", ">=", "<=", "~=", "===", ">", "<":
+		v, err := Parse(strings.TrimSuffix(s.Version, ".*"))
+		return err == nil && v.IsPrerelease()
+	default:
+		return false
+	}
+}
+
+// Contains проверяет версию; pre-релизы допускаются, только если их упоминает условие
+func (s Specifier) Contains(v Version) bool {
+	return s.ContainsPrereleases(v, s.AllowsPrereleases())
+}
+
+// ContainsPrereleases проверяет версию с явным разрешением или запретом pre-релизов
+func (s Specifier) ContainsPrereleases(v Version, prereleases bool) bool {
+	if v.IsPrerelease() && !prereleases {
+		return false
+	}
+
+	switch s.Operator {
+	case "~=":
+		return s.compatible(v)
+	case "==":
+		return s.equal(v)
+	case "!=":
+		return !s.equal(v)
+	case "<=":
+		return v.Public().Compare(MustParse(s.Version)) <= 0
+	case ">=":
+		return v.Public().Compare(MustParse(s.Version)) >= 0
+	case "<":
+		return s.lessThan(v)
+	case ">":
+		return s.greaterThan(v)
+	case "===":
+		return strings.EqualFold(v.String(), s.Version)
+	default:
+		return false
+	}
+}
+
+// compatible: ~=2.2.1 эквивалентно >=2.2.1,==2.2.*
+func (s Specifier) compatible(v Version) bool {
+	spec := MustParse(s.Version)
+	prefix := Version{epoch: spec.epoch, release: spec.release[:len(spec.release)-1]}
+
+	return v.Public().Compare(spec) >= 0 &&
+		Specifier{Operator: "==", Version: prefix.String() + ".*"}.equal(v)
+}
+
+func (s Specifier) equal(v Version) bool {
+	if prefix, ok := strings.CutSuffix(s.Version, ".*"); ok {
+		specParts := splitVersion(MustParse(prefix))
+		prospective := splitVersion(v.Public())
+
+		// Дополняем релиз нулями, чтобы 1 совпадала с ==1.0.*
+		padded := padRelease(prospective, specParts)
+		if len(padded) < len(specParts) {
+			return false
+		}
+		return slices.Equal(padded[:len(specParts)], specParts)
+	}
+
+	spec := MustParse(s.Version)
+	if spec.local == nil {
+		v = v.Public()
+	}
+	return v.Equal(spec)
+}
+
+// lessThan: <3.1 не включает pre-релизы самой 3.1, если условие не pre-релиз
+func (s Specifier) lessThan(v Version) bool {
+	spec := MustParse(s.Version)
+	if v.Compare(spec) >= 0 {
+		return false
+	}
+	if !spec.IsPrerelease() && v.IsPrerelease() && v.BaseVersion().Equal(spec.BaseVersion()) {
+		return false
+	}
+	return true
+}
+
+// greaterThan: >3.1 не включает post-релизы и локальные версии самой 3.1
+func (s Specifier) greaterThan(v Version) bool {
+	spec := MustParse(s.Version)
+	if v.Compare(spec) <= 0 {
+		return false
+	}
+	sameBase := v.BaseVersion().Equal(spec.BaseVersion())
+	if !spec.IsPostrelease() && v.IsPostrelease() && sameBase {
+		return false
+	}
+	if v.local != nil && sameBase {
+		return false
+	}
+	return true
+}
+
+// splitVersion делит нормализованную версию на эпоху, части релиза и суффиксы,
+// как _version_split в packaging: 1.0rc1 → [0 1 0 rc1]
+func splitVersion(v Version) []string {
+	parts := []string{fmt.Sprint(v.epoch)}
+	for _, n := range v.release {
+		parts = append(parts, fmt.Sprint(n))
+	}
+	if v.pre != nil {
+		parts = append(parts, fmt.Sprintf("%s%d", v.pre.label, v.pre.number))
+	}
+	if v.post != nil {
+		parts = append(parts, fmt.Sprintf("post%d", v.post.number))
+	}
+	if v.dev != nil {
+		parts = append(parts, fmt.Sprintf("dev%d", v.dev.number))
+	}
+	return parts
+}
+
+// padRelease дополняет числовую часть left нулями до длины числовой части right
+func padRelease(left []string, right []string) []string {
+	numeric := func(parts []string) int {
+		n := 0
+		for n < len(parts) && strings.Trim(parts[n], "0123456789") == "" {
+			n++
+		}
+		return n
+	}
+
+	leftRelease, rightRelease := numeric(left), numeric(right)
+	padded := append([]string(nil), left[:leftRelease]...)
+	for i := leftRelease; i < rightRelease; i++ {
+		padded = append(padded, "0")
+	}
+	return append(padded, left[leftRelease:]...)
+}
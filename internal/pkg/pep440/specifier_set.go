@@ -0,0 +1,142 @@
+package pep440
+
+import "strings"
+
+// SpecifierSet — набор условий через запятую, все должны выполняться
+type SpecifierSet []Specifier
+
+// SpecifierSetError перечисляет все неверные условия набора
+type SpecifierSetError struct {
+	Errors []error
+}
+
+func (e *SpecifierSetError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *SpecifierSetError) Unwrap() []error {
+	return e.Errors
+}
+
+// ParseSpecifierSet разбирает набор условий; пустая строка означает любую версию
+func ParseSpecifierSet(s string) (SpecifierSet, error) {
+	var (
+		set  SpecifierSet
+		errs []error
+	)
+
+	for part := range strings.SplitSeq(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		spec, err := ParseSpecifier(part)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		set = append(set, spec)
+	}
+
+	if len(errs) > 0 {
+		return nil, &SpecifierSetError{Errors: errs}
+	}
+	return set, nil
+}
+
+// ParseRequirementVersion разбирает package_version из запроса: допускается
+// и набор условий, и голая версия, которая означает точное совпадение
+// и хранится в нормализованном виде (v1.0 → ==1.0)
+func ParseRequirementVersion(s string) (SpecifierSet, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed != "" && !strings.ContainsAny(trimmed[:1], "<>=!~") {
+		if version, err := Parse(trimmed); err == nil {
+			return SpecifierSet{{Operator: "==", Version: version.String()}}, nil
+		}
+	}
+	return ParseSpecifierSet(s)
+}
+
+func (s SpecifierSet) String() string {
+	parts := make([]string, len(s))
+	for i, spec := range s {
+		parts[i] = spec.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// AllowsPrereleases сообщает, что хотя бы одно условие упоминает pre-релиз
+func (s SpecifierSet) AllowsPrereleases() bool {
+	for _, spec := range s {
+		if spec.AllowsPrereleases() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPinned сообщает, что набор допускает ровно одну версию (==1.0 или ===1.0)
+func (s SpecifierSet) IsPinned() bool {
+	if len(s) != 1 {
+		return false
+	}
+	spec := s[0]
+	return spec.Operator == "===" || (spec.Operator == "==" && !strings.HasSuffix(spec.Version, ".*"))
+}
+
+// Contains проверяет версию по всем условиям с правилами pre-релизов по умолчанию
+func (s SpecifierSet) Contains(v Version) bool {
+	return s.ContainsPrereleases(v, s.AllowsPrereleases())
+}
+
+// ContainsPrereleases проверяет версию с явным разрешением или запретом pre-релизов
+func (s SpecifierSet) ContainsPrereleases(v Version, prereleases bool) bool {
+	if v.IsPrerelease() && !prereleases {
+		return false
+	}
+	for _, spec := range s {
+		if !spec.ContainsPrereleases(v, prereleases) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter оставляет подходящие версии. Как и в packaging, pre-релизы возвращаются,
+// только если условия их упоминают; пустой набор отдаёт их, только если других версий нет
+func (s SpecifierSet) Filter(versions []Version) []Version {
+	if len(s) == 0 {
+		var final, prereleases []Version
+		for _, v := range versions {
+			if v.IsPrerelease() {
+				if len(final) == 0 {
+					prereleases = append(prereleases, v)
+				}
+				continue
+			}
+			final = append(final, v)
+		}
+		if len(final) == 0 {
+			return prereleases
+		}
+		return final
+	}
+
+	// Непустой набор, как SpecifierSet.filter в packaging, передаёт условиям общий флаг:
+	// pre-релиз проходит, только если его упоминает хотя бы одно условие
+	return s.FilterPrereleases(versions, s.AllowsPrereleases())
+}
+
+// FilterPrereleases оставляет подходящие версии с явным разрешением или запретом pre-релизов
+func (s SpecifierSet) FilterPrereleases(versions []Version, prereleases bool) []Version {
+	var result []Version
+	for _, v := range versions {
+		if s.ContainsPrereleases(v, prereleases) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package pep440_test
+
+import (
+	"testing"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseVersions(t *testing.T, inputs []string) []pep440.Version {
+	t.Helper()
+
+	versions := make([]pep440.Version, len(inputs))
+	for i, s := range inputs {
+		v, err := pep440.Parse(s)
+		require.NoError(t, err, s)
+		versions[i] = v
+	}
+	return versions
+}
+
+func TestSpecifierSet_ContainsMatchesPackaging(t *testing.T) {
+	g := loadGolden(t)
+
+	for _, tc := range g.Contains {
+		set, err := pep440.ParseSpecifierSet(tc.Specifier)
+		require.NoError(t, err, tc.Specifier)
+
+		for _, row := range tc.Versions {
+			v := pep440.MustParse(row.Version)
+			assert.Equal(t, row.Default, set.Contains(v), "%q contains %s", tc.Specifier, row.Version)
+			assert.Equal(t, row.Prereleases, set.ContainsPrereleases(v, true),
+				"%q contains %s with prereleases", tc.Specifier, row.Version)
+		}
+	}
+}
+
+func TestSpecifierSet_FilterMatchesPackaging(t *testing.T) {
+	g := loadGolden(t)
+
+	var defaults []string
+	for _, tc := range g.Normalize {
+		defaults = append(defaults, tc.Input)
+	}
+
+	for _, tc := range g.Filter {
+		set, err := pep440.ParseSpecifierSet(tc.Specifier)
+		require.NoError(t, err, tc.Specifier)
+
+		inputs := tc.Versions
+		if inputs == nil {
+			inputs = defaults
+		}
+
+		// packaging возвращает исходные строки, сравниваем нормализованные
+		expected := []string{}
+		for _, v := range parseVersions(t, tc.Expected) {
+			expected = append(expected, v.String())
+		}
+		got := []string{}
+		for _, v := range set.Filter(parseVersions(t, inputs)) {
+			got = append(got, v.String())
+		}
+		assert.Equal(t, expected, got, tc.Specifier)
+	}
+}
+
+func TestParseSpecifier_InvalidMatchesPackaging(t *testing.T) {
+	g := loadGolden(t)
+
+	for _, input := range g.InvalidSpecifiers {
+		_, err := pep440.ParseSpecifier(input)
+		assert.ErrorIs(t, err, pep440.ErrInvalidSpecifier, input)
+	}
+}
+
+func TestParseSpecifier_PreciseErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+	}{
+		{"1.0", "missing comparison operator"},
+		{">=", "missing version after >="},
+		{">=1.0.*", "wildcard .* is only allowed with == and !="},
+		{"==1.0a1.*", "wildcard .* may only follow a release segment"},
+		{"==1.0.x", `"1.0.x" is not a valid PEP 440 version`},
+		{">=1.0+local", "local version label is only allowed with == and !="},
+		{"~=1", "~= requires at least two release segments, e.g. ~=1.0"},
+		{"===1.0 ;", "arbitrary equality version must not contain whitespace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := pep440.ParseSpecifier(tt.input)
+			require.ErrorIs(t, err, pep440.ErrInvalidSpecifier)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestParseSpecifierSet_CollectsAllErrors(t *testing.T) {
+	_, err := pep440.ParseSpecifierSet(">=1.0.*, <2.0, ~=3")
+	require.Error(t, err)
+
+	var setErr *pep440.SpecifierSetError
+	require.ErrorAs(t, err, &setErr)
+	assert.Len(t, setErr.Errors, 2)
+	assert.ErrorIs(t, err, pep440.ErrInvalidSpecifier)
+}
+
+func TestParseRequirementVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		pinned   bool
+		wantErr  bool
+	}{
+		{input: "", expected: "", pinned: false},
+		{input: "2.28.1", expected: "==2.28.1", pinned: true},
+		{input: "v1.0", expected: "==1.0", pinned: true},
+		{input: " 1.0.0-rc1 ", expected: "==1.0.0rc1", pinned: true},
+		{input: "==2.28.1", expected: "==2.28.1", pinned: true},
+		{input: "===2.28.1", expected: "===2.28.1", pinned: true},
+		{input: "==2.28.*", expected: "==2.28.*", pinned: false},
+		{input: ">=2.0,<3", expected: ">=2.0,<3", pinned: false},
+		{input: "==1.0,==1.0", expected: "==1.0,==1.0", pinned: false},
+		{input: "latest", wantErr: true},
+		{input: ">=2.0,<<3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			set, err := pep440.ParseRequirementVersion(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, pep440.ErrInvalidSpecifier)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, set.String())
+			assert.Equal(t, tt.pinned, set.IsPinned())
+		})
+	}
+}
@@ -0,0 +1,139 @@
+"""Пересчитывает packaging.json — эталон для тестов pep440.
+
+Запуск из каталога internal/pkg/pep440:
+
+    python3 -m venv /tmp/pep440 && /tmp/pep440/bin/pip install packaging==25.0
+    /tmp/pep440/bin/python testdata/generate_packaging.py > testdata/packaging.json
+"""
+
+import json
+import sys
+
+import packaging
+from packaging.specifiers import InvalidSpecifier, SpecifierSet
+from packaging.version import InvalidVersion, Version
+
+PACKAGING_VERSION = "25.0"
+
+# Версии для нормализации, сортировки и проверки условий
+VERSIONS = [
+    "1.0", "1.0.0", "1", "v1.0",
+    "1.0a1", "1.0alpha1", "1.0-beta.2", "1.0c3", "1.0pre4", "1.0preview5", "1.0rc1",
+    "1.0.dev0", "1.0dev", "1.0a1.dev1",
+    "1.0.post1", "1.0-1", "1.0r2", "1.0rev3", "1.0.post1.dev2",
+    "1.0+local.7", "1.0+abc.5", "1.0+5", "1.0+abc",
+    "1!0.5", "2.0", "2.0b1", "1.1", "1.1.post0", "0.9",
+    "3.1", "3.1.dev0", "3.0.dev0", "3.1.post1", "3.1+ubuntu", "3.0.post1", "3.2.post0",
+    " 1.2.3 ", "1.2.3.4.5", "2.2.1", "2.3", "2.2",
+    "1.0RC1", "1.0.0.0.0", "01.002", "1.0+LOCAL.007",
+    "1.0_a_1", "1.0.a.1", "1.0.post", "1.0-dev-3", "2024.1.15",
+]
+
+INVALID_VERSIONS = [
+    "", "abc", "1.0+", "1..0", "1.0-", "1.0+local..1", "1.0.x", "=1.0", "1.0 .1", "1.0a1a2", "1.0++1",
+]
+
+SPECIFIERS = [
+    "==1.0", "==1.0.*", "==1.*", "!=1.0", "!=1.0.*",
+    "~=1.0", "~=2.2", "~=2.2.0", "~=2.2.post3", "~=1.0a1",
+    ">=1.0", ">1.0", "<1.0", "<=1.0", ">3.1", "<3.1", ">=1.0a1", "<2.0b1",
+    "==1.0+local.7", "!=1.0+local.7", "===1.0", "===1.0.0",
+    ">=1!0.1", "==1!0.*", "==2024.*",
+    ">=1.0,<2.0", ">=1.0,!=1.1,<3", "~=3.0,!=3.0.post1", "",
+    "==1.0.0.*", ">=1.0.dev0", "<3.1.dev0", ">3.0.post0",
+]
+
+# Отдельные наборы версий для правил отбора pre-релизов в filter
+FILTER_CASES = [
+    ("", ["1.0a1", "1.0b2"]),
+    (">=1.0", ["1.1a1", "1.2b1"]),
+    (">=1.0a1", ["1.0a2", "1.0"]),
+    ("", ["1.0a1", "0.9", "1.1a1"]),
+]
+
+INVALID_SPECIFIERS = [
+    "1.0", ">=1.0.*", "~=1", "~=1.0+local", ">=1.0+local", "==1.0a1.*",
+    "=>1.0", ">=", "==1.0.x", "<=1.0.*", "~=1.0.*", ">= abc",
+]
+
+
+def normalize():
+    result = []
+    for input in VERSIONS:
+        version = Version(input)
+        result.append({
+            "input": input,
+            "normalized": str(version),
+            "prerelease": version.is_prerelease,
+            "postrelease": version.is_postrelease,
+        })
+    return result
+
+
+def invalid_versions():
+    for input in INVALID_VERSIONS:
+        try:
+            Version(input)
+        except InvalidVersion:
+            continue
+        raise SystemExit(f"{input!r} is a valid version")
+    return INVALID_VERSIONS
+
+
+def contains():
+    result = []
+    for specifier in SPECIFIERS:
+        spec = SpecifierSet(specifier)
+        result.append({
+            "specifier": specifier,
+            "versions": [
+                {
+                    "version": version,
+                    "default": spec.contains(version),
+                    "prereleases": spec.contains(version, prereleases=True),
+                }
+                for version in VERSIONS
+            ],
+        })
+    return result
+
+
+def filter_cases():
+    result = [
+        {"specifier": specifier, "expected": list(SpecifierSet(specifier).filter(VERSIONS)), "versions": None}
+        for specifier in SPECIFIERS
+    ]
+    result += [
+        {"specifier": specifier, "expected": list(SpecifierSet(specifier).filter(versions)), "versions": versions}
+        for specifier, versions in FILTER_CASES
+    ]
+    return result
+
+
+def invalid_specifiers():
+    for input in INVALID_SPECIFIERS:
+        try:
+            SpecifierSet(input)
+        except InvalidSpecifier:
+            continue
+        raise SystemExit(f"{input!r} is a valid specifier")
+    return INVALID_SPECIFIERS
+
+
+def main():
+    if packaging.__version__ != PACKAGING_VERSION:
+        raise SystemExit(f"packaging {PACKAGING_VERSION} is required, found {packaging.__version__}")
+
+    json.dump({
+        "normalize": normalize(),
+        "invalid_versions": invalid_versions(),
+        # sorted устойчива, равные версии остаются в порядке VERSIONS
+        "ordered": sorted(VERSIONS, key=Version),
+        "contains": contains(),
+        "filter": filter_cases(),
+        "invalid_specifiers": invalid_specifiers(),
+    }, sys.stdout, indent=1)
+
+
+if __name__ == "__main__":
+    main()
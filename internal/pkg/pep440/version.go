@@ -0,0 +1,312 @@
+// Package pep440 реализует версии и спецификаторы версий PEP 440
+// с той же семантикой, что и библиотека packaging, которой пользуется pip
+package pep440
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidVersion = errors.New("invalid PEP 440 version")
+
+var versionPattern = regexp.MustCompile(`(?i)^\s*v?` +
+	`(?:(?P<epoch>[0-9]+)!)?` +
+	`(?P<release>[0-9]+(?:\.[0-9]+)*)` +
+	`(?P<pre>[-_.]?(?P<pre_l>alpha|a|beta|b|preview|pre|c|rc)[-_.]?(?P<pre_n>[0-9]+)?)?` +
+	`(?P<post>(?:-(?P<post_n1>[0-9]+))|(?:[-_.]?(?P<post_l>post|rev|r)[-_.]?(?P<post_n2>[0-9]+)?))?` +
+	`(?P<dev>[-_.]?(?P<dev_l>dev)[-_.]?(?P<dev_n>[0-9]+)?)?` +
+	`(?:\+(?P<local>[a-z0-9]+(?:[-_.][a-z0-9]+)*))?` +
+	`\s*$`)
+
+var preLabels = map[string]string{
+	"a": "a", "alpha": "a",
+	"b": "b", "beta": "b",
+	"c": "rc", "rc": "rc", "pre": "rc", "preview": "rc",
+}
+
+// Version — разобранная версия PEP 440
+type Version struct {
+	epoch   uint64
+	release []uint64
+	pre     *segment
+	post    *segment
+	dev     *segment
+	local   []localPart
+}
+
+// segment — метка с номером: a1, post2, dev0
+type segment struct {
+	label  string
+	number uint64
+}
+
+type localPart struct {
+	text    string
+	number  uint64
+	numeric bool
+}
+
+// Parse разбирает версию PEP 440, допуская все формы, которые нормализует packaging
+func Parse(s string) (Version, error) {
+	match := versionPattern.FindStringSubmatch(s)
+	if match == nil {
+		return Version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+	}
+	group := func(name string) string {
+		return strings.ToLower(match[versionPattern.SubexpIndex(name)])
+	}
+
+	var v Version
+	var err error
+	number := func(s string) uint64 {
+		if s == "" || err != nil {
+			return 0
+		}
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 64)
+		return n
+	}
+
+	v.epoch = number(group("epoch"))
+	for part := range strings.SplitSeq(group("release"), ".") {
+		v.release = append(v.release, number(part))
+	}
+
+	if label := group("pre_l"); label != "" {
+		v.pre = &segment{label: preLabels[label], number: number(group("pre_n"))}
+	}
+	if group("post") != "" {
+		v.post = &segment{label: "post", number: number(group("post_n1") + group("post_n2"))}
+	}
+	if group("dev") != "" {
+		v.dev = &segment{label: "dev", number: number(group("dev_n"))}
+	}
+	if local := group("local"); local != "" {
+		for part := range strings.FieldsFuncSeq(local, isLocalSeparator) {
+			p := localPart{text: part}
+			if n, convErr := strconv.ParseUint(part, 10, 64); convErr == nil {
+				p.number, p.numeric = n, true
+			} else if strings.Trim(part, "0123456789") == "" {
+				err = convErr
+			}
+			v.local = append(v.local, p)
+		}
+	}
+
+	if err != nil {
+		return Version{}, fmt.Errorf("%w: %q: component out of range", ErrInvalidVersion, s)
+	}
+	return v, nil
+}
+
+// MustParse разбирает версию и паникует при ошибке. Предназначена для констант и тестов
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func isLocalSeparator(r rune) bool {
+	return r == '-' || r == '_' || r == '.'
+}
+
+// String возвращает нормализованную запись версии
+func (v Version) String() string {
+	var b strings.Builder
+	b.WriteString(v.Public().publicString())
+	if len(v.local) > 0 {
+		b.WriteString("+" + v.Local())
+	}
+	return b.String()
+}
+
+func (v Version) publicString() string {
+	var b strings.Builder
+	if v.epoch != 0 {
+		fmt.Fprintf(&b, "%d!", v.epoch)
+	}
+	b.WriteString(joinRelease(v.release))
+	if v.pre != nil {
+		fmt.Fprintf(&b, "%s%d", v.pre.label, v.pre.number)
+	}
+	if v.post != nil {
+		fmt.Fprintf(&b, ".post%d", v.post.number)
+	}
+	if v.dev != nil {
+		fmt.Fprintf(&b, ".dev%d", v.dev.number)
+	}
+	return b.String()
+}
+
+func joinRelease(release []uint64) string {
+	parts := make([]string, len(release))
+	for i, n := range release {
+		parts[i] = strconv.FormatUint(n, 10)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (v Version) Epoch() uint64 {
+	return v.epoch
+}
+
+func (v Version) Release() []uint64 {
+	return slices.Clone(v.release)
+}
+
+// Local возвращает локальную метку без '+' или пустую строку
+func (v Version) Local() string {
+	parts := make([]string, len(v.local))
+	for i, part := range v.local {
+		parts[i] = part.text
+		if part.numeric {
+			parts[i] = strconv.FormatUint(part.number, 10)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func (v Version) IsPrerelease() bool {
+	return v.pre != nil || v.dev != nil
+}
+
+func (v Version) IsPostrelease() bool {
+	return v.post != nil
+}
+
+func (v Version) IsDevrelease() bool {
+	return v.dev != nil
+}
+
+// Public возвращает версию без локальной метки
+func (v Version) Public() Version {
+	v.local = nil
+	return v
+}
+
+// BaseVersion возвращает эпоху и релиз без pre, post, dev и локальной метки
+func (v Version) BaseVersion() Version {
+	return Version{epoch: v.epoch, release: v.release}
+}
+
+func (v Version) Equal(other Version) bool {
+	return v.Compare(other) == 0
+}
+
+func (v Version) Less(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+// Compare сравнивает версии по правилам PEP 440 и возвращает -1, 0 или 1
+func (v Version) Compare(other Version) int {
+	if c := compareUint(v.epoch, other.epoch); c != 0 {
+		return c
+	}
+	if c := slices.Compare(trimZeros(v.release), trimZeros(other.release)); c != 0 {
+		return c
+	}
+	if c := comparePre(v, other); c != 0 {
+		return c
+	}
+	// Отсутствие post-релиза меньше любого post-релиза
+	if c := compareOptional(v.post, other.post, -1); c != 0 {
+		return c
+	}
+	// Отсутствие dev-релиза больше любого dev-релиза
+	if c := compareOptional(v.dev, other.dev, 1); c != 0 {
+		return c
+	}
+	return compareLocal(v.local, other.local)
+}
+
+// comparePre упорядочивает 1.0.dev0 < 1.0a1 < 1.0rc1 < 1.0
+func comparePre(a Version, b Version) int {
+	rank := func(v Version) int {
+		switch {
+		case v.pre == nil && v.post == nil && v.dev != nil:
+			return -1
+		case v.pre == nil:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	if c := rank(a) - rank(b); c != 0 || rank(a) != 0 {
+		return max(-1, min(1, c))
+	}
+	if c := strings.Compare(a.pre.label, b.pre.label); c != 0 {
+		return c
+	}
+	return compareUint(a.pre.number, b.pre.number)
+}
+
+func compareOptional(a *segment, b *segment, missing int) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return missing
+	case b == nil:
+		return -missing
+	default:
+		return compareUint(a.number, b.number)
+	}
+}
+
+// compareLocal: версия без локальной метки меньше любой с меткой,
+// числовые части больше буквенных
+func compareLocal(a []localPart, b []localPart) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	for i := 0; i < min(len(a), len(b)); i++ {
+		x, y := a[i], b[i]
+		switch {
+		case x.numeric && y.numeric:
+			if c := compareUint(x.number, y.number); c != 0 {
+				return c
+			}
+		case x.numeric:
+			return 1
+		case y.numeric:
+			return -1
+		default:
+			if c := strings.Compare(x.text, y.text); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareUint(uint64(len(a)), uint64(len(b)))
+}
+
+func compareUint(a uint64, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func trimZeros(release []uint64) []uint64 {
+	end := len(release)
+	for end > 0 && release[end-1] == 0 {
+		end--
+	}
+	return release[:end]
+}
@@ -0,0 +1,107 @@
+package pep440_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// golden — ожидаемые результаты, посчитанные packaging 25.0 на тех же входных данных.
+// testdata/packaging.json пересчитывается скриптом testdata/generate_packaging.py
+type golden struct {
+	Normalize []struct {
+		Input       string `json:"input"`
+		Normalized  string `json:"normalized"`
+		Prerelease  bool   `json:"prerelease"`
+		Postrelease bool   `json:"postrelease"`
+	} `json:"normalize"`
+	InvalidVersions []string `json:"invalid_versions"`
+	Ordered         []string `json:"ordered"`
+	Contains        []struct {
+		Specifier string `json:"specifier"`
+		Versions  []struct {
+			Version     string `json:"version"`
+			Default     bool   `json:"default"`
+			Prereleases bool   `json:"prereleases"`
+		} `json:"versions"`
+	} `json:"contains"`
+	Filter []struct {
+		Specifier string   `json:"specifier"`
+		Expected  []string `json:"expected"`
+		Versions  []string `json:"versions"`
+	} `json:"filter"`
+	InvalidSpecifiers []string `json:"invalid_specifiers"`
+}
+
+func loadGolden(t *testing.T) golden {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/packaging.json")
+	require.NoError(t, err)
+
+	var g golden
+	require.NoError(t, json.Unmarshal(data, &g))
+	return g
+}
+
+func TestParse_MatchesPackaging(t *testing.T) {
+	g := loadGolden(t)
+
+	for _, tc := range g.Normalize {
+		v, err := pep440.Parse(tc.Input)
+		require.NoError(t, err, tc.Input)
+		assert.Equal(t, tc.Normalized, v.String(), tc.Input)
+		assert.Equal(t, tc.Prerelease, v.IsPrerelease(), tc.Input)
+		assert.Equal(t, tc.Postrelease, v.IsPostrelease(), tc.Input)
+	}
+
+	for _, input := range g.InvalidVersions {
+		_, err := pep440.Parse(input)
+		assert.ErrorIs(t, err, pep440.ErrInvalidVersion, input)
+	}
+}
+
+func TestCompare_MatchesPackagingOrder(t *testing.T) {
+	g := loadGolden(t)
+
+	versions := make([]pep440.Version, len(g.Ordered))
+	for i, s := range g.Ordered {
+		versions[i] = pep440.MustParse(s)
+	}
+
+	for i := range versions {
+		for j := range versions {
+			got := versions[i].Compare(versions[j])
+			switch {
+			case i < j && versions[i].Equal(versions[j]):
+				// packaging сохраняет порядок равных версий при сортировке
+				assert.Zero(t, got)
+			case i < j:
+				assert.Equal(t, -1, got, "%s < %s", g.Ordered[i], g.Ordered[j])
+			case i > j:
+				assert.NotEqual(t, -1, got, "%s >= %s", g.Ordered[i], g.Ordered[j])
+			}
+		}
+	}
+}
+
+func TestVersion_Accessors(t *testing.T) {
+	v := pep440.MustParse("1!2.3.4rc1.post2.dev3+Ubuntu-1")
+
+	assert.Equal(t, uint64(1), v.Epoch())
+	assert.Equal(t, []uint64{2, 3, 4}, v.Release())
+	assert.Equal(t, "ubuntu.1", v.Local())
+	assert.True(t, v.IsPrerelease())
+	assert.True(t, v.IsPostrelease())
+	assert.True(t, v.IsDevrelease())
+	assert.Equal(t, "1!2.3.4rc1.post2.dev3", v.Public().String())
+	assert.Equal(t, "1!2.3.4", v.BaseVersion().String())
+
+	assert.True(t, pep440.MustParse("1.0").Equal(pep440.MustParse("1.0.0")))
+	assert.True(t, pep440.MustParse("1.0.dev1").Less(pep440.MustParse("1.0a1")))
+	assert.Panics(t, func() { pep440.MustParse("not a version") })
+}
@@ -0,0 +1,43 @@
+// Package pep503 нормализует и проверяет имена проектов по PEP 503 и PEP 508
+package pep503
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidName = errors.New("invalid project name")
+
+var (
+	// PEP 508: имя начинается и заканчивается буквой или цифрой
+	validName      = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+	nameSeparators = regexp.MustCompile(`[-_.]+`)
+)
+
+// Normalize приводит имя к виду PEP 503: нижний регистр, любые серии
+// из '-', '_' и '.' заменяются одним '-'. Так "Zope_Interface"
+// и "zope.interface" становятся одним проектом "zope-interface"
+func Normalize(name string) string {
+	return strings.ToLower(nameSeparators.ReplaceAllString(name, "-"))
+}
+
+// Validate проверяет, что строка — допустимое имя проекта или extra
+func Validate(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("%w %q: must start and end with a letter or digit "+
+			"and contain only letters, digits, '.', '_' and '-'", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// IsValid сообщает, что строка — допустимое имя проекта или extra
+func IsValid(name string) bool {
+	return validName.MatchString(name)
+}
+
+// Equal сравнивает имена после нормализации
+func Equal(a string, b string) bool {
+	return Normalize(a) == Normalize(b)
+}
@@ -0,0 +1,38 @@
+package pep503_test
+
+import (
+	"testing"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Django":            "django",
+		"zope_interface":    "zope-interface",
+		"zope.interface":    "zope-interface",
+		"Zope__.-Interface": "zope-interface",
+		"requests":          "requests",
+		"PyYAML":            "pyyaml",
+		"typing-extensions": "typing-extensions",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, pep503.Normalize(input), input)
+	}
+	assert.True(t, pep503.Equal("Django", "django"))
+	assert.False(t, pep503.Equal("django", "django-rest"))
+}
+
+func TestValidate(t *testing.T) {
+	for _, name := range []string{"a", "requests", "zope.interface", "Foo_Bar-2", "1password"} {
+		assert.NoError(t, pep503.Validate(name), name)
+	}
+
+	for _, name := range []string{"", "-pkg", "pkg-", "pkg.", "my pkg", "pkg==1.0", "пакет"} {
+		err := pep503.Validate(name)
+		assert.ErrorIs(t, err, pep503.ErrInvalidName, name)
+		assert.False(t, pep503.IsValid(name), name)
+	}
+}
@@ -0,0 +1,82 @@
+package pep508
+
+import "strings"
+
+// Environment — целевое окружение, для которого вычисляются маркеры.
+// Пустое поле сравнивается как пустая строка
+type Environment struct {
+	ImplementationName           string
+	ImplementationVersion        string
+	OSName                       string
+	PlatformMachine              string
+	PlatformPythonImplementation string
+	PlatformRelease              string
+	PlatformSystem               string
+	PlatformVersion              string
+	PythonFullVersion            string
+	PythonVersion                string
+	SysPlatform                  string
+	// Extras — запрошенные extras пакета, для которого вычисляется маркер
+	Extras []string
+}
+
+// NewEnvironment описывает CPython заданной версии на Linux x86_64.
+// Версия может быть как "3.12", так и "3.12.4"
+func NewEnvironment(pythonVersion string) Environment {
+	fullVersion := pythonVersion
+	if strings.Count(fullVersion, ".") < 2 {
+		fullVersion += ".0"
+	}
+	shortVersion := fullVersion
+	if parts := strings.SplitN(fullVersion, ".", 3); len(parts) == 3 {
+		shortVersion = parts[0] + "." + parts[1]
+	}
+
+	return Environment{
+		ImplementationName:           "cpython",
+		ImplementationVersion:        fullVersion,
+		OSName:                       "posix",
+		PlatformMachine:              "x86_64",
+		PlatformPythonImplementation: "CPython",
+		PlatformSystem:               "Linux",
+		PythonFullVersion:            fullVersion,
+		PythonVersion:                shortVersion,
+		SysPlatform:                  "linux",
+	}
+}
+
+// WithExtras возвращает копию окружения с запрошенными extras
+func (e Environment) WithExtras(extras ...string) Environment {
+	e.Extras = make([]string, len(extras))
+	copy(e.Extras, extras)
+	return e
+}
+
+func (e *Environment) value(variable string) string {
+	switch variable {
+	case "implementation_name":
+		return e.ImplementationName
+	case "implementation_version":
+		return e.ImplementationVersion
+	case "os_name":
+		return e.OSName
+	case "platform_machine":
+		return e.PlatformMachine
+	case "platform_python_implementation":
+		return e.PlatformPythonImplementation
+	case "platform_release":
+		return e.PlatformRelease
+	case "platform_system":
+		return e.PlatformSystem
+	case "platform_version":
+		return e.PlatformVersion
+	case "python_full_version":
+		return e.PythonFullVersion
+	case "python_version":
+		return e.PythonVersion
+	case "sys_platform":
+		return e.SysPlatform
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,406 @@
+// Package pep508 разбирает строки зависимостей PEP 508 и вычисляет
+// маркеры окружения так же, как библиотека packaging
+package pep508
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+)
+
+var (
+	ErrInvalidMarker      = errors.New("invalid PEP 508 marker")
+	ErrInvalidRequirement = errors.New("invalid PEP 508 requirement")
+)
+
+// SyntaxError указывает место в строке, где разбор не удался
+type SyntaxError struct {
+	Kind    error // ErrInvalidMarker или ErrInvalidRequirement
+	Input   string
+	Pos     int
+	Message string
+	Err     error
+}
+
+func (e *SyntaxError) Error() string {
+	message := fmt.Sprintf("%s %q at position %d: %s", e.Kind, e.Input, e.Pos, e.Message)
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *SyntaxError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// markerVariables сопоставляет имена переменных, включая устаревшие
+// написания с точкой, с каноническими
+var markerVariables = map[string]string{
+	"implementation_name":            "implementation_name",
+	"implementation_version":         "implementation_version",
+	"os_name":                        "os_name",
+	"os.name":                        "os_name",
+	"platform_machine":               "platform_machine",
+	"platform.machine":               "platform_machine",
+	"platform_python_implementation": "platform_python_implementation",
+	"platform.python_implementation": "platform_python_implementation",
+	"python_implementation":          "platform_python_implementation",
+	"platform_release":               "platform_release",
+	"platform_system":                "platform_system",
+	"platform_version":               "platform_version",
+	"platform.version":               "platform_version",
+	"python_full_version":            "python_full_version",
+	"python_version":                 "python_version",
+	"sys_platform":                   "sys_platform",
+	"sys.platform":                   "sys_platform",
+	"extra":                          "extra",
+}
+
+var (
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*`)
+	notInPattern      = regexp.MustCompile(`^not\s+in\b`)
+	markerOperators   = []string{"===", "==", "~=", "!=", "<=", ">=", "<", ">"}
+)
+
+// Marker — разобранный маркер окружения. Нулевое значение означает
+// отсутствие маркера и выполняется в любом окружении
+type Marker struct {
+	root *sequence
+}
+
+// expression — сравнение или выражение в скобках
+type expression interface {
+	evaluate(env *Environment) bool
+	format(nested bool) string
+}
+
+// sequence хранит выражения и связки and/or в исходном порядке,
+// как packaging: and связывает сильнее, чем or
+type sequence struct {
+	terms []expression
+	ops   []string
+}
+
+// comparison сравнивает переменную окружения со строкой
+type comparison struct {
+	variable     string
+	op           string
+	value        string
+	variableLeft bool
+}
+
+// ParseMarker разбирает маркер окружения
+func ParseMarker(s string) (Marker, error) {
+	p := &markerParser{input: s, kind: ErrInvalidMarker}
+	root, err := p.parseSequence()
+	if err != nil {
+		return Marker{}, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return Marker{}, p.fail("expected 'and', 'or' or end of marker")
+	}
+	return Marker{root: root}, nil
+}
+
+// IsEmpty сообщает, что маркер не задан
+func (m Marker) IsEmpty() bool {
+	return m.root == nil
+}
+
+// Evaluate проверяет, выполняется ли маркер в окружении
+func (m Marker) Evaluate(env Environment) bool {
+	if m.root == nil {
+		return true
+	}
+	return m.root.evaluate(&env)
+}
+
+// String возвращает маркер в каноническом виде packaging
+func (m Marker) String() string {
+	if m.root == nil {
+		return ""
+	}
+	return m.root.format(false)
+}
+
+func (s *sequence) evaluate(env *Environment) bool {
+	// Ищем группу между or, в которой выполнены все условия
+	group := true
+	for i, term := range s.terms {
+		if i > 0 && s.ops[i-1] == "or" {
+			if group {
+				return true
+			}
+			group = true
+		}
+		group = group && term.evaluate(env)
+	}
+	return group
+}
+
+func (s *sequence) format(nested bool) string {
+	if len(s.terms) == 1 {
+		return s.terms[0].format(nested)
+	}
+
+	var b strings.Builder
+	for i, term := range s.terms {
+		if i > 0 {
+			b.WriteString(" " + s.ops[i-1] + " ")
+		}
+		b.WriteString(term.format(true))
+	}
+	if nested {
+		return "(" + b.String() + ")"
+	}
+	return b.String()
+}
+
+func (c *comparison) evaluate(env *Environment) bool {
+	if c.variable == "extra" {
+		return c.evaluateExtra(env.Extras)
+	}
+
+	lhs, rhs := env.value(c.variable), c.value
+	if !c.variableLeft {
+		lhs, rhs = rhs, lhs
+	}
+	return compare(lhs, c.op, rhs)
+}
+
+// evaluateExtra проверяет extra против набора запрошенных extras: extra == "x"
+// выполняется, если x запрошен, а extra != "x" — если не запрошен
+func (c *comparison) evaluateExtra(extras []string) bool {
+	if len(extras) == 0 {
+		extras = []string{""}
+	}
+
+	negative := c.op == "!=" || c.op == "not in"
+	for _, extra := range extras {
+		lhs, rhs := pep503.Normalize(extra), c.value
+		if !c.variableLeft {
+			lhs, rhs = rhs, lhs
+		}
+		if compare(lhs, c.op, rhs) != negative {
+			return !negative
+		}
+	}
+	return negative
+}
+
+func (c *comparison) format(bool) string {
+	lhs, rhs := c.variable, `"`+c.value+`"`
+	if !c.variableLeft {
+		lhs, rhs = rhs, lhs
+	}
+	return lhs + " " + c.op + " " + rhs
+}
+
+// compare повторяет packaging: если правая часть образует спецификатор
+// PEP 440, сравниваются версии, иначе строки. Неприменимый к строкам
+// оператор, например ~= с не-версией, даёт false
+func compare(lhs string, op string, rhs string) bool {
+	if op == "===" {
+		return strings.EqualFold(lhs, rhs)
+	}
+
+	if op != "in" && op != "not in" {
+		if spec, err := pep440.ParseSpecifier(op + rhs); err == nil {
+			if version, err := pep440.Parse(lhs); err == nil {
+				return spec.ContainsPrereleases(version, true)
+			}
+		}
+	}
+
+	switch op {
+	case "in":
+		return strings.Contains(rhs, lhs)
+	case "not in":
+		return !strings.Contains(rhs, lhs)
+	case "==":
+		return lhs == rhs
+	case "!=":
+		return lhs != rhs
+	case "<":
+		return lhs < rhs
+	case "<=":
+		return lhs <= rhs
+	case ">":
+		return lhs > rhs
+	case ">=":
+		return lhs >= rhs
+	default:
+		return false
+	}
+}
+
+type markerParser struct {
+	input string
+	pos   int
+	kind  error
+}
+
+func (p *markerParser) fail(format string, args ...any) error {
+	return &SyntaxError{Kind: p.kind, Input: p.input, Pos: p.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *markerParser) skipSpace() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// keyword съедает слово, если за ним не продолжается идентификатор
+func (p *markerParser) keyword(word string) bool {
+	rest := p.input[p.pos:]
+	if !strings.HasPrefix(rest, word) {
+		return false
+	}
+	if len(rest) > len(word) && isIdentifierByte(rest[len(word)]) {
+		return false
+	}
+	p.pos += len(word)
+	return true
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func (p *markerParser) parseSequence() (*sequence, error) {
+	seq := &sequence{}
+	for {
+		term, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		seq.terms = append(seq.terms, term)
+
+		p.skipSpace()
+		switch {
+		case p.keyword("and"):
+			seq.ops = append(seq.ops, "and")
+		case p.keyword("or"):
+			seq.ops = append(seq.ops, "or")
+		default:
+			return seq, nil
+		}
+	}
+}
+
+func (p *markerParser) parseAtom() (expression, error) {
+	p.skipSpace()
+
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		p.pos++
+		inner, err := p.parseSequence()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return nil, p.fail("expected ')' to close the group")
+		}
+		p.pos++
+		return inner, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *markerParser) parseComparison() (expression, error) {
+	start := p.pos
+	left, leftIsVariable, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	op, err := p.parseOperator()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	right, rightIsVariable, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case leftIsVariable && rightIsVariable:
+		p.pos = start
+		return nil, p.fail("cannot compare two environment variables")
+	case !leftIsVariable && !rightIsVariable:
+		p.pos = start
+		return nil, p.fail("comparison must involve an environment variable")
+	case leftIsVariable:
+		return newComparison(left, op, right, true), nil
+	default:
+		return newComparison(right, op, left, false), nil
+	}
+}
+
+func newComparison(variable string, op string, value string, variableLeft bool) *comparison {
+	// PEP 685: имена extras сравниваются в нормализованном виде
+	if variable == "extra" {
+		value = pep503.Normalize(value)
+	}
+	return &comparison{variable: variable, op: op, value: value, variableLeft: variableLeft}
+}
+
+// parseOperand возвращает каноническое имя переменной или значение строки в кавычках
+func (p *markerParser) parseOperand() (string, bool, error) {
+	if p.pos >= len(p.input) {
+		return "", false, p.fail("expected environment variable or quoted string")
+	}
+
+	if quote := p.input[p.pos]; quote == '"' || quote == '\'' {
+		end := strings.IndexByte(p.input[p.pos+1:], quote)
+		if end < 0 {
+			return "", false, p.fail("unterminated quoted string")
+		}
+		value := p.input[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return value, false, nil
+	}
+
+	name := identifierPattern.FindString(p.input[p.pos:])
+	if name == "" {
+		return "", false, p.fail("expected environment variable or quoted string")
+	}
+	variable, ok := markerVariables[name]
+	if !ok {
+		return "", false, p.fail("unknown environment variable %q", name)
+	}
+	p.pos += len(name)
+	return variable, true, nil
+}
+
+func (p *markerParser) parseOperator() (string, error) {
+	rest := p.input[p.pos:]
+	for _, op := range markerOperators {
+		if strings.HasPrefix(rest, op) {
+			p.pos += len(op)
+			return op, nil
+		}
+	}
+	if match := notInPattern.FindString(rest); match != "" {
+		p.pos += len(match)
+		return "not in", nil
+	}
+	if p.keyword("in") {
+		return "in", nil
+	}
+	return "", p.fail("expected comparison operator (one of %s, in, not in)", strings.Join(markerOperators, ", "))
+}
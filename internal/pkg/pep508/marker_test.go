@@ -0,0 +1,166 @@
+package pep508_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep508"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// golden — ожидаемые результаты, посчитанные packaging 25.0 на тех же входных данных
+type golden struct {
+	Environments map[string]map[string]string `json:"environments"`
+	Markers      []struct {
+		Marker    string          `json:"marker"`
+		Canonical string          `json:"canonical"`
+		Results   map[string]bool `json:"results"`
+	} `json:"markers"`
+	InvalidMarkers []string `json:"invalid_markers"`
+	Requirements   []struct {
+		Input     string   `json:"input"`
+		Name      string   `json:"name"`
+		Extras    []string `json:"extras"`
+		Specifier string   `json:"specifier"`
+		URL       string   `json:"url"`
+		Marker    string   `json:"marker"`
+	} `json:"requirements"`
+	InvalidRequirements []string `json:"invalid_requirements"`
+}
+
+func loadGolden(t *testing.T) golden {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/packaging.json")
+	require.NoError(t, err)
+
+	var g golden
+	require.NoError(t, json.Unmarshal(data, &g))
+	return g
+}
+
+func environment(values map[string]string) pep508.Environment {
+	env := pep508.Environment{
+		ImplementationName:           values["implementation_name"],
+		ImplementationVersion:        values["implementation_version"],
+		OSName:                       values["os_name"],
+		PlatformMachine:              values["platform_machine"],
+		PlatformPythonImplementation: values["platform_python_implementation"],
+		PlatformRelease:              values["platform_release"],
+		PlatformSystem:               values["platform_system"],
+		PlatformVersion:              values["platform_version"],
+		PythonFullVersion:            values["python_full_version"],
+		PythonVersion:                values["python_version"],
+		SysPlatform:                  values["sys_platform"],
+	}
+	if extra := values["extra"]; extra != "" {
+		env.Extras = []string{extra}
+	}
+	return env
+}
+
+func TestMarker_MatchesPackaging(t *testing.T) {
+	g := loadGolden(t)
+
+	for _, tc := range g.Markers {
+		marker, err := pep508.ParseMarker(tc.Marker)
+		require.NoError(t, err, tc.Marker)
+		assert.Equal(t, tc.Canonical, marker.String(), tc.Marker)
+
+		for name, expected := range tc.Results {
+			got := marker.Evaluate(environment(g.Environments[name]))
+			assert.Equal(t, expected, got, "%s in %s", tc.Marker, name)
+		}
+	}
+}
+
+func TestParseMarker_Invalid(t *testing.T) {
+	g := loadGolden(t)
+
+	for _, input := range g.InvalidMarkers {
+		_, err := pep508.ParseMarker(input)
+		assert.ErrorIs(t, err, pep508.ErrInvalidMarker, input)
+	}
+}
+
+func TestParseMarker_PreciseErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		pos     int
+		message string
+	}{
+		{`python_version < 3.8`, 17, "expected environment variable or quoted string"},
+		{`python_version << "3.8"`, 16, "expected environment variable or quoted string"},
+		{`platform == "linux"`, 0, `unknown environment variable "platform"`},
+		{`(os_name == "nt"`, 16, "expected ')' to close the group"},
+		{`os_name == "nt" xor extra == "a"`, 16, "expected 'and', 'or' or end of marker"},
+		{`os_name = "nt"`, 8, "expected comparison operator"},
+		{`os_name == sys_platform`, 0, "cannot compare two environment variables"},
+		{`"a" == "b"`, 0, "comparison must involve an environment variable"},
+		{`os_name == "nt`, 11, "unterminated quoted string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := pep508.ParseMarker(tt.input)
+			require.ErrorIs(t, err, pep508.ErrInvalidMarker)
+
+			var syntaxErr *pep508.SyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, tt.pos, syntaxErr.Pos)
+			assert.Contains(t, syntaxErr.Message, tt.message)
+		})
+	}
+}
+
+func TestMarker_EvaluateWithSeveralExtras(t *testing.T) {
+	env := pep508.NewEnvironment("3.12").WithExtras("socks", "Test.Utils")
+
+	tests := []struct {
+		marker   string
+		expected bool
+	}{
+		{`extra == "socks"`, true},
+		{`extra == "test_utils"`, true},
+		{`extra == "docs"`, false},
+		{`extra != "socks"`, false},
+		{`extra != "docs"`, true},
+		{`extra == "docs" or extra == "socks"`, true},
+		{`extra == "socks" and python_version < "3.12"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.marker, func(t *testing.T) {
+			marker, err := pep508.ParseMarker(tt.marker)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, marker.Evaluate(env))
+		})
+	}
+}
+
+func TestMarker_UndefinedComparisonIsFalse(t *testing.T) {
+	marker, err := pep508.ParseMarker(`platform_machine ~= "x86"`)
+	require.NoError(t, err)
+	assert.False(t, marker.Evaluate(pep508.NewEnvironment("3.12")))
+}
+
+func TestMarker_EmptyAlwaysApplies(t *testing.T) {
+	var marker pep508.Marker
+	assert.True(t, marker.IsEmpty())
+	assert.True(t, marker.Evaluate(pep508.Environment{}))
+	assert.Empty(t, marker.String())
+}
+
+func TestNewEnvironment(t *testing.T) {
+	env := pep508.NewEnvironment("3.11")
+	assert.Equal(t, "3.11", env.PythonVersion)
+	assert.Equal(t, "3.11.0", env.PythonFullVersion)
+	assert.Equal(t, "linux", env.SysPlatform)
+
+	env = pep508.NewEnvironment("3.12.4")
+	assert.Equal(t, "3.12", env.PythonVersion)
+	assert.Equal(t, "3.12.4", env.PythonFullVersion)
+	assert.Equal(t, "3.12.4", env.ImplementationVersion)
+}
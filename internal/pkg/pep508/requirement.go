@@ -0,0 +1,179 @@
+package pep508
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+)
+
+// PEP 508: имя проекта и extra начинаются и заканчиваются буквой или цифрой
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9._-]*[A-Za-z0-9])?`)
+
+// Requirement — зависимость в формате PEP 508
+type Requirement struct {
+	Name      string
+	Extras    []string
+	Specifier pep440.SpecifierSet
+	URL       string
+	Marker    Marker
+}
+
+// ParseRequirement разбирает строку вида name[extras] specifier ; marker
+// или name[extras] @ url ; marker
+func ParseRequirement(s string) (Requirement, error) {
+	p := &markerParser{input: s, kind: ErrInvalidRequirement}
+
+	p.skipSpace()
+	name := namePattern.FindString(p.input[p.pos:])
+	if name == "" {
+		return Requirement{}, p.fail("expected package name at the start of dependency specifier")
+	}
+	p.pos += len(name)
+	req := Requirement{Name: name}
+
+	p.skipSpace()
+	if p.peek('[') {
+		extras, err := p.parseExtras()
+		if err != nil {
+			return Requirement{}, err
+		}
+		req.Extras = extras
+		p.skipSpace()
+	}
+
+	if p.peek('@') {
+		p.pos++
+		p.skipSpace()
+		if err := p.parseURL(&req); err != nil {
+			return Requirement{}, err
+		}
+	} else if err := p.parseSpecifier(&req); err != nil {
+		return Requirement{}, err
+	}
+
+	if p.peek(';') {
+		p.pos++
+		root, err := p.parseSequence()
+		if err != nil {
+			return Requirement{}, err
+		}
+		req.Marker = Marker{root: root}
+		p.skipSpace()
+	}
+
+	if p.pos < len(p.input) {
+		return Requirement{}, p.fail("expected end of dependency specifier or ';' before marker")
+	}
+	return req, nil
+}
+
+// AppliesTo сообщает, нужна ли зависимость в окружении
+func (r Requirement) AppliesTo(env Environment) bool {
+	return r.Marker.Evaluate(env)
+}
+
+// String возвращает зависимость в виде строки PEP 508
+func (r Requirement) String() string {
+	var b strings.Builder
+	b.WriteString(r.Name)
+	if len(r.Extras) > 0 {
+		b.WriteString("[" + strings.Join(r.Extras, ",") + "]")
+	}
+	if r.URL != "" {
+		b.WriteString(" @ " + r.URL)
+		if !r.Marker.IsEmpty() {
+			b.WriteString(" ")
+		}
+	} else {
+		b.WriteString(r.Specifier.String())
+	}
+	if !r.Marker.IsEmpty() {
+		b.WriteString("; " + r.Marker.String())
+	}
+	return b.String()
+}
+
+func (p *markerParser) peek(c byte) bool {
+	return p.pos < len(p.input) && p.input[p.pos] == c
+}
+
+func (p *markerParser) parseExtras() ([]string, error) {
+	p.pos++
+
+	var extras []string
+	for {
+		p.skipSpace()
+		if p.peek(']') && len(extras) == 0 {
+			p.pos++
+			return extras, nil
+		}
+
+		extra := namePattern.FindString(p.input[p.pos:])
+		if extra == "" {
+			return nil, p.fail("expected extra name")
+		}
+		extras = append(extras, extra)
+		p.pos += len(extra)
+
+		p.skipSpace()
+		switch {
+		case p.peek(','):
+			p.pos++
+		case p.peek(']'):
+			p.pos++
+			return extras, nil
+		default:
+			return nil, p.fail("expected ',' or ']' after extra name")
+		}
+	}
+}
+
+// parseURL читает URL до пробела: ';' без пробела перед ним считается частью URL
+func (p *markerParser) parseURL(req *Requirement) error {
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] != ' ' && p.input[p.pos] != '\t' {
+		p.pos++
+	}
+	if p.pos == start {
+		return p.fail("expected URL after '@'")
+	}
+
+	raw := p.input[start:p.pos]
+	if _, err := url.Parse(raw); err != nil {
+		p.pos = start
+		return &SyntaxError{Kind: p.kind, Input: p.input, Pos: start, Message: "invalid URL", Err: err}
+	}
+	req.URL = raw
+
+	p.skipSpace()
+	return nil
+}
+
+func (p *markerParser) parseSpecifier(req *Requirement) error {
+	start := p.pos
+	end := strings.IndexByte(p.input[p.pos:], ';')
+	if end < 0 {
+		end = len(p.input)
+	} else {
+		end += p.pos
+	}
+
+	text := strings.TrimSpace(p.input[start:end])
+	if inner, ok := strings.CutPrefix(text, "("); ok {
+		var closed bool
+		if inner, closed = strings.CutSuffix(inner, ")"); !closed {
+			return p.fail("expected ')' after version specifier")
+		}
+		text = inner
+	}
+
+	specifier, err := pep440.ParseSpecifierSet(text)
+	if err != nil {
+		return &SyntaxError{Kind: p.kind, Input: p.input, Pos: start, Message: "invalid version specifier", Err: err}
+	}
+	req.Specifier = specifier
+	p.pos = end
+	return nil
+}
@@ -0,0 +1,69 @@
+package pep508_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep508"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sortedClauses приводит набор условий к порядку packaging, который их сортирует
+func sortedClauses(s string) []string {
+	if s == "" {
+		return nil
+	}
+	clauses := strings.Split(s, ",")
+	slices.Sort(clauses)
+	return clauses
+}
+
+func TestParseRequirement_MatchesPackaging(t *testing.T) {
+	g := loadGolden(t)
+
+	for _, tc := range g.Requirements {
+		req, err := pep508.ParseRequirement(tc.Input)
+		require.NoError(t, err, tc.Input)
+
+		extras := slices.Sorted(slices.Values(req.Extras))
+		if len(tc.Extras) == 0 {
+			assert.Empty(t, extras, tc.Input)
+		} else {
+			assert.Equal(t, tc.Extras, extras, tc.Input)
+		}
+		assert.Equal(t, tc.Name, req.Name, tc.Input)
+		assert.Equal(t, sortedClauses(tc.Specifier), sortedClauses(req.Specifier.String()), tc.Input)
+		assert.Equal(t, tc.URL, req.URL, tc.Input)
+		assert.Equal(t, tc.Marker, req.Marker.String(), tc.Input)
+	}
+}
+
+func TestParseRequirement_Invalid(t *testing.T) {
+	g := loadGolden(t)
+
+	for _, input := range g.InvalidRequirements {
+		_, err := pep508.ParseRequirement(input)
+		assert.ErrorIs(t, err, pep508.ErrInvalidRequirement, input)
+	}
+}
+
+func TestParseRequirement_SpecifierErrorKeepsCause(t *testing.T) {
+	_, err := pep508.ParseRequirement("name ~= 1")
+	require.Error(t, err)
+
+	assert.ErrorIs(t, err, pep508.ErrInvalidRequirement)
+	assert.ErrorIs(t, err, pep440.ErrInvalidSpecifier)
+	assert.Contains(t, err.Error(), "~= requires at least two release segments")
+}
+
+func TestRequirement_AppliesTo(t *testing.T) {
+	req, err := pep508.ParseRequirement(`tomli>=1.1; python_version < "3.11"`)
+	require.NoError(t, err)
+
+	assert.True(t, req.AppliesTo(pep508.NewEnvironment("3.10")))
+	assert.False(t, req.AppliesTo(pep508.NewEnvironment("3.11")))
+	assert.Equal(t, `tomli>=1.1; python_version < "3.11"`, req.String())
+}
@@ -0,0 +1,129 @@
+package pypi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	cacheKeyPrefix = "pypi:cache:"
+	// Просроченные ответы хранятся дольше cache_timeout, чтобы их можно было
+	// перепроверить условным запросом вместо полной загрузки
+	cacheRetention = 7 * 24 * time.Hour
+)
+
+// Cache хранит ответы индексов в Redis. Все ответы одного проекта лежат в хеше
+// pypi:cache:<нормализованное имя>, поле хеша — адрес запроса
+type Cache struct {
+	client *redis.Client
+	ttl    time.Duration
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	revalidations atomic.Int64
+	errors        atomic.Int64
+}
+
+// CacheStats — счётчики обращений к кешу с момента запуска
+type CacheStats struct {
+	// Hits — ответ взят из кеша без запроса к индексу
+	Hits int64
+	// Misses — ответ загружен из индекса целиком
+	Misses int64
+	// Revalidations — просроченный ответ подтверждён индексом через 304 Not Modified
+	Revalidations int64
+	// Errors — ошибки Redis; запрос при этом уходит в индекс
+	Errors int64
+}
+
+// cacheEntry — ответ индекса вместе с валидаторами для условного запроса
+type cacheEntry struct {
+	Body         []byte    `json:"body"`
+	ContentType  string    `json:"content_type"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+}
+
+// NewCache создаёт кеш, ответы которого считаются свежими ttl (cache_timeout из PyPIConfig)
+func NewCache(client *redis.Client, ttl time.Duration) *Cache {
+	return &Cache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// WithCache включает кеширование ответов индекса
+func WithCache(cache *Cache) Option {
+	return func(f *fetcher) {
+		f.cache = cache
+	}
+}
+
+// Stats возвращает текущие значения счётчиков
+func (c *Cache) Stats() CacheStats {
+	return CacheStats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Revalidations: c.revalidations.Load(),
+		Errors:        c.errors.Load(),
+	}
+}
+
+// Purge удаляет все сохранённые ответы проекта из всех индексов
+func (c *Cache) Purge(ctx context.Context, name string) error {
+	if err := c.client.Del(ctx, cacheKey(name)).Err(); err != nil {
+		return fmt.Errorf("failed to purge cache for %s: %w", name, err)
+	}
+	return nil
+}
+
+// load возвращает сохранённый ответ или nil, если его нет
+func (c *Cache) load(ctx context.Context, name string, endpoint string) (*cacheEntry, error) {
+	data, err := c.client.HGet(ctx, cacheKey(name), endpoint).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cache for %s: %w", name, err)
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode cache for %s: %w", name, err)
+	}
+	return &entry, nil
+}
+
+func (c *Cache) store(ctx context.Context, name string, endpoint string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache for %s: %w", name, err)
+	}
+
+	key := cacheKey(name)
+	_, err = c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, endpoint, data)
+		pipe.PExpire(ctx, key, max(c.ttl, cacheRetention))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store cache for %s: %w", name, err)
+	}
+	return nil
+}
+
+func (c *Cache) fresh(entry *cacheEntry, now time.Time) bool {
+	return now.Sub(entry.StoredAt) < c.ttl
+}
+
+func cacheKey(name string) string {
+	return cacheKeyPrefix + pep503.Normalize(name)
+}
@@ -0,0 +1,116 @@
+package pypi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	projectETag         = `"serial-42"`
+	projectLastModified = "Wed, 01 Jan 2025 12:00:00 GMT"
+)
+
+// newRevalidatingServer отвечает 304 на условные запросы с актуальным ETag и считает полные ответы
+func newRevalidatingServer(t *testing.T) (*httptest.Server, *atomic.Int64, *atomic.Int64) {
+	t.Helper()
+
+	var full, notModified atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == projectETag && r.Header.Get("If-Modified-Since") == projectLastModified {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", projectETag)
+		w.Header().Set("Last-Modified", projectLastModified)
+		_, _ = w.Write([]byte(`{"info": {"name": "requests"}, "releases": {"2.32.3": [{"filename": "requests-2.32.3.tar.gz"}]}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &full, &notModified
+}
+
+func setupCache(t *testing.T, ttl time.Duration) (*pypi.Cache, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return pypi.NewCache(client, ttl), server
+}
+
+func TestCache_ServesFreshResponses(t *testing.T) {
+	server, full, _ := newRevalidatingServer(t)
+	cache, redisServer := setupCache(t, time.Hour)
+	client := pypi.NewClient(config.PyPIConfig{APIURL: server.URL}, pypi.WithCache(cache))
+
+	for range 3 {
+		project, err := client.Project(context.Background(), "Requests")
+		require.NoError(t, err)
+		assert.Equal(t, "requests", project.Info.Name)
+	}
+	// Другое написание имени попадает в ту же запись
+	_, err := client.Project(context.Background(), "REQUESTS")
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), full.Load())
+	assert.Equal(t, pypi.CacheStats{Hits: 3, Misses: 1}, cache.Stats())
+	assert.True(t, redisServer.Exists("pypi:cache:requests"))
+	assert.Greater(t, redisServer.TTL("pypi:cache:requests"), time.Hour)
+}
+
+func TestCache_RevalidatesExpiredResponses(t *testing.T) {
+	server, full, notModified := newRevalidatingServer(t)
+	cache, _ := setupCache(t, 0)
+	client := pypi.NewClient(config.PyPIConfig{APIURL: server.URL}, pypi.WithCache(cache))
+
+	for range 3 {
+		files, err := client.Files(context.Background(), "requests")
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, "2.32.3", files[0].Version)
+	}
+
+	assert.Equal(t, int64(1), full.Load())
+	assert.Equal(t, int64(2), notModified.Load())
+	assert.Equal(t, pypi.CacheStats{Misses: 1, Revalidations: 2}, cache.Stats())
+}
+
+func TestCache_Purge(t *testing.T) {
+	server, full, _ := newRevalidatingServer(t)
+	cache, redisServer := setupCache(t, time.Hour)
+	client := pypi.NewClient(config.PyPIConfig{APIURL: server.URL}, pypi.WithCache(cache))
+
+	_, err := client.Project(context.Background(), "requests")
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Purge(context.Background(), "Requests"))
+	assert.False(t, redisServer.Exists("pypi:cache:requests"))
+
+	_, err = client.Project(context.Background(), "requests")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), full.Load())
+}
+
+func TestCache_FallsBackToIndexWhenRedisIsDown(t *testing.T) {
+	server, full, _ := newRevalidatingServer(t)
+	cache, redisServer := setupCache(t, time.Hour)
+	client := pypi.NewClient(config.PyPIConfig{APIURL: server.URL}, pypi.WithCache(cache))
+
+	redisServer.Close()
+
+	_, err := client.Project(context.Background(), "requests")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), full.Load())
+	assert.Equal(t, pypi.CacheStats{Misses: 1, Errors: 2}, cache.Stats())
+}
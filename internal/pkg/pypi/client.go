@@ -0,0 +1,101 @@
+package pypi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+)
+
+// Client запрашивает метаданные проектов и релизов из JSON API PyPI
+type Client struct {
+	fetcher
+	baseURL string
+}
+
+// NewClient создаёт клиент JSON API по адресу api_url из PyPIConfig
+func NewClient(cfg config.PyPIConfig, opts ...Option) *Client {
+	return &Client{
+		fetcher: newFetcher(cfg, opts),
+		baseURL: strings.TrimRight(cfg.APIURL, "/"),
+	}
+}
+
+// Project возвращает метаданные проекта со списком всех релизов
+func (c *Client) Project(ctx context.Context, name string) (*Project, error) {
+	if err := pep503.Validate(name); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidName, err)
+	}
+
+	var project Project
+	if err := c.getJSON(ctx, name, c.baseURL+"/"+pep503.Normalize(name)+"/json", &project); err != nil {
+		return nil, fmt.Errorf("project %s: %w", name, err)
+	}
+	return &project, nil
+}
+
+// Release возвращает метаданные одной версии проекта
+func (c *Client) Release(ctx context.Context, name string, version string) (*Release, error) {
+	if err := pep503.Validate(name); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidName, err)
+	}
+
+	endpoint := c.baseURL + "/" + pep503.Normalize(name) + "/" + url.PathEscape(version) + "/json"
+
+	var release Release
+	if err := c.getJSON(ctx, name, endpoint, &release); err != nil {
+		return nil, fmt.Errorf("release %s %s: %w", name, version, err)
+	}
+	return &release, nil
+}
+
+// Files перечисляет файлы всех релизов проекта
+func (c *Client) Files(ctx context.Context, name string) ([]File, error) {
+	project, err := c.Project(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	for _, version := range slices.Sorted(maps.Keys(project.Releases)) {
+		for _, file := range project.Releases[version] {
+			file.Version = version
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// Metadata берёт core metadata версии из блока info ответа о релизе
+func (c *Client) Metadata(ctx context.Context, name string, version string) (*Metadata, error) {
+	release, err := c.Release(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Metadata{
+		Name:           release.Info.Name,
+		Version:        release.Info.Version,
+		RequiresPython: release.Info.RequiresPython,
+		RequiresDist:   release.Info.RequiresDist,
+		ProvidesExtra:  release.Info.ProvidesExtra,
+	}, nil
+}
+
+func (c *Client) getJSON(ctx context.Context, name string, endpoint string, target any) error {
+	resp, err := c.get(ctx, name, endpoint, "application/json")
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(resp.body, target); err != nil {
+		return fmt.Errorf("%w: invalid JSON from %s: %w", ErrRequestFailed, endpoint, err)
+	}
+	return nil
+}
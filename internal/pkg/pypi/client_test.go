@@ -0,0 +1,253 @@
+package pypi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const requestsProject = `{
+	"info": {
+		"name": "requests",
+		"version": "2.32.3",
+		"summary": "Python HTTP for Humans.",
+		"requires_python": ">=3.8",
+		"requires_dist": ["charset-normalizer<4,>=2", "PySocks!=1.5.7,>=1.5.6; extra == \"socks\""],
+		"yanked": false,
+		"yanked_reason": null
+	},
+	"last_serial": 24172515,
+	"releases": {
+		"2.9.0": [{"filename": "requests-2.9.0.tar.gz", "packagetype": "sdist"}],
+		"2.32.3": [{
+			"filename": "requests-2.32.3-py3-none-any.whl",
+			"packagetype": "bdist_wheel",
+			"python_version": "py3",
+			"requires_python": ">=3.8",
+			"size": 64928,
+			"url": "https://files.pythonhosted.org/packages/requests-2.32.3-py3-none-any.whl",
+			"digests": {"sha256": "70761cfe03c773ceb22aa2f671b4757976145175cdfca038c02654d061d6dcc6"},
+			"upload_time_iso_8601": "2024-05-29T15:37:47.027801Z"
+		}],
+		"2.10.0": [{"filename": "requests-2.10.0.tar.gz", "packagetype": "sdist"}],
+		"0.0.1-dev": [],
+		"not-a-version": [{"filename": "requests-legacy.tar.gz", "packagetype": "sdist"}]
+	},
+	"urls": []
+}`
+
+func newTestClient(server *httptest.Server, maxRetries int, rateLimit int) *pypi.Client {
+	return pypi.NewClient(config.PyPIConfig{
+		APIURL:         server.URL + "/pypi/",
+		RequestTimeout: time.Second,
+		MaxRetries:     maxRetries,
+		RateLimit:      rateLimit,
+	}, pypi.WithBackoff(time.Millisecond))
+}
+
+func TestClient_Project(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/pypi/zope-interface/json", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte(requestsProject))
+	}))
+	defer server.Close()
+
+	project, err := newTestClient(server, 0, 0).Project(context.Background(), "Zope_Interface")
+	require.NoError(t, err)
+
+	assert.Equal(t, "requests", project.Info.Name)
+	assert.Equal(t, ">=3.8", project.Info.RequiresPython)
+	assert.Len(t, project.Info.RequiresDist, 2)
+	assert.Equal(t, int64(24172515), project.LastSerial)
+
+	wheel := project.Releases["2.32.3"][0]
+	assert.Equal(t, "bdist_wheel", wheel.PackageType)
+	assert.Equal(t, int64(64928), wheel.Size)
+	assert.Equal(t, "70761cfe03c773ceb22aa2f671b4757976145175cdfca038c02654d061d6dcc6", wheel.Digests["sha256"])
+	assert.Equal(t, 2024, wheel.UploadTime.Year())
+
+	var versions []string
+	for _, v := range project.Versions() {
+		versions = append(versions, v.String())
+	}
+	assert.Equal(t, []string{"2.9.0", "2.10.0", "2.32.3"}, versions)
+}
+
+func TestClient_Release(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/pypi/requests/2.32.3/json", r.URL.Path)
+		_, _ = w.Write([]byte(`{"info": {"name": "requests", "version": "2.32.3"}, "urls": [{"filename": "requests-2.32.3.tar.gz"}]}`))
+	}))
+	defer server.Close()
+
+	release, err := newTestClient(server, 0, 0).Release(context.Background(), "requests", "2.32.3")
+	require.NoError(t, err)
+	assert.Equal(t, "2.32.3", release.Info.Version)
+	require.Len(t, release.URLs, 1)
+}
+
+func TestClient_NotFoundIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server, 3, 0).Project(context.Background(), "does-not-exist")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, pypi.ErrNotFound)
+	assert.ErrorIs(t, err, pypi.ErrRequestFailed)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(requestsProject))
+	}))
+	defer server.Close()
+
+	project, err := newTestClient(server, 3, 0).Project(context.Background(), "requests")
+	require.NoError(t, err)
+	assert.Equal(t, "requests", project.Info.Name)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server, 2, 0).Project(context.Background(), "requests")
+	require.Error(t, err)
+
+	var statusErr *pypi.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_HonoursRetryAfter(t *testing.T) {
+	var (
+		calls     atomic.Int32
+		firstCall time.Time
+		retriedAt time.Time
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			firstCall = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		retriedAt = time.Now()
+		_, _ = w.Write([]byte(requestsProject))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server, 1, 0).Project(context.Background(), "requests")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, retriedAt.Sub(firstCall), time.Second)
+}
+
+func TestClient_RetryAfterIsCapped(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(requestsProject))
+	}))
+	defer server.Close()
+
+	client := pypi.NewClient(config.PyPIConfig{APIURL: server.URL, MaxRetries: 1},
+		pypi.WithMaxRetryWait(10*time.Millisecond))
+
+	start := time.Now()
+	_, err := client.Project(context.Background(), "requests")
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestClient_RequestTimeoutIsPerAttempt(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(requestsProject))
+	}))
+	defer server.Close()
+
+	client := pypi.NewClient(config.PyPIConfig{
+		APIURL:         server.URL,
+		RequestTimeout: 50 * time.Millisecond,
+		MaxRetries:     1,
+	}, pypi.WithBackoff(time.Millisecond))
+
+	_, err := client.Project(context.Background(), "requests")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestClient_ContextCancellationStopsRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := pypi.NewClient(config.PyPIConfig{APIURL: server.URL, MaxRetries: 10},
+		pypi.WithBackoff(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Project(ctx, "requests")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_RejectsInvalidNames(t *testing.T) {
+	client := pypi.NewClient(config.PyPIConfig{APIURL: "http://127.0.0.1:1"})
+
+	_, err := client.Project(context.Background(), "../admin")
+	assert.ErrorIs(t, err, pypi.ErrInvalidName)
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := pypi.NewRateLimiter(5)
+
+	start := time.Now()
+	for range 7 {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+	// Пять токенов есть сразу, ещё два пополняются за 2/5 секунды
+	assert.GreaterOrEqual(t, time.Since(start), 350*time.Millisecond)
+
+	assert.NoError(t, pypi.NewRateLimiter(0).Wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	slow := pypi.NewRateLimiter(1)
+	require.NoError(t, slow.Wait(ctx))
+	assert.ErrorIs(t, slow.Wait(ctx), context.Canceled)
+}
@@ -0,0 +1,273 @@
+package pypi
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+)
+
+const (
+	defaultBackoff      = 500 * time.Millisecond
+	defaultMaxRetryWait = 30 * time.Second
+	userAgent           = "python-deps-crawler"
+)
+
+// fetcher выполняет GET запросы к индексу с ограничением частоты и повторами
+type fetcher struct {
+	httpClient   *http.Client
+	timeout      time.Duration
+	maxRetries   int
+	backoff      time.Duration
+	maxRetryWait time.Duration
+	limiter      *RateLimiter
+	username     string
+	password     string
+	cache        *Cache
+}
+
+// Option настраивает клиент индекса
+type Option func(*fetcher)
+
+// WithHTTPClient подменяет HTTP клиент, например транспортом httptest
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(f *fetcher) {
+		f.httpClient = httpClient
+	}
+}
+
+// WithBackoff задаёт начальную паузу между повторами, она удваивается с каждой попыткой
+func WithBackoff(backoff time.Duration) Option {
+	return func(f *fetcher) {
+		f.backoff = backoff
+	}
+}
+
+// WithMaxRetryWait ограничивает паузу перед повтором, в том числе из Retry-After
+func WithMaxRetryWait(wait time.Duration) Option {
+	return func(f *fetcher) {
+		f.maxRetryWait = wait
+	}
+}
+
+// WithBasicAuth передаёт учётные данные закрытого индекса в каждом запросе
+func WithBasicAuth(username string, password string) Option {
+	return func(f *fetcher) {
+		f.username, f.password = username, password
+	}
+}
+
+// newFetcher берёт из PyPIConfig request_timeout на одну попытку,
+// max_retries повторов и rate_limit запросов в секунду
+func newFetcher(cfg config.PyPIConfig, opts []Option) fetcher {
+	f := fetcher{
+		httpClient:   http.DefaultClient,
+		timeout:      cfg.RequestTimeout,
+		maxRetries:   max(cfg.MaxRetries, 0),
+		backoff:      defaultBackoff,
+		maxRetryWait: defaultMaxRetryWait,
+		limiter:      NewRateLimiter(cfg.RateLimit),
+	}
+	for _, opt := range opts {
+		opt(&f)
+	}
+	return f
+}
+
+// response — успешный ответ индекса
+type response struct {
+	body         []byte
+	contentType  string
+	etag         string
+	lastModified string
+	// notModified — индекс подтвердил сохранённый ответ статусом 304
+	notModified bool
+}
+
+// get отдаёт свежий ответ из кеша, а просроченный перепроверяет условным запросом.
+// project — имя проекта, под которым ответ хранится в кеше
+func (f *fetcher) get(ctx context.Context, project string, endpoint string, accept string) (*response, error) {
+	if f.cache == nil {
+		return f.fetch(ctx, endpoint, accept, nil)
+	}
+
+	// Недоступность Redis не должна останавливать разрешение зависимостей
+	cached, err := f.cache.load(ctx, project, endpoint)
+	if err != nil {
+		f.cache.errors.Add(1)
+	}
+	if cached != nil && f.cache.fresh(cached, time.Now()) {
+		f.cache.hits.Add(1)
+		return &response{body: cached.Body, contentType: cached.ContentType}, nil
+	}
+
+	resp, err := f.fetch(ctx, endpoint, accept, cached)
+	if err != nil {
+		return nil, err
+	}
+	if resp.notModified {
+		f.cache.revalidations.Add(1)
+	} else {
+		f.cache.misses.Add(1)
+	}
+
+	entry := &cacheEntry{
+		Body:         resp.body,
+		ContentType:  resp.contentType,
+		ETag:         resp.etag,
+		LastModified: resp.lastModified,
+		StoredAt:     time.Now(),
+	}
+	if err := f.cache.store(ctx, project, endpoint, entry); err != nil {
+		f.cache.errors.Add(1)
+	}
+	return resp, nil
+}
+
+// fetch выполняет GET с повторами при сетевых ошибках, 429 и 5xx.
+// Если передан сохранённый ответ, запрос становится условным
+func (f *fetcher) fetch(ctx context.Context, endpoint string, accept string, cached *cacheEntry) (*response, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= f.maxRetries; attempt++ {
+		if err := f.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, retryAfter, err := f.attempt(ctx, endpoint, accept, cached)
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if retryAfter < 0 {
+			return nil, err
+		}
+		lastErr = err
+
+		if attempt == f.maxRetries {
+			break
+		}
+
+		wait := retryAfter
+		if wait == 0 {
+			wait = f.backoff << attempt
+		}
+		if err := sleep(ctx, min(wait, f.maxRetryWait)); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("giving up after %d retries: %w", f.maxRetries, lastErr)
+}
+
+// attempt делает один запрос. retryAfter < 0 означает, что повторять не нужно,
+// 0 — повторить с обычной паузой, > 0 — пауза из заголовка Retry-After
+func (f *fetcher) attempt(ctx context.Context, endpoint string, accept string, cached *cacheEntry) (*response, time.Duration, error) {
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, -1, fmt.Errorf("%w: %w", ErrRequestFailed, err)
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", userAgent)
+	for key, values := range f.authHeader() {
+		req.Header[key] = values
+	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrRequestFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: reading %s: %w", ErrRequestFailed, endpoint, err)
+		}
+		return &response{
+			body:         body,
+			contentType:  resp.Header.Get("Content-Type"),
+			etag:         resp.Header.Get("ETag"),
+			lastModified: resp.Header.Get("Last-Modified"),
+		}, 0, nil
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		// 304 может не повторять валидаторы, тогда остаются прежние
+		return &response{
+			body:         cached.Body,
+			contentType:  cached.ContentType,
+			etag:         cmp.Or(resp.Header.Get("ETag"), cached.ETag),
+			lastModified: cmp.Or(resp.Header.Get("Last-Modified"), cached.LastModified),
+			notModified:  true,
+		}, 0, nil
+	}
+
+	// Тело ошибки не нужно, но его дочитывание позволяет переиспользовать соединение
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	statusErr := &StatusError{URL: endpoint, StatusCode: resp.StatusCode}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
+		return nil, -1, statusErr
+	}
+	return nil, parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), statusErr
+}
+
+// authHeader возвращает заголовки с учётными данными индекса
+func (f *fetcher) authHeader() http.Header {
+	header := http.Header{}
+	if f.username != "" || f.password != "" {
+		req := http.Request{Header: header}
+		req.SetBasicAuth(f.username, f.password)
+	}
+	return header
+}
+
+// parseRetryAfter понимает оба формата Retry-After: секунды и HTTP дату
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
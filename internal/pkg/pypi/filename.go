@@ -0,0 +1,62 @@
+package pypi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep425"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+)
+
+var sdistExtensions = []string{".tar.gz", ".zip", ".tar.bz2", ".tar.xz", ".tgz", ".tar"}
+
+// Distribution — сведения, извлечённые из имени файла дистрибутива
+type Distribution struct {
+	Name    string
+	Version string
+	Wheel   bool
+	// Теги wheel по PEP 427: build необязателен, остальные могут быть составными через точку
+	BuildTag    string
+	PythonTag   string
+	ABITag      string
+	PlatformTag string
+}
+
+// ParseFilename разбирает имя wheel ({name}-{version}(-{build})?-{python}-{abi}-{platform}.whl)
+// или sdist ({name}-{version}.tar.gz и другие архивы)
+func ParseFilename(filename string) (Distribution, error) {
+	if strings.HasSuffix(filename, ".whl") {
+		wheel, err := pep425.ParseWheelFilename(filename)
+		if err != nil {
+			return Distribution{}, err
+		}
+
+		tags := strings.Split(wheel.TagSet, "-")
+		return Distribution{
+			Name:        wheel.Name,
+			Version:     wheel.Version,
+			Wheel:       true,
+			BuildTag:    wheel.BuildTag,
+			PythonTag:   tags[0],
+			ABITag:      tags[1],
+			PlatformTag: tags[2],
+		}, nil
+	}
+
+	for _, ext := range sdistExtensions {
+		stem, ok := strings.CutSuffix(filename, ext)
+		if !ok {
+			continue
+		}
+		// Имя sdist может содержать дефисы, поэтому версией считается
+		// самый короткий суффикс после дефиса, который разбирается как PEP 440
+		for i := strings.LastIndex(stem, "-"); i > 0; i = strings.LastIndex(stem[:i], "-") {
+			if _, err := pep440.Parse(stem[i+1:]); err == nil {
+				return Distribution{Name: stem[:i], Version: stem[i+1:]}, nil
+			}
+		}
+		return Distribution{}, fmt.Errorf("cannot find version in sdist filename %q", filename)
+	}
+
+	return Distribution{}, fmt.Errorf("unsupported distribution filename %q", filename)
+}
@@ -0,0 +1,60 @@
+// Package pypi — клиенты индексов пакетов: JSON API PyPI и Simple API
+// (PEP 503 и PEP 691) с ограничением частоты запросов, повторами при 429 и 5xx
+// и кешем ответов в Redis
+package pypi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+)
+
+var (
+	ErrNotFound            = errors.New("not found in package index")
+	ErrInvalidName         = errors.New("invalid project name")
+	ErrRequestFailed       = errors.New("package index request failed")
+	ErrMetadataUnavailable = errors.New("core metadata is not available")
+)
+
+// StatusError — ответ индекса с неожиданным HTTP статусом
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %s returned %d %s", ErrRequestFailed, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func (e *StatusError) Is(target error) bool {
+	return target == ErrRequestFailed || (target == ErrNotFound && e.StatusCode == http.StatusNotFound)
+}
+
+// Index — источник файлов и метаданных пакетов. Его реализуют Client
+// для JSON API PyPI и SimpleClient для любого индекса с Simple API
+type Index interface {
+	// Files перечисляет файлы всех релизов проекта
+	Files(ctx context.Context, name string) ([]File, error)
+	// Metadata возвращает core metadata версии без скачивания дистрибутива
+	Metadata(ctx context.Context, name string, version string) (*Metadata, error)
+}
+
+// interface check
+var (
+	_ Index = (*Client)(nil)
+	_ Index = (*SimpleClient)(nil)
+	_ Index = (*MultiIndex)(nil)
+	_ Index = (*Snapshot)(nil)
+)
+
+// NewIndex выбирает источник пакетов по PyPIConfig: в офлайн режиме снимок
+// из snapshot_path, иначе индексы из конфигурации
+func NewIndex(cfg config.PyPIConfig, opts ...Option) (Index, error) {
+	if cfg.Offline {
+		return OpenSnapshot(cfg.SnapshotPath)
+	}
+	return NewMultiIndex(cfg, opts...), nil
+}
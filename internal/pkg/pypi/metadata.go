@@ -0,0 +1,33 @@
+package pypi
+
+import "github.com/0hJonny/python-deps-crawler/internal/pkg/distmeta"
+
+// Metadata — поля core metadata (файл METADATA или PKG-INFO), нужные для разрешения зависимостей
+type Metadata struct {
+	Name           string   `json:"name"`
+	Version        string   `json:"version"`
+	RequiresPython string   `json:"requires_python,omitempty"`
+	RequiresDist   []string `json:"requires_dist,omitempty"`
+	ProvidesExtra  []string `json:"provides_extra,omitempty"`
+	// Index — имя индекса, из которого получены метаданные, заполняется MultiIndex
+	Index string `json:"-"`
+}
+
+// ParseMetadata разбирает core metadata (METADATA или PKG-INFO)
+func ParseMetadata(data []byte) (*Metadata, error) {
+	core, err := distmeta.ParseCoreMetadata(data)
+	if err != nil {
+		return nil, err
+	}
+	return metadataFromCore(core), nil
+}
+
+func metadataFromCore(core *distmeta.CoreMetadata) *Metadata {
+	return &Metadata{
+		Name:           core.Name,
+		Version:        core.Version,
+		RequiresPython: core.RequiresPython,
+		RequiresDist:   core.RequiresDist,
+		ProvidesExtra:  core.ProvidesExtra,
+	}
+}
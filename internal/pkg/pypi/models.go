@@ -0,0 +1,75 @@
+package pypi
+
+import (
+	"slices"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+)
+
+// Project — ответ /pypi/<project>/json: описание последней версии и файлы всех релизов
+type Project struct {
+	Info       Info              `json:"info"`
+	LastSerial int64             `json:"last_serial"`
+	Releases   map[string][]File `json:"releases"`
+	URLs       []File            `json:"urls"`
+}
+
+// Release — ответ /pypi/<project>/<version>/json: описание и файлы одной версии
+type Release struct {
+	Info       Info   `json:"info"`
+	LastSerial int64  `json:"last_serial"`
+	URLs       []File `json:"urls"`
+}
+
+// Info — метаданные версии из блока info
+type Info struct {
+	Name           string            `json:"name"`
+	Version        string            `json:"version"`
+	Summary        string            `json:"summary"`
+	License        string            `json:"license"`
+	HomePage       string            `json:"home_page"`
+	ProjectURLs    map[string]string `json:"project_urls"`
+	RequiresDist   []string          `json:"requires_dist"`
+	RequiresPython string            `json:"requires_python"`
+	ProvidesExtra  []string          `json:"provides_extra"`
+	Yanked         bool              `json:"yanked"`
+	YankedReason   string            `json:"yanked_reason"`
+}
+
+// File — дистрибутив релиза: wheel или sdist
+type File struct {
+	// Version заполняется клиентом: в JSON API из ключа releases, в Simple API из имени файла.
+	// Index — имя индекса, из которого получен файл, его заполняет MultiIndex
+	Version        string            `json:"-"`
+	Index          string            `json:"-"`
+	Filename       string            `json:"filename"`
+	PackageType    string            `json:"packagetype"`
+	PythonVersion  string            `json:"python_version"`
+	RequiresPython string            `json:"requires_python"`
+	Size           int64             `json:"size"`
+	URL            string            `json:"url"`
+	Digests        map[string]string `json:"digests"`
+	UploadTime     time.Time         `json:"upload_time_iso_8601"`
+	Yanked         bool              `json:"yanked"`
+	YankedReason   string            `json:"yanked_reason"`
+	// CoreMetadata сообщает, что индекс отдаёт METADATA файла отдельно по PEP 658
+	CoreMetadata       bool              `json:"-"`
+	CoreMetadataHashes map[string]string `json:"-"`
+}
+
+// Versions возвращает по возрастанию версии, у которых есть хотя бы один файл.
+// Версии, не соответствующие PEP 440, пропускаются, как это делает pip
+func (p *Project) Versions() []pep440.Version {
+	versions := make([]pep440.Version, 0, len(p.Releases))
+	for raw, files := range p.Releases {
+		if len(files) == 0 {
+			continue
+		}
+		if version, err := pep440.Parse(raw); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	slices.SortFunc(versions, pep440.Version.Compare)
+	return versions
+}
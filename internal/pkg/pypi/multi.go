@@ -0,0 +1,156 @@
+package pypi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sync"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+)
+
+// ErrNoIndex — правило маршрутизации не оставило индексов для пакета
+var ErrNoIndex = errors.New("no package index allowed for project")
+
+// MultiIndex опрашивает индексы по приоритету и берёт проект целиком из первого,
+// где он найден. Версии из разных индексов не смешиваются: так внутренний
+// пакет нельзя подменить более новой версией с тем же именем из публичного индекса
+type MultiIndex struct {
+	indexes []namedIndex
+	routes  []config.RouteConfig
+
+	mu     sync.Mutex
+	owners map[string]string
+}
+
+type namedIndex struct {
+	name  string
+	index Index
+}
+
+// NewMultiIndex создаёт клиенты для всех индексов из PyPIConfig.
+// Без списка indexes используется один JSON индекс по адресу api_url
+func NewMultiIndex(cfg config.PyPIConfig, opts ...Option) *MultiIndex {
+	indexes := slices.Clone(cfg.Indexes)
+	if len(indexes) == 0 {
+		indexes = []config.IndexConfig{{Name: "pypi", URL: cfg.APIURL, API: config.IndexAPIJSON}}
+	}
+	slices.SortStableFunc(indexes, func(a, b config.IndexConfig) int {
+		return a.Priority - b.Priority
+	})
+
+	m := &MultiIndex{
+		routes: cfg.Routes,
+		owners: make(map[string]string),
+	}
+	for _, index := range indexes {
+		indexOpts := append(slices.Clone(opts), WithBasicAuth(index.Username, index.Password))
+
+		var client Index
+		if index.API == config.IndexAPIJSON {
+			indexCfg := cfg
+			indexCfg.APIURL = index.URL
+			client = NewClient(indexCfg, indexOpts...)
+		} else {
+			client = NewSimpleClient(index.URL, cfg, indexOpts...)
+		}
+		m.indexes = append(m.indexes, namedIndex{name: index.Name, index: client})
+	}
+	return m
+}
+
+// Files возвращает файлы проекта из первого по приоритету разрешённого индекса,
+// в котором проект есть. Каждый файл помечается именем индекса
+func (m *MultiIndex) Files(ctx context.Context, name string) ([]File, error) {
+	candidates, err := m.candidates(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range candidates {
+		files, err := candidate.index.Files(ctx, name)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		// Любая другая ошибка прерывает поиск: переход к следующему индексу
+		// из-за сбоя приватного открыл бы дорогу подмене зависимости
+		if err != nil {
+			return nil, fmt.Errorf("index %s: %w", candidate.name, err)
+		}
+
+		for i := range files {
+			files[i].Index = candidate.name
+		}
+		m.setOwner(name, candidate.name)
+		return files, nil
+	}
+
+	return nil, fmt.Errorf("project %s: %w", name, ErrNotFound)
+}
+
+// Metadata берёт метаданные из того же индекса, что отдал файлы проекта
+func (m *MultiIndex) Metadata(ctx context.Context, name string, version string) (*Metadata, error) {
+	owner, ok := m.owner(name)
+	if !ok {
+		if _, err := m.Files(ctx, name); err != nil {
+			return nil, err
+		}
+		owner, _ = m.owner(name)
+	}
+
+	index := m.indexes[slices.IndexFunc(m.indexes, func(i namedIndex) bool { return i.name == owner })]
+	metadata, err := index.index.Metadata(ctx, name, version)
+	if err != nil {
+		return nil, fmt.Errorf("index %s: %w", owner, err)
+	}
+	metadata.Index = owner
+	return metadata, nil
+}
+
+// candidates возвращает индексы, которым разрешено отдавать проект, в порядке приоритета
+func (m *MultiIndex) candidates(name string) ([]namedIndex, error) {
+	if err := pep503.Validate(name); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidName, err)
+	}
+	normalized := pep503.Normalize(name)
+
+	for _, route := range m.routes {
+		if !slices.ContainsFunc(route.Packages, func(pattern string) bool {
+			matched, _ := path.Match(pep503.Normalize(pattern), normalized)
+			return matched
+		}) {
+			continue
+		}
+
+		var allowed []namedIndex
+		for _, index := range m.indexes {
+			if slices.Contains(route.Indexes, index.name) {
+				allowed = append(allowed, index)
+			}
+		}
+		if len(allowed) == 0 {
+			return nil, fmt.Errorf("project %s: %w", name, ErrNoIndex)
+		}
+		return allowed, nil
+	}
+
+	return m.indexes, nil
+}
+
+func (m *MultiIndex) owner(name string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	owner, ok := m.owners[pep503.Normalize(name)]
+	return owner, ok
+}
+
+func (m *MultiIndex) setOwner(name string, index string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.owners[pep503.Normalize(name)] = index
+}
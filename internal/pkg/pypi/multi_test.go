@@ -0,0 +1,129 @@
+package pypi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIndexServer отдаёт Simple API страницы для перечисленных проектов с одной версией каждый
+func newIndexServer(t *testing.T, versions map[string]string, check func(r *http.Request) int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if check != nil {
+			if status := check(r); status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+		}
+
+		project := strings.Trim(strings.TrimPrefix(r.URL.Path, "/simple/"), "/")
+		if name, ok := strings.CutSuffix(project, ".metadata"); ok {
+			dist, _ := pypi.ParseFilename(strings.TrimPrefix(name, "files/"))
+			_, _ = fmt.Fprintf(w, "Name: %s\nVersion: %s\n", dist.Name, dist.Version)
+			return
+		}
+
+		version, ok := versions[project]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		filename := fmt.Sprintf("%s-%s-py3-none-any.whl", strings.ReplaceAll(project, "-", "_"), version)
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprintf(w, `<a href="/files/%s" data-core-metadata="true">%s</a>`, filename, filename)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMultiIndex_RoutingAndProvenance(t *testing.T) {
+	internal := newIndexServer(t, map[string]string{"acme-utils": "1.0.0", "requests": "2.0.0"}, func(r *http.Request) int {
+		if username, password, ok := r.BasicAuth(); !ok || username != "ci" || password != "secret" {
+			return http.StatusUnauthorized
+		}
+		return http.StatusOK
+	})
+	// Публичный индекс содержит более новую версию внутреннего пакета — попытку подмены
+	public := newIndexServer(t, map[string]string{"acme-utils": "99.0.0", "acme-tools": "1.0.0", "requests": "2.32.3", "attrs": "23.2.0"}, nil)
+
+	index := pypi.NewMultiIndex(config.PyPIConfig{
+		RequestTimeout: time.Second,
+		Indexes: []config.IndexConfig{
+			{Name: "public", URL: public.URL + "/simple", API: config.IndexAPISimple, Priority: 10},
+			{Name: "internal", URL: internal.URL + "/simple", API: config.IndexAPISimple, Username: "ci", Password: "secret"},
+		},
+		Routes: []config.RouteConfig{
+			{Packages: []string{"ACME_*"}, Indexes: []string{"internal"}},
+		},
+	})
+
+	tests := []struct {
+		name    string
+		index   string
+		version string
+	}{
+		{"acme.utils", "internal", "1.0.0"},
+		{"requests", "internal", "2.0.0"},
+		{"attrs", "public", "23.2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := index.Files(context.Background(), tt.name)
+			require.NoError(t, err)
+			require.Len(t, files, 1)
+			assert.Equal(t, tt.index, files[0].Index)
+			assert.Equal(t, tt.version, files[0].Version)
+
+			metadata, err := index.Metadata(context.Background(), tt.name, tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, tt.index, metadata.Index)
+			assert.Equal(t, tt.version, metadata.Version)
+		})
+	}
+
+	// acme-tools есть только в публичном индексе, но правило запрещает брать его оттуда
+	_, err := index.Files(context.Background(), "acme-tools")
+	assert.ErrorIs(t, err, pypi.ErrNotFound)
+}
+
+func TestMultiIndex_StopsOnIndexFailure(t *testing.T) {
+	broken := newIndexServer(t, nil, func(r *http.Request) int { return http.StatusUnauthorized })
+	public := newIndexServer(t, map[string]string{"acme-utils": "99.0.0"}, nil)
+
+	index := pypi.NewMultiIndex(config.PyPIConfig{
+		Indexes: []config.IndexConfig{
+			{Name: "internal", URL: broken.URL + "/simple", API: config.IndexAPISimple, Priority: 1},
+			{Name: "public", URL: public.URL + "/simple", API: config.IndexAPISimple, Priority: 2},
+		},
+	})
+
+	_, err := index.Files(context.Background(), "acme-utils")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, pypi.ErrRequestFailed)
+	assert.NotErrorIs(t, err, pypi.ErrNotFound)
+	assert.Contains(t, err.Error(), "index internal")
+}
+
+func TestMultiIndex_DefaultsToAPIURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/pypi/attrs/json", r.URL.Path)
+		_, _ = w.Write([]byte(`{"releases": {"23.2.0": [{"filename": "attrs-23.2.0.tar.gz"}]}}`))
+	}))
+	defer server.Close()
+
+	files, err := pypi.NewMultiIndex(config.PyPIConfig{APIURL: server.URL + "/pypi"}).Files(context.Background(), "attrs")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "pypi", files[0].Index)
+}
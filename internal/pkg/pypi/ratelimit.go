@@ -0,0 +1,69 @@
+package pypi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter — token bucket: ведро на rate токенов пополняется
+// со скоростью rate в секунду, каждый запрос забирает один токен
+type RateLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+// NewRateLimiter создаёт ограничитель на rate запросов в секунду.
+// При rate <= 0 ограничения нет и возвращается nil
+func NewRateLimiter(rate int) *RateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &RateLimiter{
+		rate:     float64(rate),
+		capacity: float64(rate),
+		tokens:   float64(rate),
+		last:     time.Now(),
+	}
+}
+
+// Wait блокируется, пока не появится свободный токен или не отменится контекст
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	for {
+		delay := l.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve забирает токен и возвращает 0 или время до появления следующего токена
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = min(l.capacity, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
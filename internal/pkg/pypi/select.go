@@ -0,0 +1,69 @@
+package pypi
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep425"
+)
+
+// RankedWheel — wheel, подходящий целевой платформе. Меньший Rank предпочтительнее
+type RankedWheel struct {
+	File File
+	Rank int
+}
+
+// Selection — файлы одной версии, разобранные по совместимости с платформой
+type Selection struct {
+	// Wheels — совместимые wheel от лучшего к худшему
+	Wheels []RankedWheel
+	// Incompatible — wheel, собранные под другие платформы или интерпретаторы
+	Incompatible []File
+	Sdists       []File
+}
+
+// SelectFiles ранжирует wheel по тегам платформы так же, как pip.
+// Файлы с неразборчивыми именами пропускаются
+func SelectFiles(files []File, compat *pep425.Compatibility) Selection {
+	var selection Selection
+	for _, file := range files {
+		if !strings.HasSuffix(file.Filename, ".whl") {
+			if _, err := ParseFilename(file.Filename); err == nil {
+				selection.Sdists = append(selection.Sdists, file)
+			}
+			continue
+		}
+
+		wheel, err := pep425.ParseWheelFilename(file.Filename)
+		if err != nil {
+			continue
+		}
+		if rank, ok := compat.Rank(wheel.Tags); ok {
+			selection.Wheels = append(selection.Wheels, RankedWheel{File: file, Rank: rank})
+		} else {
+			selection.Incompatible = append(selection.Incompatible, file)
+		}
+	}
+
+	slices.SortStableFunc(selection.Wheels, func(a, b RankedWheel) int {
+		return cmp.Compare(a.Rank, b.Rank)
+	})
+	return selection
+}
+
+// Best возвращает файл для установки: лучший wheel, иначе sdist
+func (s Selection) Best() (File, bool) {
+	if len(s.Wheels) > 0 {
+		return s.Wheels[0].File, true
+	}
+	if len(s.Sdists) > 0 {
+		return s.Sdists[0], true
+	}
+	return File{}, false
+}
+
+// SdistOnly сообщает, что подходящих wheel нет и пакет придётся собирать из sdist
+func (s Selection) SdistOnly() bool {
+	return len(s.Wheels) == 0 && len(s.Sdists) > 0
+}
@@ -0,0 +1,339 @@
+package pypi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/distmeta"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+	"golang.org/x/net/html"
+)
+
+const (
+	simpleJSONType = "application/vnd.pypi.simple.v1+json"
+	simpleHTMLType = "application/vnd.pypi.simple.v1+html"
+	// PEP 691: JSON предпочтительнее, HTML поддерживают все индексы
+	simpleAccept = simpleJSONType + ", " + simpleHTMLType + ";q=0.2, text/html;q=0.01"
+)
+
+// SimpleClient работает с любым индексом, который реализует Simple API:
+// PyPI, devpi, Artifactory или каталог, раздаваемый веб-сервером
+type SimpleClient struct {
+	fetcher
+	indexURL string
+}
+
+// NewSimpleClient создаёт клиент для индекса с адресом вида https://pypi.org/simple.
+// Таймауты, повторы и rate limit берутся из PyPIConfig
+func NewSimpleClient(indexURL string, cfg config.PyPIConfig, opts ...Option) *SimpleClient {
+	return &SimpleClient{
+		fetcher:  newFetcher(cfg, opts),
+		indexURL: strings.TrimRight(indexURL, "/"),
+	}
+}
+
+// Files перечисляет файлы проекта со страницы /<project>/ в формате JSON или HTML.
+// Файлы, из имени которых не удаётся извлечь версию, пропускаются
+func (c *SimpleClient) Files(ctx context.Context, name string) ([]File, error) {
+	if err := pep503.Validate(name); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidName, err)
+	}
+
+	endpoint := c.indexURL + "/" + pep503.Normalize(name) + "/"
+	resp, err := c.get(ctx, name, endpoint, simpleAccept)
+	if err != nil {
+		return nil, fmt.Errorf("project %s: %w", name, err)
+	}
+
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRequestFailed, err)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.contentType)
+	var files []File
+	switch mediaType {
+	case simpleJSONType:
+		files, err = parseSimpleJSON(resp.body, base)
+	case simpleHTMLType, "text/html", "":
+		files, err = parseSimpleHTML(resp.body, base)
+	default:
+		err = fmt.Errorf("unsupported content type %q", resp.contentType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: project %s at %s: %w", ErrRequestFailed, name, endpoint, err)
+	}
+
+	result := files[:0]
+	for _, file := range files {
+		dist, err := ParseFilename(file.Filename)
+		if err != nil {
+			continue
+		}
+		file.Version = dist.Version
+		result = append(result, file)
+	}
+	return result, nil
+}
+
+// Metadata скачивает METADATA версии по PEP 658, предпочитая wheel.
+// Если индекс не отдаёт метаданные отдельно, они читаются из самого
+// дистрибутива Range запросами без загрузки файла целиком
+func (c *SimpleClient) Metadata(ctx context.Context, name string, version string) (*Metadata, error) {
+	target, err := pep440.Parse(version)
+	if err != nil {
+		return nil, fmt.Errorf("release %s %s: %w", name, version, err)
+	}
+
+	files, err := c.Files(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidate, distribution *File
+	for i, file := range files {
+		fileVersion, err := pep440.Parse(file.Version)
+		if err != nil || !fileVersion.Equal(target) {
+			continue
+		}
+		if preferFile(file, distribution) {
+			distribution = &files[i]
+		}
+		if file.CoreMetadata && preferFile(file, candidate) {
+			candidate = &files[i]
+		}
+	}
+	if distribution == nil {
+		return nil, fmt.Errorf("release %s %s: %w", name, version, ErrNotFound)
+	}
+	if candidate == nil {
+		return c.extractMetadata(ctx, distribution)
+	}
+
+	resp, err := c.get(ctx, name, candidate.URL+".metadata", "*/*")
+	if err != nil {
+		return nil, fmt.Errorf("metadata of %s: %w", candidate.Filename, err)
+	}
+	if err := verifyHashes(resp.body, candidate.CoreMetadataHashes); err != nil {
+		return nil, fmt.Errorf("%w: metadata of %s: %w", ErrRequestFailed, candidate.Filename, err)
+	}
+
+	metadata, err := ParseMetadata(resp.body)
+	if err != nil {
+		return nil, fmt.Errorf("metadata of %s: %w", candidate.Filename, err)
+	}
+	return metadata, nil
+}
+
+func (c *SimpleClient) extractMetadata(ctx context.Context, file *File) (*Metadata, error) {
+	core, err := distmeta.Extract(ctx, c.httpClient, file.URL, c.authHeader(), file.Filename)
+	if errors.Is(err, distmeta.ErrMetadataNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrMetadataUnavailable, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: metadata of %s: %w", ErrRequestFailed, file.Filename, err)
+	}
+	return metadataFromCore(core), nil
+}
+
+// preferFile сообщает, что file лучше текущего выбора: wheel предпочтительнее sdist
+func preferFile(file File, current *File) bool {
+	return current == nil || strings.HasSuffix(file.Filename, ".whl") && !strings.HasSuffix(current.Filename, ".whl")
+}
+
+// simpleJSONPage — страница проекта по PEP 691
+type simpleJSONPage struct {
+	Meta struct {
+		APIVersion string `json:"api-version"`
+	} `json:"meta"`
+	Files []struct {
+		Filename       string            `json:"filename"`
+		URL            string            `json:"url"`
+		Hashes         map[string]string `json:"hashes"`
+		RequiresPython string            `json:"requires-python"`
+		// PEP 714 переименовал dist-info-metadata в core-metadata, старые индексы отдают только прежнее имя
+		CoreMetadata     json.RawMessage `json:"core-metadata"`
+		DistInfoMetadata json.RawMessage `json:"dist-info-metadata"`
+		Yanked           json.RawMessage `json:"yanked"`
+		Size             int64           `json:"size"`
+		UploadTime       string          `json:"upload-time"`
+	} `json:"files"`
+}
+
+func parseSimpleJSON(body []byte, base *url.URL) ([]File, error) {
+	var page simpleJSONPage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if major, _, _ := strings.Cut(page.Meta.APIVersion, "."); major != "1" {
+		return nil, fmt.Errorf("unsupported Simple API version %q", page.Meta.APIVersion)
+	}
+
+	files := make([]File, 0, len(page.Files))
+	for _, f := range page.Files {
+		file := File{
+			Filename:       f.Filename,
+			URL:            resolveURL(base, f.URL),
+			Digests:        f.Hashes,
+			RequiresPython: f.RequiresPython,
+			Size:           f.Size,
+		}
+		if t, err := time.Parse(time.RFC3339, f.UploadTime); err == nil {
+			file.UploadTime = t
+		}
+
+		metadata := f.CoreMetadata
+		if len(metadata) == 0 {
+			metadata = f.DistInfoMetadata
+		}
+		// Значение — true, false или словарь хешей файла метаданных
+		var hashes map[string]string
+		if json.Unmarshal(metadata, &hashes) == nil && hashes != nil {
+			file.CoreMetadata, file.CoreMetadataHashes = true, hashes
+		} else {
+			_ = json.Unmarshal(metadata, &file.CoreMetadata)
+		}
+
+		// yanked — false, true или строка с причиной
+		if json.Unmarshal(f.Yanked, &file.YankedReason) == nil {
+			file.Yanked = true
+		} else {
+			_ = json.Unmarshal(f.Yanked, &file.Yanked)
+		}
+
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// parseSimpleHTML разбирает ссылки страницы PEP 503 вместе с атрибутами data-*
+func parseSimpleHTML(body []byte, base *url.URL) ([]File, error) {
+	var (
+		files   []File
+		current *File
+	)
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF {
+				return nil, fmt.Errorf("invalid HTML: %w", err)
+			}
+			return files, nil
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "base":
+				if href := attr(token, "href"); href != "" {
+					if parsed, err := base.Parse(href); err == nil {
+						base = parsed
+					}
+				}
+			case "a":
+				current = anchorFile(token, base)
+			}
+
+		case html.TextToken:
+			if current != nil {
+				current.Filename += string(tokenizer.Text())
+			}
+
+		case html.EndTagToken:
+			if tokenizer.Token().Data == "a" && current != nil {
+				current.Filename = strings.TrimSpace(current.Filename)
+				files = append(files, *current)
+				current = nil
+			}
+		}
+	}
+}
+
+func anchorFile(token html.Token, base *url.URL) *File {
+	href, fragment, _ := strings.Cut(attr(token, "href"), "#")
+	file := &File{
+		URL:            resolveURL(base, href),
+		RequiresPython: attr(token, "data-requires-python"),
+	}
+
+	// Хеш файла передаётся во фрагменте ссылки: #sha256=<hex>
+	if algorithm, digest, ok := strings.Cut(fragment, "="); ok {
+		file.Digests = map[string]string{algorithm: digest}
+	}
+
+	if reason, ok := attrValue(token, "data-yanked"); ok {
+		file.Yanked, file.YankedReason = true, reason
+	}
+
+	metadata, ok := attrValue(token, "data-core-metadata")
+	if !ok {
+		metadata, ok = attrValue(token, "data-dist-info-metadata")
+	}
+	if ok && metadata != "false" {
+		file.CoreMetadata = true
+		if algorithm, digest, found := strings.Cut(metadata, "="); found {
+			file.CoreMetadataHashes = map[string]string{algorithm: digest}
+		}
+	}
+
+	return file
+}
+
+func attr(token html.Token, name string) string {
+	value, _ := attrValue(token, name)
+	return value
+}
+
+func attrValue(token html.Token, name string) (string, bool) {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func resolveURL(base *url.URL, ref string) string {
+	parsed, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return parsed.String()
+}
+
+// verifyHashes сверяет содержимое с известными индексу хешами; неизвестные алгоритмы пропускаются
+func verifyHashes(data []byte, hashes map[string]string) error {
+	for algorithm, expected := range hashes {
+		var h hash.Hash
+		switch algorithm {
+		case "sha256":
+			h = sha256.New()
+		case "sha384":
+			h = sha512.New384()
+		case "sha512":
+			h = sha512.New()
+		default:
+			continue
+		}
+		h.Write(data)
+		if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, expected) {
+			return fmt.Errorf("%s mismatch: expected %s, got %s", algorithm, expected, actual)
+		}
+	}
+	return nil
+}
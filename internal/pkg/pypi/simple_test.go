@@ -0,0 +1,307 @@
+package pypi_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep425"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const attrsMetadata = `Metadata-Version: 2.1
+Name: attrs
+Version: 23.2.0
+Summary: Classes Without Boilerplate
+Requires-Python: >=3.7
+Provides-Extra: tests
+Requires-Dist: importlib-metadata; python_version < "3.8"
+Requires-Dist: pytest>=4.3.0;
+  extra == "tests"
+
+attrs is the Python package that will bring back the joy of writing classes.
+Requires-Dist: not-a-header
+`
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// newSimpleServer отдаёт страницу проекта attrs в формате, который выбирает contentType
+func newSimpleServer(t *testing.T, contentType string, page string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/simple/attrs/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Accept"), "application/vnd.pypi.simple.v1+json")
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write([]byte(page))
+	})
+	mux.HandleFunc("/files/attrs-23.2.0-py3-none-any.whl.metadata", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(attrsMetadata))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newSimpleClient(server *httptest.Server) *pypi.SimpleClient {
+	return pypi.NewSimpleClient(server.URL+"/simple/", config.PyPIConfig{RequestTimeout: time.Second})
+}
+
+func TestSimpleClient_JSONPage(t *testing.T) {
+	page := `{
+		"meta": {"api-version": "1.1"},
+		"name": "attrs",
+		"files": [
+			{"filename": "attrs-23.1.0.tar.gz", "url": "https://files.example.com/attrs-23.1.0.tar.gz",
+			 "hashes": {"sha256": "aaa"}, "requires-python": ">=3.7", "yanked": "broken release"},
+			{"filename": "attrs-23.2.0-py3-none-any.whl", "url": "../../files/attrs-23.2.0-py3-none-any.whl",
+			 "hashes": {"sha256": "bbb"}, "core-metadata": {"sha256": "` + sha256Hex(attrsMetadata) + `"},
+			 "size": 60152, "upload-time": "2023-12-31T06:30:30.772444Z"},
+			{"filename": "attrs-23.2.0.tar.gz", "url": "../../files/attrs-23.2.0.tar.gz",
+			 "dist-info-metadata": true, "yanked": false},
+			{"filename": "attrs.egg-info", "url": "../../files/attrs.egg-info"}
+		]
+	}`
+	server := newSimpleServer(t, "application/vnd.pypi.simple.v1+json", page)
+
+	files, err := newSimpleClient(server).Files(context.Background(), "Attrs")
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	assert.Equal(t, "23.1.0", files[0].Version)
+	assert.True(t, files[0].Yanked)
+	assert.Equal(t, "broken release", files[0].YankedReason)
+	assert.Equal(t, ">=3.7", files[0].RequiresPython)
+	assert.False(t, files[0].CoreMetadata)
+
+	assert.Equal(t, server.URL+"/files/attrs-23.2.0-py3-none-any.whl", files[1].URL)
+	assert.Equal(t, "bbb", files[1].Digests["sha256"])
+	assert.True(t, files[1].CoreMetadata)
+	assert.Equal(t, int64(60152), files[1].Size)
+	assert.Equal(t, 2023, files[1].UploadTime.Year())
+
+	assert.True(t, files[2].CoreMetadata)
+	assert.False(t, files[2].Yanked)
+}
+
+func TestSimpleClient_HTMLPage(t *testing.T) {
+	page := `<!DOCTYPE html>
+<html><body>
+<h1>Links for attrs</h1>
+<a href="https://files.example.com/attrs-23.1.0.tar.gz#sha256=aaa" data-requires-python="&gt;=3.7" data-yanked="">attrs-23.1.0.tar.gz</a><br/>
+<a href="../../files/attrs-23.2.0-py3-none-any.whl#sha256=bbb" data-requires-python="&gt;=3.7"
+   data-dist-info-metadata="sha256=` + sha256Hex(attrsMetadata) + `" data-core-metadata="sha256=` + sha256Hex(attrsMetadata) + `">attrs-23.2.0-py3-none-any.whl</a><br/>
+</body></html>`
+	server := newSimpleServer(t, "text/html; charset=utf-8", page)
+
+	files, err := newSimpleClient(server).Files(context.Background(), "attrs")
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	assert.Equal(t, "https://files.example.com/attrs-23.1.0.tar.gz", files[0].URL)
+	assert.Equal(t, map[string]string{"sha256": "aaa"}, files[0].Digests)
+	assert.Equal(t, ">=3.7", files[0].RequiresPython)
+	assert.True(t, files[0].Yanked)
+	assert.Empty(t, files[0].YankedReason)
+
+	assert.Equal(t, "23.2.0", files[1].Version)
+	assert.Equal(t, server.URL+"/files/attrs-23.2.0-py3-none-any.whl", files[1].URL)
+	assert.True(t, files[1].CoreMetadata)
+	assert.Equal(t, sha256Hex(attrsMetadata), files[1].CoreMetadataHashes["sha256"])
+
+	metadata, err := newSimpleClient(server).Metadata(context.Background(), "attrs", "23.2")
+	require.NoError(t, err)
+	assert.Equal(t, "attrs", metadata.Name)
+	assert.Equal(t, []string{`importlib-metadata; python_version < "3.8"`, `pytest>=4.3.0; extra == "tests"`}, metadata.RequiresDist)
+}
+
+func TestSimpleClient_MetadataHashMismatch(t *testing.T) {
+	page := `<a href="../../files/attrs-23.2.0-py3-none-any.whl" data-core-metadata="sha256=deadbeef">attrs-23.2.0-py3-none-any.whl</a>`
+	server := newSimpleServer(t, "text/html", page)
+
+	_, err := newSimpleClient(server).Metadata(context.Background(), "attrs", "23.2.0")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, pypi.ErrRequestFailed)
+	assert.Contains(t, err.Error(), "sha256 mismatch")
+}
+
+func TestSimpleClient_MetadataFromDistribution(t *testing.T) {
+	dir := t.TempDir()
+	writeWheel(t, filepath.Join(dir, "attrs-23.2.0-py3-none-any.whl"), map[string]string{
+		"attrs-23.2.0.dist-info/METADATA": attrsMetadata,
+		"attrs-23.2.0.dist-info/WHEEL":    "Wheel-Version: 1.0\nTag: py3-none-any\n",
+	})
+	writeSdist(t, filepath.Join(dir, "attrs-23.1.0.tar.gz"), map[string]string{
+		"attrs-23.1.0/setup.py": "",
+	})
+
+	var ranged atomic.Int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/simple/attrs/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<a href="../../files/attrs-23.1.0.tar.gz">attrs-23.1.0.tar.gz</a>
+<a href="../../files/attrs-23.2.0.tar.gz">attrs-23.2.0.tar.gz</a>
+<a href="../../files/attrs-23.2.0-py3-none-any.whl">attrs-23.2.0-py3-none-any.whl</a>`))
+	})
+	mux.HandleFunc("/files/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" {
+			ranged.Add(1)
+		}
+		http.ServeFile(w, r, filepath.Join(dir, strings.TrimPrefix(r.URL.Path, "/files/")))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := newSimpleClient(server)
+
+	metadata, err := client.Metadata(context.Background(), "attrs", "23.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, "23.2.0", metadata.Version)
+	assert.Equal(t, []string{"tests"}, metadata.ProvidesExtra)
+	assert.Positive(t, ranged.Load())
+
+	_, err = client.Metadata(context.Background(), "attrs", "23.1.0")
+	assert.ErrorIs(t, err, pypi.ErrMetadataUnavailable)
+
+	_, err = client.Metadata(context.Background(), "attrs", "1.0")
+	assert.ErrorIs(t, err, pypi.ErrNotFound)
+}
+
+func TestSimpleClient_UnsupportedAPIVersion(t *testing.T) {
+	server := newSimpleServer(t, "application/vnd.pypi.simple.v1+json", `{"meta": {"api-version": "2.0"}, "files": []}`)
+
+	_, err := newSimpleClient(server).Files(context.Background(), "attrs")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported Simple API version "2.0"`)
+}
+
+func TestIndex_BackendsAreInterchangeable(t *testing.T) {
+	jsonServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/23.2.0/json") {
+			_, _ = w.Write([]byte(`{"info": {"name": "attrs", "version": "23.2.0", "requires_python": ">=3.7",
+				"requires_dist": ["importlib-metadata; python_version < \"3.8\""], "provides_extra": ["tests"]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"info": {"name": "attrs"}, "releases": {
+			"23.2.0": [{"filename": "attrs-23.2.0-py3-none-any.whl"}]}}`))
+	}))
+	defer jsonServer.Close()
+
+	page := `<a href="../../files/attrs-23.2.0-py3-none-any.whl" data-core-metadata="true">attrs-23.2.0-py3-none-any.whl</a>`
+	simpleServer := newSimpleServer(t, "text/html", page)
+
+	indexes := []pypi.Index{
+		pypi.NewClient(config.PyPIConfig{APIURL: jsonServer.URL}),
+		newSimpleClient(simpleServer),
+	}
+	for _, index := range indexes {
+		files, err := index.Files(context.Background(), "attrs")
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, "23.2.0", files[0].Version)
+
+		metadata, err := index.Metadata(context.Background(), "attrs", "23.2.0")
+		require.NoError(t, err)
+		assert.Equal(t, ">=3.7", metadata.RequiresPython)
+		assert.Equal(t, []string{"tests"}, metadata.ProvidesExtra)
+		assert.Equal(t, `importlib-metadata; python_version < "3.8"`, metadata.RequiresDist[0])
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	metadata, err := pypi.ParseMetadata([]byte(strings.ReplaceAll(attrsMetadata, "\n", "\r\n")))
+	require.NoError(t, err)
+
+	assert.Equal(t, "attrs", metadata.Name)
+	assert.Equal(t, "23.2.0", metadata.Version)
+	assert.Equal(t, ">=3.7", metadata.RequiresPython)
+	assert.Equal(t, []string{"tests"}, metadata.ProvidesExtra)
+	assert.Len(t, metadata.RequiresDist, 2)
+
+	_, err = pypi.ParseMetadata([]byte("Metadata-Version: 2.1\nSummary: no name\n"))
+	assert.Error(t, err)
+}
+
+func TestParseFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected pypi.Distribution
+	}{
+		{"requests-2.32.3-py3-none-any.whl", pypi.Distribution{
+			Name: "requests", Version: "2.32.3", Wheel: true, PythonTag: "py3", ABITag: "none", PlatformTag: "any"}},
+		{"numpy-1.26.4-1-cp312-cp312-manylinux_2_17_x86_64.manylinux2014_x86_64.whl", pypi.Distribution{
+			Name: "numpy", Version: "1.26.4", Wheel: true, BuildTag: "1", PythonTag: "cp312", ABITag: "cp312",
+			PlatformTag: "manylinux_2_17_x86_64.manylinux2014_x86_64"}},
+		{"python-dateutil-2.8.2.tar.gz", pypi.Distribution{Name: "python-dateutil", Version: "2.8.2"}},
+		{"Django-5.0.zip", pypi.Distribution{Name: "Django", Version: "5.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			dist, err := pypi.ParseFilename(tt.filename)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, dist)
+		})
+	}
+
+	for _, filename := range []string{"pkg-1.0.egg", "pkg-py3-none-any.whl", "pkg.tar.gz", "pkg-latest-py3-none-any.whl"} {
+		_, err := pypi.ParseFilename(filename)
+		assert.Error(t, err, filename)
+	}
+}
+
+func TestSelectFiles(t *testing.T) {
+	files := func(names ...string) []pypi.File {
+		result := make([]pypi.File, len(names))
+		for i, name := range names {
+			result[i] = pypi.File{Filename: name}
+		}
+		return result
+	}
+	numpy := files(
+		"numpy-1.26.4.tar.gz",
+		"numpy-1.26.4-cp311-cp311-macosx_11_0_arm64.whl",
+		"numpy-1.26.4-cp312-cp312-macosx_11_0_arm64.whl",
+		"numpy-1.26.4-cp312-cp312-manylinux_2_17_x86_64.manylinux2014_x86_64.whl",
+		"numpy-1.26.4-cp312-cp312-win_amd64.whl",
+	)
+
+	linux, err := pep425.NewCompatibility(pep425.Platform{PythonVersion: "3.12"})
+	require.NoError(t, err)
+	selection := pypi.SelectFiles(numpy, linux)
+	best, ok := selection.Best()
+	require.True(t, ok)
+	assert.Equal(t, numpy[3].Filename, best.Filename)
+	assert.Len(t, selection.Incompatible, 3)
+	assert.False(t, selection.SdistOnly())
+
+	mac, err := pep425.NewCompatibility(pep425.Platform{PythonVersion: "3.12", OS: "macos", Arch: "arm64"})
+	require.NoError(t, err)
+	best, _ = pypi.SelectFiles(numpy, mac).Best()
+	assert.Equal(t, numpy[2].Filename, best.Filename)
+
+	musl, err := pep425.NewCompatibility(pep425.Platform{PythonVersion: "3.12", Libc: "musl"})
+	require.NoError(t, err)
+	selection = pypi.SelectFiles(numpy, musl)
+	assert.True(t, selection.SdistOnly())
+	best, _ = selection.Best()
+	assert.Equal(t, "numpy-1.26.4.tar.gz", best.Filename)
+
+	selection = pypi.SelectFiles(files("six-1.16.0-py2.py3-none-any.whl", "six-1.16.0-1-py3-none-any.whl"), musl)
+	require.Len(t, selection.Wheels, 2)
+	assert.Equal(t, selection.Wheels[0].Rank, selection.Wheels[1].Rank)
+}
@@ -0,0 +1,185 @@
+package pypi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+)
+
+const (
+	snapshotFormatVersion = 1
+	// SnapshotIndexName — имя источника в File.Index и Metadata.Index для пакетов из снимка
+	SnapshotIndexName = "snapshot"
+)
+
+// Snapshot — локальный снимок метаданных пакетов для работы без доступа к индексам.
+// Реализует Index и отвечает только данными снимка
+type Snapshot struct {
+	FormatVersion int       `json:"format_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	// Projects — проекты по нормализованному по PEP 503 имени
+	Projects map[string]*SnapshotProject `json:"projects"`
+}
+
+// SnapshotProject — релизы проекта в снимке
+type SnapshotProject struct {
+	Name     string                      `json:"name"`
+	Releases map[string]*SnapshotRelease `json:"releases"`
+}
+
+// SnapshotRelease — core metadata версии и её файлы
+type SnapshotRelease struct {
+	Metadata *Metadata `json:"metadata"`
+	Files    []File    `json:"files"`
+}
+
+// NewSnapshot создаёт пустой снимок
+func NewSnapshot() *Snapshot {
+	return &Snapshot{
+		FormatVersion: snapshotFormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		Projects:      make(map[string]*SnapshotProject),
+	}
+}
+
+// OpenSnapshot загружает снимок из файла или импортирует каталог с дистрибутивами
+func OpenSnapshot(path string) (*Snapshot, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	if info.IsDir() {
+		return ImportDirectory(path)
+	}
+	return LoadSnapshot(path)
+}
+
+// LoadSnapshot читает снимок, ранее сохранённый Save
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %s: %w", path, err)
+	}
+	if snapshot.FormatVersion != snapshotFormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot format version %d in %s", snapshot.FormatVersion, path)
+	}
+	if snapshot.Projects == nil {
+		snapshot.Projects = make(map[string]*SnapshotProject)
+	}
+	return &snapshot, nil
+}
+
+// Save записывает снимок в файл, чтобы перенести его в изолированную сеть
+func (s *Snapshot) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Add добавляет файл релиза. Метаданные релиза берутся из первого
+// добавленного файла, у которого они есть
+func (s *Snapshot) Add(metadata *Metadata, file File) {
+	key := pep503.Normalize(metadata.Name)
+	project, ok := s.Projects[key]
+	if !ok {
+		project = &SnapshotProject{Name: metadata.Name, Releases: make(map[string]*SnapshotRelease)}
+		s.Projects[key] = project
+	}
+
+	release, ok := project.Releases[metadata.Version]
+	if !ok {
+		release = &SnapshotRelease{}
+		project.Releases[metadata.Version] = release
+	}
+	if release.Metadata == nil {
+		release.Metadata = metadata
+	}
+	release.Files = append(release.Files, file)
+}
+
+// Offline сообщает, что источник не обращается к сети
+func (s *Snapshot) Offline() bool {
+	return true
+}
+
+// Files перечисляет файлы всех релизов проекта из снимка
+func (s *Snapshot) Files(_ context.Context, name string) ([]File, error) {
+	project, err := s.project(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	for _, version := range slices.Sorted(maps.Keys(project.Releases)) {
+		for _, file := range project.Releases[version].Files {
+			file.Version = version
+			file.Index = SnapshotIndexName
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// Metadata возвращает core metadata версии из снимка
+func (s *Snapshot) Metadata(_ context.Context, name string, version string) (*Metadata, error) {
+	project, err := s.project(name)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := pep440.Parse(version)
+	if err != nil {
+		return nil, fmt.Errorf("release %s %s: %w", name, version, err)
+	}
+
+	for raw, release := range project.Releases {
+		parsed, err := pep440.Parse(raw)
+		if err != nil || !parsed.Equal(target) {
+			continue
+		}
+		if release.Metadata == nil {
+			return nil, fmt.Errorf("release %s %s: %w", name, version, ErrMetadataUnavailable)
+		}
+		metadata := *release.Metadata
+		metadata.Index = SnapshotIndexName
+		return &metadata, nil
+	}
+
+	return nil, fmt.Errorf("release %s %s: %w", name, version, ErrNotFound)
+}
+
+func (s *Snapshot) project(name string) (*SnapshotProject, error) {
+	if err := pep503.Validate(name); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidName, err)
+	}
+
+	project, ok := s.Projects[pep503.Normalize(name)]
+	if !ok {
+		return nil, fmt.Errorf("project %s: %w", name, ErrNotFound)
+	}
+	return project, nil
+}
+
+// IsOffline сообщает, что источник пакетов работает без сети, например снимок.
+// Результаты анализа по такому источнику помечаются как офлайн
+func IsOffline(index Index) bool {
+	offline, ok := index.(interface{ Offline() bool })
+	return ok && offline.Offline()
+}
@@ -0,0 +1,111 @@
+package pypi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/distmeta"
+)
+
+// ImportDirectory строит снимок из каталога с wheel и sdist, например из выгрузки
+// pip download. Метаданные wheel читаются из *.dist-info/METADATA, sdist — из PKG-INFO.
+// Файлы, имя которых не похоже на дистрибутив, пропускаются
+func ImportDirectory(dir string) (*Snapshot, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+
+	// Метаданные wheel точнее, чем PKG-INFO в sdist, поэтому wheel добавляются первыми
+	slices.SortStableFunc(paths, func(a, b string) int {
+		aWheel, bWheel := strings.HasSuffix(a, ".whl"), strings.HasSuffix(b, ".whl")
+		switch {
+		case aWheel && !bWheel:
+			return -1
+		case !aWheel && bWheel:
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	snapshot := NewSnapshot()
+	for _, p := range paths {
+		dist, err := ParseFilename(filepath.Base(p))
+		if err != nil {
+			continue
+		}
+
+		file, metadata, err := importDistribution(dir, p, dist)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Add(metadata, file)
+	}
+	return snapshot, nil
+}
+
+func importDistribution(dir string, p string, dist Distribution) (File, *Metadata, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return File{}, nil, fmt.Errorf("failed to read %s: %w", p, err)
+	}
+
+	metadata, err := distributionMetadata(data, filepath.Base(p), dist)
+	if err != nil {
+		return File{}, nil, fmt.Errorf("%s: %w", p, err)
+	}
+
+	rel, err := filepath.Rel(dir, p)
+	if err != nil {
+		rel = filepath.Base(p)
+	}
+	sum := sha256.Sum256(data)
+
+	packageType := "sdist"
+	if dist.Wheel {
+		packageType = "bdist_wheel"
+	}
+	return File{
+		Filename:       filepath.Base(p),
+		PackageType:    packageType,
+		PythonVersion:  dist.PythonTag,
+		RequiresPython: metadata.RequiresPython,
+		Size:           int64(len(data)),
+		URL:            filepath.ToSlash(rel),
+		Digests:        map[string]string{"sha256": hex.EncodeToString(sum[:])},
+	}, metadata, nil
+}
+
+// distributionMetadata достаёт core metadata из архива дистрибутива
+func distributionMetadata(data []byte, filename string, dist Distribution) (*Metadata, error) {
+	r := bytes.NewReader(data)
+	if dist.Wheel {
+		wheel, err := distmeta.ReadWheel(r, r.Size())
+		if err != nil {
+			return nil, err
+		}
+		return metadataFromCore(wheel.Metadata), nil
+	}
+
+	core, err := distmeta.ReadSdist(r, r.Size(), filename)
+	if err != nil {
+		return nil, err
+	}
+	return metadataFromCore(core), nil
+}
@@ -0,0 +1,141 @@
+package pypi_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeWheel(t *testing.T, path string, members map[string]string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range members {
+		member, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = member.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+}
+
+func writeSdist(t *testing.T, path string, members map[string]string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	w := tar.NewWriter(gz)
+	for name, content := range members {
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, gz.Close())
+}
+
+func setupMirror(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "attrs"), 0o755))
+
+	writeWheel(t, filepath.Join(dir, "attrs", "attrs-23.2.0-py3-none-any.whl"), map[string]string{
+		"attrs/__init__.py":               "",
+		"attrs-23.2.0.dist-info/WHEEL":    "Wheel-Version: 1.0\n",
+		"attrs-23.2.0.dist-info/METADATA": attrsMetadata,
+	})
+	// PKG-INFO старого формата без Requires-Dist: должны остаться метаданные wheel
+	writeSdist(t, filepath.Join(dir, "attrs", "attrs-23.2.0.tar.gz"), map[string]string{
+		"attrs-23.2.0/PKG-INFO":          "Metadata-Version: 1.0\nName: attrs\nVersion: 23.2.0\n",
+		"attrs-23.2.0/tests/PKG-INFO":    "broken",
+		"attrs-23.2.0/src/attr/__init__": "",
+	})
+	writeSdist(t, filepath.Join(dir, "python-dateutil-2.9.0.tar.gz"), map[string]string{
+		"python-dateutil-2.9.0/PKG-INFO": "Metadata-Version: 2.1\nName: python-dateutil\nVersion: 2.9.0\n" +
+			"Requires-Python: !=3.0.*,>=2.7\nRequires-Dist: six>=1.5\n",
+	})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.txt"), []byte("mirror"), 0o644))
+
+	return dir
+}
+
+func TestSnapshot_ImportDirectory(t *testing.T) {
+	snapshot, err := pypi.ImportDirectory(setupMirror(t))
+	require.NoError(t, err)
+	require.Len(t, snapshot.Projects, 2)
+
+	files, err := snapshot.Files(context.Background(), "Attrs")
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "attrs-23.2.0-py3-none-any.whl", files[0].Filename)
+	assert.Equal(t, "attrs/attrs-23.2.0-py3-none-any.whl", files[0].URL)
+	assert.Equal(t, "23.2.0", files[0].Version)
+	assert.Equal(t, pypi.SnapshotIndexName, files[0].Index)
+	assert.Equal(t, ">=3.7", files[0].RequiresPython)
+	assert.Len(t, files[0].Digests["sha256"], 64)
+	assert.Positive(t, files[0].Size)
+	assert.Equal(t, "sdist", files[1].PackageType)
+
+	metadata, err := snapshot.Metadata(context.Background(), "attrs", "23.2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{`importlib-metadata; python_version < "3.8"`, `pytest>=4.3.0; extra == "tests"`}, metadata.RequiresDist)
+	assert.Equal(t, pypi.SnapshotIndexName, metadata.Index)
+
+	metadata, err = snapshot.Metadata(context.Background(), "python_dateutil", "2.9.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"six>=1.5"}, metadata.RequiresDist)
+	assert.Equal(t, "!=3.0.*,>=2.7", metadata.RequiresPython)
+
+	_, err = snapshot.Files(context.Background(), "requests")
+	assert.ErrorIs(t, err, pypi.ErrNotFound)
+	_, err = snapshot.Metadata(context.Background(), "attrs", "1.0")
+	assert.ErrorIs(t, err, pypi.ErrNotFound)
+}
+
+func TestSnapshot_SaveAndLoad(t *testing.T) {
+	snapshot, err := pypi.ImportDirectory(setupMirror(t))
+	require.NoError(t, err)
+
+	dump := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, snapshot.Save(dump))
+
+	index, err := pypi.NewIndex(config.PyPIConfig{Offline: true, SnapshotPath: dump})
+	require.NoError(t, err)
+	assert.True(t, pypi.IsOffline(index))
+
+	files, err := index.Files(context.Background(), "attrs")
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	metadata, err := index.Metadata(context.Background(), "python-dateutil", "2.9.0")
+	require.NoError(t, err)
+	assert.Equal(t, "python-dateutil", metadata.Name)
+
+	online, err := pypi.NewIndex(config.PyPIConfig{APIURL: "https://pypi.org/pypi"})
+	require.NoError(t, err)
+	assert.False(t, pypi.IsOffline(online))
+}
+
+func TestSnapshot_LoadRejectsUnknownFormat(t *testing.T) {
+	dump := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, os.WriteFile(dump, []byte(`{"format_version": 2, "projects": {}}`), 0o644))
+
+	_, err := pypi.OpenSnapshot(dump)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported snapshot format version 2")
+}
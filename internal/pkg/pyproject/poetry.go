@@ -0,0 +1,340 @@
+package pyproject
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep508"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/requirements"
+)
+
+var releasePattern = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)(.*)$`)
+
+type poetryTable struct {
+	Name            string         `toml:"name"`
+	Dependencies    map[string]any `toml:"dependencies"`
+	DevDependencies map[string]any `toml:"dev-dependencies"`
+	Group           map[string]struct {
+		Dependencies map[string]any `toml:"dependencies"`
+	} `toml:"group"`
+	Extras map[string][]string `toml:"extras"`
+}
+
+// parsePoetry разбирает [tool.poetry]. Если зависимости уже заданы в [project],
+// основные зависимости Poetry игнорируются, как в Poetry 2
+func (p *parser) parsePoetry(poetry *poetryTable, hasProjectDependencies bool) {
+	if p.project.Name == "" {
+		p.project.Name = poetry.Name
+	}
+
+	optional := make(map[string]*requirements.Requirement)
+
+	for _, name := range sortedKeys(poetry.Dependencies) {
+		field := "tool.poetry.dependencies." + name
+		value := poetry.Dependencies[name]
+
+		if strings.EqualFold(name, "python") {
+			p.parsePoetryPython(field, value)
+			continue
+		}
+		if hasProjectDependencies {
+			continue
+		}
+
+		req, isOptional := p.parsePoetryDependency(field, name, value)
+		if req == nil {
+			continue
+		}
+		if isOptional {
+			optional[pep503.Normalize(name)] = req
+			continue
+		}
+		p.project.Dependencies = append(p.project.Dependencies, req)
+	}
+
+	for _, extra := range sortedKeys(poetry.Extras) {
+		for _, name := range poetry.Extras[extra] {
+			req, ok := optional[pep503.Normalize(name)]
+			if !ok {
+				p.fail("tool.poetry.extras."+extra, "%q is not an optional dependency", name)
+				continue
+			}
+			p.project.OptionalDependencies[extra] = append(p.project.OptionalDependencies[extra], req)
+		}
+	}
+
+	p.addPoetryGroup("dev", "tool.poetry.dev-dependencies", poetry.DevDependencies)
+	for _, group := range sortedKeys(poetry.Group) {
+		p.addPoetryGroup(group, fmt.Sprintf("tool.poetry.group.%s.dependencies", group), poetry.Group[group].Dependencies)
+	}
+}
+
+func (p *parser) addPoetryGroup(group string, field string, deps map[string]any) {
+	var reqs []*requirements.Requirement
+	for _, name := range sortedKeys(deps) {
+		if req, _ := p.parsePoetryDependency(field+"."+name, name, deps[name]); req != nil {
+			reqs = append(reqs, req)
+		}
+	}
+	if len(reqs) > 0 {
+		p.addGroup(group, reqs)
+	}
+}
+
+func (p *parser) parsePoetryPython(field string, value any) {
+	constraint, ok := value.(string)
+	if !ok {
+		p.fail(field, "expected a version constraint string")
+		return
+	}
+
+	specifier, err := TranslatePoetryConstraint(constraint)
+	if err != nil {
+		p.fail(field, "%s", err)
+		return
+	}
+
+	if p.project.RequiresPython == "" {
+		p.project.RequiresPython = specifier
+	}
+}
+
+// parsePoetryDependency переводит зависимость Poetry в требование PEP 508.
+// Второе значение сообщает, что зависимость помечена optional = true
+func (p *parser) parsePoetryDependency(field string, name string, value any) (*requirements.Requirement, bool) {
+	req, err := requirements.ParseRequirement(name)
+	if err != nil || req.Name != name {
+		p.fail(field, "invalid package name %q", name)
+		return nil, false
+	}
+
+	switch spec := value.(type) {
+	case string:
+		req.Specifier, err = TranslatePoetryConstraint(spec)
+		if err != nil {
+			p.fail(field, "%s", err)
+			return nil, false
+		}
+		return req, false
+
+	case map[string]any:
+		return p.parsePoetryTable(field, req, spec)
+
+	case []any:
+		p.fail(field, "multiple constraints dependencies are not supported")
+		return nil, false
+
+	default:
+		p.fail(field, "expected a version constraint string or table")
+		return nil, false
+	}
+}
+
+func (p *parser) parsePoetryTable(field string, req *requirements.Requirement, spec map[string]any) (*requirements.Requirement, bool) {
+	str := func(key string) string {
+		s, _ := spec[key].(string)
+		return s
+	}
+
+	if path := str("path"); path != "" {
+		p.warn("%s: path dependency %q is skipped", field, path)
+		return nil, false
+	}
+
+	switch {
+	case str("git") != "":
+		req.URL = poetryGitURL(str("git"), str("rev")+str("tag")+str("branch"), str("subdirectory"))
+	case str("url") != "":
+		req.URL = str("url")
+	default:
+		specifier, err := TranslatePoetryConstraint(str("version"))
+		if err != nil {
+			p.fail(field, "%s", err)
+			return nil, false
+		}
+		req.Specifier = specifier
+	}
+
+	if extras, ok := spec["extras"].([]any); ok {
+		for _, extra := range extras {
+			if s, ok := extra.(string); ok {
+				req.Extras = append(req.Extras, s)
+			}
+		}
+	}
+
+	var markers []string
+	if python := str("python"); python != "" {
+		marker, err := pythonMarker(python)
+		if err != nil {
+			p.fail(field+".python", "%s", err)
+			return nil, false
+		}
+		markers = append(markers, marker)
+	}
+	if m := str("markers"); m != "" {
+		if _, err := pep508.ParseMarker(m); err != nil {
+			p.fail(field+".markers", "%s", err)
+			return nil, false
+		}
+		markers = append(markers, m)
+	}
+	req.Marker = joinMarkers(markers)
+
+	optional, _ := spec["optional"].(bool)
+	return req, optional
+}
+
+func poetryGitURL(repository string, ref string, subdirectory string) string {
+	url := repository
+	if !strings.HasPrefix(url, "git+") {
+		url = "git+" + url
+	}
+	if ref != "" {
+		url += "@" + ref
+	}
+	if subdirectory != "" {
+		url += "#subdirectory=" + subdirectory
+	}
+	return url
+}
+
+// pythonMarker переводит ограничение Poetry на версию Python в маркер окружения.
+// Как и Poetry, версии больше чем из двух компонентов сравнивает с python_full_version
+func pythonMarker(constraint string) (string, error) {
+	specifier, err := TranslatePoetryConstraint(constraint)
+	if err != nil || specifier == "" {
+		return "", err
+	}
+
+	var clauses []string
+	for clause := range strings.SplitSeq(specifier, ",") {
+		version := strings.TrimLeft(clause, "<>=!~")
+		op := clause[:len(clause)-len(version)]
+		variable := "python_version"
+		if strings.Count(strings.TrimSuffix(version, ".*"), ".") > 1 {
+			variable = "python_full_version"
+		}
+		clauses = append(clauses, fmt.Sprintf("%s %s %q", variable, op, version))
+	}
+	return strings.Join(clauses, " and "), nil
+}
+
+func joinMarkers(markers []string) string {
+	if len(markers) < 2 {
+		return strings.Join(markers, "")
+	}
+	for i, marker := range markers {
+		markers[i] = "(" + marker + ")"
+	}
+	return strings.Join(markers, " and ")
+}
+
+// TranslatePoetryConstraint переводит ограничение версии Poetry в спецификатор PEP 440:
+// ^1.2.3 → >=1.2.3,<2; ~1.2.3 → >=1.2.3,<1.3; 1.2.3 → ==1.2.3; * → без ограничений
+func TranslatePoetryConstraint(constraint string) (string, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" || constraint == "*" {
+		return "", nil
+	}
+	if strings.Contains(constraint, "||") || strings.Contains(constraint, "|") {
+		return "", fmt.Errorf("union constraint %q cannot be expressed in PEP 440", constraint)
+	}
+
+	var clauses []string
+	for clause := range strings.SplitSeq(constraint, ",") {
+		for _, part := range poetryClauses(clause) {
+			translated, err := translatePoetryClause(part)
+			if err != nil {
+				return "", err
+			}
+			clauses = append(clauses, translated)
+		}
+	}
+
+	return requirements.NormalizeSpecifier(strings.Join(clauses, ","))
+}
+
+// poetryClauses делит условие по пробелам: в Poetry ">=3.9 <4" означает ">=3.9,<4".
+// Оператор, отделённый от версии пробелом (">= 1.2"), остаётся с ней
+func poetryClauses(clause string) []string {
+	var parts []string
+	operator := ""
+	for _, field := range strings.Fields(clause) {
+		if strings.Trim(field, "<>=!~^") == "" {
+			operator += field
+			continue
+		}
+		parts = append(parts, operator+field)
+		operator = ""
+	}
+	if operator != "" || len(parts) == 0 {
+		parts = append(parts, operator)
+	}
+	return parts
+}
+
+func translatePoetryClause(clause string) (string, error) {
+	switch {
+	case clause == "*":
+		return "", fmt.Errorf("wildcard '*' cannot be combined with other constraints")
+	case strings.HasPrefix(clause, "^"):
+		return bounded(clause, strings.TrimSpace(clause[1:]), caretUpperBound)
+	case strings.HasPrefix(clause, "~") && !strings.HasPrefix(clause, "~="):
+		return bounded(clause, strings.TrimSpace(clause[1:]), tildeUpperBound)
+	case strings.IndexAny(clause, "<>=!~") == 0:
+		return clause, nil
+	default:
+		// Голая версия в Poetry означает точное совпадение
+		return "==" + clause, nil
+	}
+}
+
+func bounded(clause string, version string, upperBound func([]int) []int) (string, error) {
+	match := releasePattern.FindStringSubmatch(version)
+	if match == nil {
+		return "", fmt.Errorf("invalid version in constraint %q", clause)
+	}
+
+	var release []int
+	for part := range strings.SplitSeq(match[1], ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return "", fmt.Errorf("invalid version in constraint %q", clause)
+		}
+		release = append(release, n)
+	}
+
+	upper := upperBound(release)
+	parts := make([]string, len(upper))
+	for i, n := range upper {
+		parts[i] = strconv.Itoa(n)
+	}
+
+	return fmt.Sprintf(">=%s,<%s", version, strings.Join(parts, ".")), nil
+}
+
+// caretUpperBound увеличивает первый ненулевой компонент версии
+func caretUpperBound(release []int) []int {
+	i := len(release) - 1
+	for j, n := range release {
+		if n != 0 {
+			i = j
+			break
+		}
+	}
+	upper := append([]int(nil), release[:i+1]...)
+	upper[i]++
+	return upper
+}
+
+// tildeUpperBound увеличивает минорную версию, а если её нет — мажорную
+func tildeUpperBound(release []int) []int {
+	i := min(1, len(release)-1)
+	upper := append([]int(nil), release[:i+1]...)
+	upper[i]++
+	return upper
+}
@@ -0,0 +1,298 @@
+package pyproject
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/requirements"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Project — зависимости, извлечённые из pyproject.toml
+type Project struct {
+	Name           string
+	RequiresPython string // спецификатор PEP 440
+	Dependencies   []*requirements.Requirement
+	// OptionalDependencies — extras проекта: [project.optional-dependencies] или [tool.poetry.extras]
+	OptionalDependencies map[string][]*requirements.Requirement
+	// DependencyGroups — dev-группы PEP 735, PDM и Poetry
+	DependencyGroups map[string][]*requirements.Requirement
+	Warnings         []string
+}
+
+// FieldError — ошибка в конкретном поле pyproject.toml
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ParseError содержит все ошибки, найденные в файле
+type ParseError struct {
+	Errors []*FieldError
+}
+
+func (e *ParseError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e.Errors[0].Error(), len(e.Errors)-1)
+}
+
+type document struct {
+	Project *struct {
+		Name                 string              `toml:"name"`
+		RequiresPython       string              `toml:"requires-python"`
+		Dependencies         []string            `toml:"dependencies"`
+		OptionalDependencies map[string][]string `toml:"optional-dependencies"`
+	} `toml:"project"`
+	DependencyGroups map[string][]any `toml:"dependency-groups"`
+	Tool             struct {
+		Poetry *poetryTable `toml:"poetry"`
+		PDM    *struct {
+			DevDependencies map[string][]string `toml:"dev-dependencies"`
+		} `toml:"pdm"`
+	} `toml:"tool"`
+}
+
+// parser накапливает ошибки, чтобы вернуть их все сразу
+type parser struct {
+	project *Project
+	errors  []*FieldError
+}
+
+func (p *parser) fail(field string, format string, args ...any) {
+	p.errors = append(p.errors, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (p *parser) warn(format string, args ...any) {
+	p.project.Warnings = append(p.project.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Parse разбирает pyproject.toml в формате PEP 621, Poetry или PDM
+func Parse(data []byte) (*Project, error) {
+	var doc document
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			row, _ := decodeErr.Position()
+			return nil, &ParseError{Errors: []*FieldError{{Field: fmt.Sprintf("line %d", row), Message: decodeErr.Error()}}}
+		}
+		return nil, &ParseError{Errors: []*FieldError{{Field: "pyproject.toml", Message: err.Error()}}}
+	}
+
+	p := &parser{project: &Project{
+		OptionalDependencies: make(map[string][]*requirements.Requirement),
+		DependencyGroups:     make(map[string][]*requirements.Requirement),
+	}}
+
+	if doc.Project != nil {
+		p.parseProject(&doc)
+	}
+
+	if doc.Tool.Poetry != nil {
+		p.parsePoetry(doc.Tool.Poetry, doc.Project != nil && doc.Project.Dependencies != nil)
+	}
+
+	if doc.Tool.PDM != nil {
+		for _, group := range sortedKeys(doc.Tool.PDM.DevDependencies) {
+			field := fmt.Sprintf("tool.pdm.dev-dependencies.%s", group)
+			p.addGroup(group, p.parseList(field, doc.Tool.PDM.DevDependencies[group]))
+		}
+	}
+
+	p.parseDependencyGroups(doc.DependencyGroups)
+
+	if doc.Project == nil && doc.Tool.Poetry == nil {
+		p.fail("project", "neither [project] nor [tool.poetry] table found")
+	}
+
+	if len(p.errors) > 0 {
+		return nil, &ParseError{Errors: p.errors}
+	}
+
+	return p.project, nil
+}
+
+func (p *parser) parseProject(doc *document) {
+	p.project.Name = doc.Project.Name
+	p.project.Dependencies = p.parseList("project.dependencies", doc.Project.Dependencies)
+
+	if doc.Project.RequiresPython != "" {
+		specifier, err := requirements.NormalizeSpecifier(doc.Project.RequiresPython)
+		if err != nil {
+			p.fail("project.requires-python", "%s", err)
+		}
+		p.project.RequiresPython = specifier
+	}
+
+	for _, extra := range sortedKeys(doc.Project.OptionalDependencies) {
+		field := fmt.Sprintf("project.optional-dependencies.%s", extra)
+		p.project.OptionalDependencies[extra] = p.parseList(field, doc.Project.OptionalDependencies[extra])
+	}
+}
+
+// parseDependencyGroups разбирает [dependency-groups] из PEP 735 вместе с include-group
+func (p *parser) parseDependencyGroups(groups map[string][]any) {
+	resolving := make(map[string]bool)
+
+	var resolve func(name string) []*requirements.Requirement
+	resolve = func(name string) []*requirements.Requirement {
+		if resolving[name] {
+			p.fail("dependency-groups."+name, "cyclic include-group")
+			return nil
+		}
+		resolving[name] = true
+		defer delete(resolving, name)
+
+		var reqs []*requirements.Requirement
+		for i, entry := range groups[name] {
+			field := fmt.Sprintf("dependency-groups.%s[%d]", name, i)
+			switch value := entry.(type) {
+			case string:
+				if req := p.parseEntry(field, value); req != nil {
+					reqs = append(reqs, req)
+				}
+			case map[string]any:
+				include, ok := value["include-group"].(string)
+				if !ok {
+					p.fail(field, "expected a requirement string or {include-group = \"...\"}")
+					continue
+				}
+				if _, exists := groups[include]; !exists {
+					p.fail(field, "included group %q is not defined", include)
+					continue
+				}
+				reqs = append(reqs, resolve(include)...)
+			default:
+				p.fail(field, "expected a requirement string or {include-group = \"...\"}")
+			}
+		}
+		return reqs
+	}
+
+	for _, name := range sortedKeys(groups) {
+		p.addGroup(name, resolve(name))
+	}
+}
+
+func (p *parser) addGroup(name string, reqs []*requirements.Requirement) {
+	p.project.DependencyGroups[name] = append(p.project.DependencyGroups[name], reqs...)
+}
+
+func (p *parser) parseList(field string, entries []string) []*requirements.Requirement {
+	reqs := make([]*requirements.Requirement, 0, len(entries))
+	for i, entry := range entries {
+		if req := p.parseEntry(fmt.Sprintf("%s[%d]", field, i), entry); req != nil {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
+
+// parseEntry разбирает строку PEP 508; PDM дополнительно допускает префикс -e
+func (p *parser) parseEntry(field string, entry string) *requirements.Requirement {
+	value, editable := strings.CutPrefix(strings.TrimSpace(entry), "-e ")
+
+	req, err := requirements.ParseRequirement(value)
+	if err != nil {
+		p.fail(field, "%s", err)
+		return nil
+	}
+	req.Editable = editable
+	return req
+}
+
+// Requirements возвращает основные зависимости вместе с выбранными extras и группами
+func (p *Project) Requirements(extras []string, groups []string) ([]*requirements.Requirement, error) {
+	reqs := slices.Clone(p.Dependencies)
+
+	for _, extra := range extras {
+		deps, ok := lookupNormalized(p.OptionalDependencies, extra)
+		if !ok {
+			return nil, &FieldError{Field: "extras", Message: fmt.Sprintf("unknown extra %q", extra)}
+		}
+		reqs = append(reqs, deps...)
+	}
+
+	for _, group := range groups {
+		deps, ok := lookupNormalized(p.DependencyGroups, group)
+		if !ok {
+			return nil, &FieldError{Field: "groups", Message: fmt.Sprintf("unknown dependency group %q", group)}
+		}
+		reqs = append(reqs, deps...)
+	}
+
+	return reqs, nil
+}
+
+// Диапазон версий Python, среди которых PythonVersion ищет наименьшую допустимую
+const (
+	maxPythonMinor = 30
+	maxPythonPatch = 30
+)
+
+// PythonVersion выбирает наименьшую версию Python (major.minor), которую допускает спецификатор
+// requires-python: для >3.9 это 3.9, потому что подходит 3.9.1. Понимает и синтаксис Poetry
+// (^3.8, >=3.9 <4). Без нижней границы или для неразобранного спецификатора возвращает пустую строку
+func PythonVersion(specifier string) string {
+	set, err := pep440.ParseSpecifierSet(specifier)
+	if err != nil {
+		translated, err := TranslatePoetryConstraint(specifier)
+		if err != nil {
+			return ""
+		}
+		if set, err = pep440.ParseSpecifierSet(translated); err != nil {
+			return ""
+		}
+	}
+
+	bounded := slices.ContainsFunc(set, func(spec pep440.Specifier) bool {
+		return spec.Operator != "<" && spec.Operator != "<=" && spec.Operator != "!="
+	})
+	if !bounded {
+		return ""
+	}
+
+	for major := 2; major <= 3; major++ {
+		for minor := 0; minor <= maxPythonMinor; minor++ {
+			series := fmt.Sprintf("%d.%d", major, minor)
+			// Сначала X.Y без патча: ===3.9 допускает только такую запись
+			if set.Contains(pep440.MustParse(series)) {
+				return series
+			}
+			for patch := 1; patch <= maxPythonPatch; patch++ {
+				if set.Contains(pep440.MustParse(fmt.Sprintf("%s.%d", series, patch))) {
+					return series
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// lookupNormalized ищет extra или группу по имени PEP 503: "Dev_Tools" и "dev-tools" совпадают
+func lookupNormalized[V any](m map[string]V, name string) (V, bool) {
+	if value, ok := m[name]; ok {
+		return value, true
+	}
+	for key, value := range m {
+		if pep503.Equal(key, name) {
+			return value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
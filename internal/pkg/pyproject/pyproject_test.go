@@ -0,0 +1,205 @@
+package pyproject_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pyproject"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/requirements"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func names(reqs []*requirements.Requirement) []string {
+	result := make([]string, len(reqs))
+	for i, req := range reqs {
+		result[i] = req.String()
+	}
+	return result
+}
+
+func TestParse_PEP621WithPDM(t *testing.T) {
+	data := `
+[project]
+name = "demo"
+requires-python = ">= 3.9"
+dependencies = [
+    "requests[socks]>=2.28",
+    "tomli; python_version < '3.11'",
+]
+
+[project.optional-dependencies]
+docs = ["sphinx>=7"]
+
+[tool.pdm.dev-dependencies]
+test = ["pytest>=7", "-e git+https://github.com/org/helper.git#egg=helper"]
+
+[dependency-groups]
+lint = ["ruff"]
+dev = [{include-group = "lint"}, "mypy"]
+`
+
+	project, err := pyproject.Parse([]byte(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, "demo", project.Name)
+	assert.Equal(t, ">=3.9", project.RequiresPython)
+	assert.Equal(t, "3.9", pyproject.PythonVersion(project.RequiresPython))
+	assert.Equal(t, []string{"requests[socks]>=2.28", `tomli; python_version < "3.11"`}, names(project.Dependencies))
+	assert.Equal(t, []string{"sphinx>=7"}, names(project.OptionalDependencies["docs"]))
+	assert.Equal(t, []string{"ruff", "mypy"}, names(project.DependencyGroups["dev"]))
+	require.Len(t, project.DependencyGroups["test"], 2)
+	assert.True(t, project.DependencyGroups["test"][1].Editable)
+
+	reqs, err := project.Requirements([]string{"docs"}, []string{"lint"})
+	require.NoError(t, err)
+	assert.Len(t, reqs, 4)
+
+	// extras и группы сравниваются по PEP 503
+	reqs, err = project.Requirements([]string{"Docs"}, []string{"LINT"})
+	require.NoError(t, err)
+	assert.Len(t, reqs, 4)
+
+	_, err = project.Requirements([]string{"missing"}, nil)
+	assert.Error(t, err)
+}
+
+func TestParse_Poetry(t *testing.T) {
+	data := `
+[tool.poetry]
+name = "legacy"
+
+[tool.poetry.dependencies]
+python = "^3.8"
+requests = "^2.28.1"
+django = { version = "~4.2", extras = ["argon2"], python = ">=3.10" }
+pendulum = { git = "https://github.com/sdispater/pendulum.git", tag = "3.0.0" }
+local = { path = "../local" }
+redis = { version = "*", optional = true }
+attrs = { version = "^23.1", python = "^3.8.1" }
+
+[tool.poetry.extras]
+cache = ["redis"]
+
+[tool.poetry.group.test.dependencies]
+pytest = "7.4.0"
+`
+
+	project, err := pyproject.Parse([]byte(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, "legacy", project.Name)
+	assert.Equal(t, ">=3.8,<4", project.RequiresPython)
+	assert.Equal(t, []string{
+		`attrs>=23.1,<24; python_full_version >= "3.8.1" and python_version < "4"`,
+		`django[argon2]>=4.2,<4.3; python_version >= "3.10"`,
+		"pendulum @ git+https://github.com/sdispater/pendulum.git@3.0.0",
+		"requests>=2.28.1,<3",
+	}, names(project.Dependencies))
+	assert.Equal(t, []string{"redis"}, names(project.OptionalDependencies["cache"]))
+	assert.Equal(t, []string{"pytest==7.4.0"}, names(project.DependencyGroups["test"]))
+	require.Len(t, project.Warnings, 1)
+	assert.Contains(t, project.Warnings[0], "path dependency")
+}
+
+func TestParse_Errors(t *testing.T) {
+	data := `
+[project]
+name = "broken"
+dependencies = ["requests>>2", "ok"]
+
+[tool.poetry.dependencies]
+flask = "^2.0 || ^3.0"
+`
+
+	_, err := pyproject.Parse([]byte(data))
+
+	var parseErr *pyproject.ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Len(t, parseErr.Errors, 1)
+	assert.Equal(t, "project.dependencies[0]", parseErr.Errors[0].Field)
+
+	_, err = pyproject.Parse([]byte("[project\nname = 1"))
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, "line 1", parseErr.Errors[0].Field)
+
+	_, err = pyproject.Parse([]byte("[build-system]\nrequires = []"))
+	assert.Error(t, err)
+
+	_, err = pyproject.Parse([]byte(`
+[tool.poetry.dependencies]
+pywin32 = { version = "^306", markers = "platform = 'win32'" }
+`))
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, "tool.poetry.dependencies.pywin32.markers", parseErr.Errors[0].Field)
+}
+
+func TestTranslatePoetryConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+		wantErr    bool
+	}{
+		{"^1.2.3", ">=1.2.3,<2", false},
+		{"^1.2", ">=1.2,<2", false},
+		{"^0.2.3", ">=0.2.3,<0.3", false},
+		{"^0.0.3", ">=0.0.3,<0.0.4", false},
+		{"^0.0", ">=0.0,<0.1", false},
+		{"^0", ">=0,<1", false},
+		{"~1.2.3", ">=1.2.3,<1.3", false},
+		{"~1", ">=1,<2", false},
+		{"~=1.4", "~=1.4", false},
+		{"1.2.3", "==1.2.3", false},
+		{"1.2.*", "==1.2.*", false},
+		{">= 1.2, < 1.5", ">=1.2,<1.5", false},
+		{">=3.9 <4", ">=3.9,<4", false},
+		{">= 3.9 < 4", ">=3.9,<4", false},
+		{"*", "", false},
+		{"^1.0 || ^2.0", "", true},
+		{"^abc", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			got, err := pyproject.TranslatePoetryConstraint(tt.constraint)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPythonVersion(t *testing.T) {
+	tests := []struct {
+		specifier string
+		want      string
+	}{
+		{">=3.9", "3.9"},
+		{">=3.8.1", "3.8"},
+		{">3.9", "3.9"},
+		{">3.9.99", "3.10"},
+		{"<4,>=3.10", "3.10"},
+		{"<4, >=3.10", "3.10"},
+		{"==3.11.*", "3.11"},
+		{"~=3.12.1", "3.12"},
+		{"===3.9", "3.9"},
+		{">=2.7,!=3.0.*,!=3.1.*", "2.7"},
+		{"!=2.7.*,>=2", "2.0"},
+		{"^3.8", "3.8"},
+		{">=3.9 <4", "3.9"},
+		{"~3.10", "3.10"},
+		{"<4", ""},
+		{"", ""},
+		{"*", ""},
+		{"not a version", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.specifier, func(t *testing.T) {
+			assert.Equal(t, tt.want, pyproject.PythonVersion(tt.specifier))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// NewClient создаёт клиент Redis по RedisConfig и проверяет соединение
+func NewClient(ctx context.Context, cfg *config.RedisConfig) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:         cfg.GetRedisAddress(),
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		MaxRetries:   cfg.MaxRetries,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		PoolSize:     cfg.PoolSize,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", cfg.GetRedisAddress(), err)
+	}
+
+	return client, nil
+}
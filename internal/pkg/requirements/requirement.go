@@ -0,0 +1,180 @@
+package requirements
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep508"
+)
+
+var vcsPrefixes = []string{"git+", "hg+", "svn+", "bzr+"}
+
+// Requirement — одна зависимость из requirements.txt или строки PEP 508
+type Requirement struct {
+	Name      string
+	Extras    []string
+	Specifier string // список спецификаторов через запятую, без пробелов
+	URL       string // прямая ссылка или VCS URL
+	Marker    string
+	Hashes    []string
+	Editable  bool
+	Line      int
+}
+
+// IsVCS сообщает, что зависимость ссылается на систему контроля версий
+func (r *Requirement) IsVCS() bool {
+	for _, prefix := range vcsPrefixes {
+		if strings.HasPrefix(r.URL, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRequirement разбирает строку зависимости PEP 508, а также URL
+// и пути в стиле pip с именем в фрагменте #egg=. Маркер приводится
+// к каноническому виду
+func ParseRequirement(line string) (*Requirement, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, fmt.Errorf("empty requirement")
+	}
+
+	if looksLikeURL(line) {
+		return parseURLRequirement(line)
+	}
+
+	parsed, err := pep508.ParseRequirement(line)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Requirement{
+		Name:      parsed.Name,
+		Extras:    parsed.Extras,
+		Specifier: parsed.Specifier.String(),
+		URL:       parsed.URL,
+		Marker:    parsed.Marker.String(),
+	}, nil
+}
+
+// String возвращает зависимость в виде строки PEP 508
+func (r *Requirement) String() string {
+	var b strings.Builder
+	b.WriteString(r.Name)
+	if len(r.Extras) > 0 {
+		b.WriteString("[" + strings.Join(r.Extras, ",") + "]")
+	}
+	if r.URL != "" {
+		b.WriteString(" @ " + r.URL)
+		if r.Marker != "" {
+			b.WriteString(" ")
+		}
+	} else {
+		b.WriteString(r.Specifier)
+	}
+	if r.Marker != "" {
+		b.WriteString("; " + r.Marker)
+	}
+	return b.String()
+}
+
+func parseURLRequirement(line string) (*Requirement, error) {
+	urlPart, marker, _ := cutURLMarker(line)
+
+	parsed, err := url.Parse(urlPart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", urlPart, err)
+	}
+
+	fragment, err := url.ParseQuery(parsed.Fragment)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL fragment in %q: %w", urlPart, err)
+	}
+
+	egg := fragment.Get("egg")
+	if egg == "" {
+		return nil, fmt.Errorf("cannot determine project name for %q, add #egg=<name> or use '<name> @ <url>'", urlPart)
+	}
+
+	name, extrasPart, hasExtras := strings.Cut(egg, "[")
+	if !pep503.IsValid(name) {
+		return nil, fmt.Errorf("invalid project name %q in #egg fragment", name)
+	}
+
+	req := &Requirement{
+		Name: name,
+		URL:  urlPart,
+	}
+
+	if marker != "" {
+		parsed, err := pep508.ParseMarker(marker)
+		if err != nil {
+			return nil, err
+		}
+		req.Marker = parsed.String()
+	}
+
+	if hasExtras {
+		extras, err := parseExtras(strings.TrimSuffix(extrasPart, "]"))
+		if err != nil {
+			return nil, err
+		}
+		req.Extras = extras
+	}
+
+	return req, nil
+}
+
+func looksLikeURL(line string) bool {
+	for _, prefix := range vcsPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+
+	if line == "." || strings.HasPrefix(line, "./") || strings.HasPrefix(line, "../") || strings.HasPrefix(line, "/") {
+		return true
+	}
+
+	// URL без имени: схема стоит раньше любых символов спецификатора
+	scheme := strings.Index(line, "://")
+	return scheme > 0 && !strings.ContainsAny(line[:scheme], " @[;<>=!~")
+}
+
+// cutURLMarker отделяет маркер, который после URL должен идти через пробел и ';'
+func cutURLMarker(s string) (string, string, bool) {
+	for _, sep := range []string{" ;", "\t;"} {
+		if i := strings.Index(s, sep); i >= 0 {
+			return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+len(sep):]), true
+		}
+	}
+	return strings.TrimSpace(s), "", false
+}
+
+func parseExtras(s string) ([]string, error) {
+	var extras []string
+	for extra := range strings.SplitSeq(s, ",") {
+		extra = strings.TrimSpace(extra)
+		if extra == "" {
+			continue
+		}
+		if !pep503.IsValid(extra) {
+			return nil, fmt.Errorf("invalid extra %q", extra)
+		}
+		extras = append(extras, extra)
+	}
+	return extras, nil
+}
+
+// NormalizeSpecifier проверяет список спецификаторов версий и убирает из него пробелы
+func NormalizeSpecifier(s string) (string, error) {
+	specifier, err := pep440.ParseSpecifierSet(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid version specifier: %w", err)
+	}
+	return specifier.String(), nil
+}
@@ -0,0 +1,302 @@
+package requirements
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	commentPattern = regexp.MustCompile(`(^|\s+)#.*$`)
+	hashPattern    = regexp.MustCompile(`^(sha256|sha384|sha512):[0-9a-fA-F]+$`)
+)
+
+// ReferenceKind — тип ссылки на другой файл требований
+type ReferenceKind string
+
+const (
+	ReferenceRequirements ReferenceKind = "requirements"
+	ReferenceConstraints  ReferenceKind = "constraints"
+)
+
+// Reference — ссылка -r/-c, которую нельзя разрешить без исходного файла
+type Reference struct {
+	Kind ReferenceKind
+	Path string
+	Line int
+}
+
+// File — результат разбора requirements.txt
+type File struct {
+	Requirements []*Requirement
+	References   []Reference
+	IndexURLs    []string
+}
+
+// LineError — ошибка разбора конкретной строки
+type LineError struct {
+	Line    int
+	Message string
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ParseError содержит все ошибки, найденные в файле
+type ParseError struct {
+	Errors []*LineError
+}
+
+func (e *ParseError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e.Errors[0].Error(), len(e.Errors)-1)
+}
+
+// Parse разбирает requirements.txt в формате pip
+func Parse(r io.Reader) (*File, error) {
+	lines, err := logicalLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{}
+	var errs []*LineError
+
+	for _, l := range lines {
+		if err := file.parseLine(l.text, l.number); err != nil {
+			errs = append(errs, &LineError{Line: l.number, Message: err.Error()})
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, &ParseError{Errors: errs}
+	}
+
+	return file, nil
+}
+
+type logicalLine struct {
+	text   string
+	number int
+}
+
+// logicalLines склеивает строки с '\' в конце и убирает комментарии.
+// Номер логической строки — номер её первой физической строки
+func logicalLines(r io.Reader) ([]logicalLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var (
+		lines   []logicalLine
+		current strings.Builder
+		start   int
+		number  int
+	)
+
+	for scanner.Scan() {
+		number++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if number == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		line = commentPattern.ReplaceAllString(line, "")
+
+		if current.Len() == 0 {
+			start = number
+		}
+
+		if trimmed, ok := strings.CutSuffix(line, `\`); ok {
+			current.WriteString(trimmed)
+			current.WriteString(" ")
+			continue
+		}
+
+		current.WriteString(line)
+		if text := strings.TrimSpace(current.String()); text != "" {
+			lines = append(lines, logicalLine{text: text, number: start})
+		}
+		current.Reset()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read requirements: %w", err)
+	}
+
+	if text := strings.TrimSpace(current.String()); text != "" {
+		lines = append(lines, logicalLine{text: text, number: start})
+	}
+
+	return lines, nil
+}
+
+func (f *File) parseLine(text string, number int) error {
+	args, options := splitOptions(strings.Fields(text))
+
+	if len(args) == 0 {
+		return f.parseGlobalOptions(options, number)
+	}
+
+	req, err := ParseRequirement(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	req.Line = number
+
+	if err := parseRequirementOptions(req, options); err != nil {
+		return err
+	}
+
+	f.Requirements = append(f.Requirements, req)
+	return nil
+}
+
+// splitOptions отделяет требование от опций: всё, начиная с первого
+// токена с '-', считается опциями, как в pip
+func splitOptions(tokens []string) ([]string, []string) {
+	for i, token := range tokens {
+		if strings.HasPrefix(token, "-") {
+			return tokens[:i], tokens[i:]
+		}
+	}
+	return tokens, nil
+}
+
+type option struct {
+	name  string
+	value string
+}
+
+var (
+	valueOptions = map[string]string{
+		"-r": "--requirement", "--requirement": "--requirement",
+		"-c": "--constraint", "--constraint": "--constraint",
+		"-e": "--editable", "--editable": "--editable",
+		"-i": "--index-url", "--index-url": "--index-url",
+		"--extra-index-url": "--extra-index-url",
+		"-f":                "--find-links", "--find-links": "--find-links",
+		"--no-binary":       "--no-binary",
+		"--only-binary":     "--only-binary",
+		"--trusted-host":    "--trusted-host",
+		"--use-feature":     "--use-feature",
+		"--hash":            "--hash",
+		"--config-settings": "--config-settings",
+		"--global-option":   "--global-option",
+	}
+	flagOptions = map[string]bool{
+		"--pre":            true,
+		"--prefer-binary":  true,
+		"--require-hashes": true,
+		"--no-index":       true,
+	}
+)
+
+func readOptions(tokens []string) ([]option, error) {
+	var options []option
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		if flagOptions[token] {
+			options = append(options, option{name: token})
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(token, "=")
+		if !strings.HasPrefix(token, "--") {
+			// Короткая форма: -rfile или -r file
+			name, value = token[:min(2, len(token))], token[min(2, len(token)):]
+			hasValue = value != ""
+		}
+
+		canonical, ok := valueOptions[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown option %q", token)
+		}
+
+		if !hasValue {
+			if i+1 >= len(tokens) {
+				return nil, fmt.Errorf("option %s requires a value", name)
+			}
+			i++
+			value = tokens[i]
+		}
+
+		options = append(options, option{name: canonical, value: value})
+	}
+
+	return options, nil
+}
+
+func (f *File) parseGlobalOptions(tokens []string, number int) error {
+	options, err := readOptions(tokens)
+	if err != nil {
+		return err
+	}
+
+	for i, opt := range options {
+		switch opt.name {
+		case "--requirement":
+			f.References = append(f.References, Reference{Kind: ReferenceRequirements, Path: opt.value, Line: number})
+		case "--constraint":
+			f.References = append(f.References, Reference{Kind: ReferenceConstraints, Path: opt.value, Line: number})
+		case "--index-url", "--extra-index-url":
+			f.IndexURLs = append(f.IndexURLs, opt.value)
+		case "--editable":
+			req, err := ParseRequirement(opt.value)
+			if err != nil {
+				return err
+			}
+			req.Editable = true
+			req.Line = number
+			if err := parseRequirementOptions(req, tokensOf(options[i+1:])); err != nil {
+				return err
+			}
+			f.Requirements = append(f.Requirements, req)
+			return nil
+		case "--hash":
+			return fmt.Errorf("--hash must follow a requirement on the same line")
+		}
+	}
+
+	return nil
+}
+
+func parseRequirementOptions(req *Requirement, tokens []string) error {
+	options, err := readOptions(tokens)
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range options {
+		switch opt.name {
+		case "--hash":
+			if !hashPattern.MatchString(opt.value) {
+				return fmt.Errorf("invalid hash %q, expected <sha256|sha384|sha512>:<hex digest>", opt.value)
+			}
+			req.Hashes = append(req.Hashes, opt.value)
+		case "--config-settings", "--global-option":
+			// Опции сборки не влияют на анализ зависимостей
+		default:
+			return fmt.Errorf("option %s is not allowed after a requirement", opt.name)
+		}
+	}
+
+	return nil
+}
+
+func tokensOf(options []option) []string {
+	tokens := make([]string, 0, len(options))
+	for _, opt := range options {
+		if opt.value == "" {
+			tokens = append(tokens, opt.name)
+			continue
+		}
+		tokens = append(tokens, opt.name+"="+opt.value)
+	}
+	return tokens
+}
@@ -0,0 +1,126 @@
+package requirements_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/requirements"
+)
+
+func TestParse(t *testing.T) {
+	input := `# зависимости проекта
+requests[security,socks] >= 2.28.0, < 3 ; python_version >= "3.8"  # комментарий
+django==4.2.1 \
+    --hash=sha256:abcdef0123 \
+    --hash sha512:FFEE
+numpy
+-r base.txt
+--constraint=constraints.txt
+-i https://pypi.example.com/simple
+flask (>=2.0)
+mylib @ https://example.com/mylib-1.0.tar.gz ; sys_platform == "linux"
+git+https://github.com/pypa/pip.git@22.0#egg=pip
+-e git+https://github.com/org/tool.git#egg=tool[cli]
+`
+
+	file, err := requirements.Parse(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, file.Requirements, 7)
+
+	reqs := file.Requirements
+	assert.Equal(t, "requests", reqs[0].Name)
+	assert.Equal(t, []string{"security", "socks"}, reqs[0].Extras)
+	assert.Equal(t, ">=2.28.0,<3", reqs[0].Specifier)
+	assert.Equal(t, `python_version >= "3.8"`, reqs[0].Marker)
+	assert.Equal(t, 2, reqs[0].Line)
+
+	assert.Equal(t, "django", reqs[1].Name)
+	assert.Equal(t, "==4.2.1", reqs[1].Specifier)
+	assert.Equal(t, []string{"sha256:abcdef0123", "sha512:FFEE"}, reqs[1].Hashes)
+	assert.Equal(t, 3, reqs[1].Line)
+
+	assert.Equal(t, "numpy", reqs[2].Name)
+	assert.Empty(t, reqs[2].Specifier)
+	assert.Equal(t, 6, reqs[2].Line)
+
+	assert.Equal(t, ">=2.0", reqs[3].Specifier)
+
+	assert.Equal(t, "mylib", reqs[4].Name)
+	assert.Equal(t, "https://example.com/mylib-1.0.tar.gz", reqs[4].URL)
+	assert.Equal(t, `sys_platform == "linux"`, reqs[4].Marker)
+
+	assert.Equal(t, "pip", reqs[5].Name)
+	assert.True(t, reqs[5].IsVCS())
+
+	assert.Equal(t, "tool", reqs[6].Name)
+	assert.Equal(t, []string{"cli"}, reqs[6].Extras)
+	assert.True(t, reqs[6].Editable)
+
+	assert.Equal(t, []requirements.Reference{
+		{Kind: requirements.ReferenceRequirements, Path: "base.txt", Line: 7},
+		{Kind: requirements.ReferenceConstraints, Path: "constraints.txt", Line: 8},
+	}, file.References)
+	assert.Equal(t, []string{"https://pypi.example.com/simple"}, file.IndexURLs)
+}
+
+func TestParse_Errors(t *testing.T) {
+	input := `requests>=2.0
+django=>4
+-e ./local/path
+
+flask --hash=md5:abc
+--unknown-option
+`
+
+	_, err := requirements.Parse(strings.NewReader(input))
+	require.Error(t, err)
+
+	var parseErr *requirements.ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Len(t, parseErr.Errors, 4)
+
+	lines := make([]int, 0, len(parseErr.Errors))
+	for _, e := range parseErr.Errors {
+		lines = append(lines, e.Line)
+	}
+	assert.Equal(t, []int{2, 3, 5, 6}, lines)
+	assert.Contains(t, parseErr.Errors[0].Message, "invalid version specifier")
+	assert.Contains(t, parseErr.Errors[1].Message, "cannot determine project name")
+	assert.Contains(t, parseErr.Errors[2].Message, "invalid hash")
+	assert.Contains(t, parseErr.Errors[3].Message, "unknown option")
+	assert.Contains(t, err.Error(), "line 2:")
+}
+
+func TestParseRequirement(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"simple", "requests", "requests", false},
+		{"specifier with spaces", "requests >= 2.0 , != 2.1", "requests>=2.0,!=2.1", false},
+		{"extras and marker", "uvicorn[standard]~=0.20;os_name=='posix'", "uvicorn[standard]~=0.20; os_name == \"posix\"", false},
+		{"direct reference", "pkg@file:///tmp/pkg.whl", "pkg @ file:///tmp/pkg.whl", false},
+		{"invalid name", "-pkg", "", true},
+		{"unterminated extras", "pkg[extra", "", true},
+		{"empty marker", "pkg==1.0;", "", true},
+		{"url without name", "https://example.com/pkg.tar.gz", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := requirements.ParseRequirement(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, req.String())
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Package app — HTTP интерфейс dependency-resolver: метрики Prometheus, health check и сброс кеша индексов
+package app
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const serviceName = "dependency-resolver"
+
+// CachePurger удаляет сохранённые ответы индексов для проекта
+type CachePurger interface {
+	Purge(ctx context.Context, name string) error
+}
+
+// SetupRouter отдаёт /metrics из gatherer и /health, /health/live.
+// DELETE /admin/cache/:package доступен, только если передан cache и задан adminToken:
+// порт открыт для Prometheus, поэтому сброс кеша требует Authorization: Bearer <adminToken>
+func SetupRouter(gatherer prometheus.Gatherer, cache CachePurger, adminToken string) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})))
+
+	health := router.Group("/health")
+	{
+		health.GET("", healthCheck)
+		health.GET("/", healthCheck)
+		health.GET("/live", liveCheck)
+	}
+
+	if cache != nil && adminToken != "" {
+		router.DELETE("/admin/cache/:package", requireToken(adminToken), purgeCache(cache))
+	}
+
+	return router
+}
+
+func healthCheck(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "healthy",
+		"service":   serviceName,
+		"timestamp": time.Now().Unix(),
+		"version":   "1.0.0",
+	})
+}
+
+func liveCheck(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "alive",
+		"service":   serviceName,
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// requireToken пропускает только запросы с Authorization: Bearer <token>
+func requireToken(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), expected) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "admin token required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// purgeCache удаляет из кеша ответы индексов для пакета, чтобы следующий запрос ушёл в индекс
+func purgeCache(cache CachePurger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("package")
+		if err := cache.Purge(c.Request.Context(), name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,108 @@
+package app_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/app"
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/metrics"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminToken = "secret"
+
+func setupCachedRouter(t *testing.T) (*gin.Engine, *pypi.Client, *miniredis.Miniredis) {
+	gin.SetMode(gin.TestMode)
+
+	index := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"info": {"name": "requests"}, "releases": {"2.32.3": [{"filename": "requests-2.32.3.tar.gz"}]}}`))
+	}))
+	t.Cleanup(index.Close)
+
+	redisServer := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	cache := pypi.NewCache(redisClient, time.Hour)
+	registry := prometheus.NewRegistry()
+	metrics.RegisterCacheStats(registry, cache.Stats)
+
+	client := pypi.NewClient(config.PyPIConfig{APIURL: index.URL}, pypi.WithCache(cache))
+	return app.SetupRouter(registry, cache, testAdminToken), client, redisServer
+}
+
+func TestSetupRouter_ExportsCacheStats(t *testing.T) {
+	router, client, _ := setupCachedRouter(t)
+
+	for range 3 {
+		_, err := client.Project(context.Background(), "requests")
+		require.NoError(t, err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "dependency_resolver_index_cache_hits_total 2")
+	assert.Contains(t, w.Body.String(), "dependency_resolver_index_cache_misses_total 1")
+}
+
+func TestSetupRouter_PurgesCache(t *testing.T) {
+	router, client, redisServer := setupCachedRouter(t)
+
+	_, err := client.Project(context.Background(), "requests")
+	require.NoError(t, err)
+	require.True(t, redisServer.Exists("pypi:cache:requests"))
+
+	for _, authorization := range []string{"", "Bearer wrong"} {
+		req := httptest.NewRequest(http.MethodDelete, "/admin/cache/Requests", nil)
+		req.Header.Set("Authorization", authorization)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.True(t, redisServer.Exists("pypi:cache:requests"))
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/cache/Requests", nil)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.False(t, redisServer.Exists("pypi:cache:requests"))
+}
+
+func TestSetupRouter_PurgeDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := pypi.NewCache(redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()}), time.Hour)
+
+	tests := []struct {
+		name   string
+		router *gin.Engine
+	}{
+		{"without cache", app.SetupRouter(prometheus.NewRegistry(), nil, testAdminToken)},
+		{"without admin token", app.SetupRouter(prometheus.NewRegistry(), cache, "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/admin/cache/requests", nil)
+			req.Header.Set("Authorization", "Bearer "+testAdminToken)
+			w := httptest.NewRecorder()
+			tt.router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		})
+	}
+}
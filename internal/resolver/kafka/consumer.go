@@ -0,0 +1,87 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/kafka"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"google.golang.org/protobuf/proto"
+)
+
+// RequestEventHandler обрабатывает десериализованный запрос на анализ
+type RequestEventHandler func(ctx context.Context, event *eventspb.AnalysisStartedEvent) error
+
+// Задержки перед повторной обработкой запроса, события которого не удалось опубликовать
+const (
+	retryInitialDelay = time.Second
+	retryMaxDelay     = time.Minute
+)
+
+type RequestConsumer struct {
+	consumer kafka.Consumer
+	topic    string
+}
+
+func NewRequestConsumer(brokers []string, groupID string, topic string, initialOffset string) (*RequestConsumer, error) {
+	baseConsumer, err := kafka.NewBaseConsumer(&kafka.ConsumerConfig{
+		Brokers:       brokers,
+		GroupID:       groupID,
+		AutoCommit:    true,
+		InitialOffset: kafka.ParseInitialOffset(initialOffset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestConsumer{
+		consumer: baseConsumer,
+		topic:    topic,
+	}, nil
+}
+
+// Run блокируется до отмены контекста, передавая каждое AnalysisStartedEvent в handler.
+// Ошибку handler Run повторяет через RetryHandler, поэтому сообщение подтверждается только после обработки
+func (c *RequestConsumer) Run(ctx context.Context, handler RequestEventHandler) error {
+	handler = RetryHandler(handler, retryInitialDelay, retryMaxDelay)
+	return c.consumer.Subscribe(ctx, []string{c.topic}, func(ctx context.Context, message *kafka.Message) error {
+		var event eventspb.AnalysisStartedEvent
+		if err := proto.Unmarshal(message.Value, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal analysis request: %w", err)
+		}
+
+		if event.RequestId == "" {
+			event.RequestId = message.Key
+		}
+
+		return handler(ctx, &event)
+	})
+}
+
+// RetryHandler повторяет handler с экспоненциально растущей задержкой (не больше maxDelay),
+// пока он не завершится без ошибки или не будет отменён контекст
+func RetryHandler(handler RequestEventHandler, initialDelay, maxDelay time.Duration) RequestEventHandler {
+	return func(ctx context.Context, event *eventspb.AnalysisStartedEvent) error {
+		delay := initialDelay
+		for {
+			err := handler(ctx, event)
+			if err == nil {
+				return nil
+			}
+
+			log.Printf("Retrying analysis request %s in %s: %v", event.RequestId, delay, err)
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("analysis request %s was not handled: %w", event.RequestId, err)
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, maxDelay)
+		}
+	}
+}
+
+func (c *RequestConsumer) Close() error {
+	return c.consumer.Close()
+}
@@ -0,0 +1,14 @@
+package kafka
+
+import (
+	"context"
+
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+)
+
+// Producer публикует события resolver: статусы в status topic, результаты в result topic
+type Producer interface {
+	PublishStatus(ctx context.Context, event *eventspb.AnalysisStatusEvent) error
+	PublishResult(ctx context.Context, event *eventspb.AnalysisResultEvent) error
+	Close() error
+}
@@ -0,0 +1,104 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/kafka"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/IBM/sarama"
+	"google.golang.org/protobuf/proto"
+)
+
+type ResolverProducer struct {
+	producer    *kafka.MetadataProducer
+	statusTopic string
+	resultTopic string
+}
+
+// interface check
+var _ Producer = (*ResolverProducer)(nil)
+
+func NewResolverProducer(brokers []string, statusTopic string, resultTopic string) (*ResolverProducer, error) {
+	baseProducer, err := kafka.NewBaseProducer(&kafka.ProducerConfig{
+		Brokers:           brokers,
+		RequiredAcks:      sarama.WaitForAll,
+		RetryMax:          3,
+		CompressionType:   4, // LZ4
+		EnableIdempotence: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	retryProducer := kafka.NewRetryProducer(baseProducer, 3, 1*time.Second)
+	metadataProducer := kafka.NewMetadataProducer(retryProducer, &protobufMetadataExtractor{})
+
+	return &ResolverProducer{
+		producer:    metadataProducer,
+		statusTopic: statusTopic,
+		resultTopic: resultTopic,
+	}, nil
+}
+
+// PublishStatus отправляет обновление статуса анализа
+func (p *ResolverProducer) PublishStatus(ctx context.Context, event *eventspb.AnalysisStatusEvent) error {
+	return p.publish(ctx, p.statusTopic, event)
+}
+
+// PublishResult отправляет разрешённый набор зависимостей
+func (p *ResolverProducer) PublishResult(ctx context.Context, event *eventspb.AnalysisResultEvent) error {
+	return p.publish(ctx, p.resultTopic, event)
+}
+
+func (p *ResolverProducer) publish(ctx context.Context, topic string, event proto.Message) error {
+	data, err := proto.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal protobuf: %w", err)
+	}
+	return p.producer.SendData(ctx, topic, event, data)
+}
+
+func (p *ResolverProducer) Close() error {
+	return p.producer.Close()
+}
+
+type protobufMetadataExtractor struct{}
+
+func (e *protobufMetadataExtractor) ExtractKey(data any) string {
+	switch event := data.(type) {
+	case *eventspb.AnalysisStatusEvent:
+		return event.RequestId
+	case *eventspb.AnalysisResultEvent:
+		return event.RequestId
+	default:
+		log.Printf("⚠️  Unknown event type: %T", data)
+		return "unknown"
+	}
+}
+
+func (e *protobufMetadataExtractor) ExtractHeaders(data any) map[string]string {
+	switch event := data.(type) {
+	case *eventspb.AnalysisStatusEvent:
+		return map[string]string{
+			"content-type": "application/x-protobuf",
+			"event-type":   "AnalysisStatusEvent",
+			"producer":     "dependency-resolver",
+			"service":      event.ServiceName,
+		}
+	case *eventspb.AnalysisResultEvent:
+		return map[string]string{
+			"content-type": "application/x-protobuf",
+			"event-type":   "AnalysisResultEvent",
+			"producer":     "dependency-resolver",
+		}
+	default:
+		return map[string]string{
+			"content-type": "application/x-protobuf",
+			"event-type":   "UnknownEvent",
+			"producer":     "dependency-resolver",
+		}
+	}
+}
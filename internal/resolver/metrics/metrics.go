@@ -0,0 +1,75 @@
+// Package metrics — метрики Prometheus сервиса dependency-resolver
+package metrics
+
+import (
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "dependency_resolver"
+
+type Metrics struct {
+	analyses *prometheus.CounterVec
+	duration prometheus.Histogram
+	packages prometheus.Histogram
+}
+
+// NewMetrics регистрирует метрики в registerer
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		analyses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "analyses_total",
+			Help:      "Processed analysis requests by final status.",
+		}, []string{"status"}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "analysis_duration_seconds",
+			Help:      "Time spent resolving one analysis request.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+		}),
+		packages: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "resolved_packages",
+			Help:      "Number of packages in a resolved dependency set.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 11),
+		}),
+	}
+	registerer.MustRegister(m.analyses, m.duration, m.packages)
+	return m
+}
+
+// ObserveAnalysis учитывает завершённый анализ; packages учитывается только для успешных
+func (m *Metrics) ObserveAnalysis(status string, duration time.Duration, packages int) {
+	m.analyses.WithLabelValues(status).Inc()
+	m.duration.Observe(duration.Seconds())
+	if packages > 0 {
+		m.packages.Observe(float64(packages))
+	}
+}
+
+// RegisterCacheStats регистрирует счётчики кеша индексов пакетов; значения читаются из stats при каждом сборе
+func RegisterCacheStats(registerer prometheus.Registerer, stats func() pypi.CacheStats) {
+	counter := func(name, help string, value func(pypi.CacheStats) int64) prometheus.CounterFunc {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      name,
+			Help:      help,
+		}, func() float64 {
+			return float64(value(stats()))
+		})
+	}
+
+	registerer.MustRegister(
+		counter("index_cache_hits_total", "Index responses served from the cache without a request.",
+			func(s pypi.CacheStats) int64 { return s.Hits }),
+		counter("index_cache_misses_total", "Index responses downloaded in full.",
+			func(s pypi.CacheStats) int64 { return s.Misses }),
+		counter("index_cache_revalidations_total", "Expired cached responses confirmed by the index with 304 Not Modified.",
+			func(s pypi.CacheStats) int64 { return s.Revalidations }),
+		counter("index_cache_errors_total", "Redis errors that sent the request to the index.",
+			func(s pypi.CacheStats) int64 { return s.Errors }),
+	)
+}
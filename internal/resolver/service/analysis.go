@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/analysis"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep425"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep508"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/kafka"
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/metrics"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ServiceName подписывает события статуса resolver
+const ServiceName = "dependency-resolver"
+
+const publishTimeout = 10 * time.Second
+
+// Доля прогресса, которую занимает разрешение: остаток — публикация результата
+const resolveProgressShare = 90
+
+var ErrInvalidRequest = errors.New("invalid analysis request")
+
+// AnalysisService разрешает зависимости из AnalysisStartedEvent и публикует статусы и результат
+type AnalysisService struct {
+	index       pypi.Index
+	producer    kafka.Producer
+	metrics     *metrics.Metrics
+	timeout     time.Duration
+	maxPackages int
+	logger      logger.LoggerInterface
+}
+
+func NewAnalysisService(
+	index pypi.Index,
+	producer kafka.Producer,
+	metrics *metrics.Metrics,
+	cfg config.ResolverConfig,
+	logger logger.LoggerInterface,
+) *AnalysisService {
+	return &AnalysisService{
+		index:       index,
+		producer:    producer,
+		metrics:     metrics,
+		timeout:     cfg.Timeout,
+		maxPackages: cfg.MaxPackages,
+		logger:      logger,
+	}
+}
+
+// Handle обрабатывает один запрос. Ошибки разрешения сообщаются статусом failed,
+// наружу возвращаются только ошибки публикации: RequestConsumer повторяет такой запрос целиком,
+// пока события не будут опубликованы, и лишь затем подтверждает сообщение
+func (s *AnalysisService) Handle(ctx context.Context, event *eventspb.AnalysisStartedEvent) error {
+	contextLogger := s.logger.WithRequestID(event.RequestId)
+	started := time.Now()
+
+	contextLogger.Info("Resolving dependencies",
+		zap.String("python_version", event.PythonVersion),
+		zap.Int("packages_count", len(event.Packages)),
+		zap.Bool("locked", event.Locked),
+		zap.Bool("universal", event.Universal != nil),
+	)
+
+	if err := s.publishStatus(ctx, event.RequestId, analysis.StatusRunning, 0, "Resolving dependencies"); err != nil {
+		return err
+	}
+
+	resolution, err := s.resolve(ctx, event)
+	if err != nil {
+		contextLogger.Warn("Dependency resolution failed", zap.Error(err))
+		s.metrics.ObserveAnalysis(analysis.StatusFailed, time.Since(started), 0)
+		return s.publishFailure(ctx, event.RequestId, err)
+	}
+
+	if err := s.publishResult(ctx, event, resolution); err != nil {
+		contextLogger.Error("Failed to publish resolution result", zap.Error(err))
+		return err
+	}
+
+	s.metrics.ObserveAnalysis(analysis.StatusCompleted, time.Since(started), len(resolution.Packages))
+	contextLogger.Info("Dependencies resolved",
+		zap.Int("resolved_count", len(resolution.Packages)),
+		zap.Duration("duration", time.Since(started)),
+	)
+
+	return s.publishStatus(ctx, event.RequestId, analysis.StatusCompleted, 100,
+		fmt.Sprintf("Resolved %d packages", len(resolution.Packages)))
+}
+
+func (s *AnalysisService) resolve(ctx context.Context, event *eventspb.AnalysisStartedEvent) (*Resolution, error) {
+	roots, err := rootRequirements(event.Packages)
+	if err != nil {
+		return nil, err
+	}
+	constraints, err := parseRequirements("constraint", event.Constraints)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := parseRequirements("override", event.Overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	request := Request{
+		Roots:       roots,
+		Locked:      event.Locked,
+		Strategy:    resolutionStrategy(event),
+		Pinned:      event.PinnedVersions,
+		Constraints: constraints,
+		Overrides:   overrides,
+	}
+	if event.Universal != nil {
+		return s.resolveUniversal(ctx, event, request)
+	}
+
+	env, compat, err := targetEnvironment(event)
+	if err != nil {
+		return nil, err
+	}
+	request.Progress = s.progressReporter(ctx, event.RequestId, 1)(0, "")
+	return NewResolver(NewProvider(s.index, env, compat), s.maxPackages).Resolve(ctx, request)
+}
+
+// progressReporter публикует прогресс разрешения шагами по 10%. Универсальное разрешение
+// делит шкалу поровну между окружениями: возвращаемая функция даёт ProgressFunc окружения
+func (s *AnalysisService) progressReporter(ctx context.Context, requestID string, environments int) func(int, string) ProgressFunc {
+	reported := int64(0)
+	return func(environment int, id string) ProgressFunc {
+		return func(resolved int, discovered int) {
+			// Статус публикуется на каждом шаге в 10%, а не на каждый пакет
+			value := int64((environment*discovered + resolved) * resolveProgressShare / (max(discovered, 1) * environments))
+			if value/10 <= reported/10 {
+				return
+			}
+			reported = value
+			message := fmt.Sprintf("Resolved %d of %d discovered packages", resolved, discovered)
+			if id != "" {
+				message += " for " + id
+			}
+			if err := s.publishStatus(ctx, requestID, analysis.StatusRunning, value, message); err != nil {
+				s.logger.WithRequestID(requestID).Warn("Failed to publish progress", zap.Error(err))
+			}
+		}
+	}
+}
+
+// resolutionStrategy возвращает стратегию события. Gateway передаёт её нормализованной,
+// пустое значение у событий от старых версий означает analysis.StrategyHighest
+func resolutionStrategy(event *eventspb.AnalysisStartedEvent) string {
+	strategy, ok := analysis.NormalizeStrategy(event.ResolutionStrategy)
+	if !ok {
+		return analysis.StrategyHighest
+	}
+	return strategy
+}
+
+// targetEnvironment строит окружение маркеров и совместимость wheel.
+// Без целевой платформы подходят любые файлы, а маркеры вычисляются для Linux x86_64
+func targetEnvironment(event *eventspb.AnalysisStartedEvent) (pep508.Environment, *pep425.Compatibility, error) {
+	target := event.TargetPlatform
+	if target == nil {
+		return pep508.NewEnvironment(event.PythonVersion), nil, nil
+	}
+
+	compat, err := pep425.NewCompatibility(pep425.Platform{
+		PythonVersion: event.PythonVersion,
+		OS:            target.Os,
+		Arch:          target.Arch,
+		Libc:          target.Libc,
+		LibcVersion:   target.LibcVersion,
+		MacOSVersion:  target.MacosVersion,
+	})
+	if err != nil {
+		return pep508.Environment{}, nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	env, err := compat.Platform().MarkerEnvironment()
+	if err != nil {
+		return pep508.Environment{}, nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	return env, compat, nil
+}
+
+func rootRequirements(packages []*eventspb.AnalysisStartedEvent_RequiredPackage) ([]pep508.Requirement, error) {
+	roots := make([]pep508.Requirement, len(packages))
+	for i, pkg := range packages {
+		specifiers, err := pep440.ParseRequirementVersion(pkg.PackageVersion)
+		if err != nil {
+			return nil, fmt.Errorf("%w: package %s: %w", ErrInvalidRequest, pkg.PackageName, err)
+		}
+		roots[i] = pep508.Requirement{
+			Name:      pkg.PackageName,
+			Extras:    pkg.Extras,
+			Specifier: specifiers,
+			URL:       pkg.Url,
+		}
+		if pkg.Marker != "" {
+			if roots[i].Marker, err = pep508.ParseMarker(pkg.Marker); err != nil {
+				return nil, fmt.Errorf("%w: package %s: %w", ErrInvalidRequest, pkg.PackageName, err)
+			}
+		}
+	}
+	return roots, nil
+}
+
+func parseRequirements(kind string, lines []string) ([]pep508.Requirement, error) {
+	requirements := make([]pep508.Requirement, len(lines))
+	for i, line := range lines {
+		requirement, err := pep508.ParseRequirement(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %q: %w", ErrInvalidRequest, kind, line, err)
+		}
+		requirements[i] = requirement
+	}
+	return requirements, nil
+}
+
+func (s *AnalysisService) publishResult(ctx context.Context, event *eventspb.AnalysisStartedEvent, resolution *Resolution) error {
+	packages := make([]*eventspb.AnalysisResultEvent_ResolvedPackage, len(resolution.Packages))
+	for i, pkg := range resolution.Packages {
+		url := pkg.URL
+		if url == "" {
+			url = pkg.File.URL
+		}
+		packages[i] = &eventspb.AnalysisResultEvent_ResolvedPackage{
+			PackageName:  pkg.Name,
+			Version:      pkg.Version,
+			Extras:       pkg.Extras,
+			Dependencies: pkg.Dependencies,
+			Direct:       pkg.Direct,
+			Index:        pkg.Index,
+			Filename:     pkg.File.Filename,
+			Url:          url,
+			SdistOnly:    pkg.SdistOnly,
+			Marker:       pkg.Marker,
+			Environments: pkg.Environments,
+		}
+		for _, dependency := range pkg.ConditionalDependencies {
+			packages[i].ConditionalDependencies = append(packages[i].ConditionalDependencies,
+				&eventspb.AnalysisResultEvent_ConditionalDependency{
+					PackageName:  dependency.Name,
+					Marker:       dependency.Marker,
+					Environments: dependency.Environments,
+				})
+		}
+	}
+
+	conflicts := make([]*eventspb.AnalysisResultEvent_OverrideConflict, len(resolution.OverrideConflicts))
+	for i, conflict := range resolution.OverrideConflicts {
+		conflicts[i] = &eventspb.AnalysisResultEvent_OverrideConflict{
+			PackageName:     conflict.Package,
+			Version:         conflict.Version,
+			Requirement:     conflict.Requirement,
+			Override:        conflict.Override,
+			ResolvedVersion: conflict.ResolvedVersion,
+			Environments:    conflict.Environments,
+		}
+	}
+
+	var environments []*eventspb.AnalysisResultEvent_Environment
+	for _, environment := range resolution.Environments {
+		environments = append(environments, &eventspb.AnalysisResultEvent_Environment{
+			Id:            environment.ID,
+			PythonVersion: environment.Platform.PythonVersion,
+			Platform: &eventspb.TargetPlatform{
+				Os:           environment.Platform.OS,
+				Arch:         environment.Platform.Arch,
+				Libc:         environment.Platform.Libc,
+				LibcVersion:  environment.Platform.LibcVersion,
+				MacosVersion: environment.Platform.MacOSVersion,
+			},
+			Marker: environment.Marker,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	return s.producer.PublishResult(ctx, &eventspb.AnalysisResultEvent{
+		RequestId:          event.RequestId,
+		PythonVersion:      event.PythonVersion,
+		Packages:           packages,
+		Offline:            pypi.IsOffline(s.index),
+		TargetPlatform:     event.TargetPlatform,
+		Warnings:           resolution.Warnings,
+		Timestamp:          timestamppb.Now(),
+		ServiceName:        ServiceName,
+		ResolutionStrategy: resolutionStrategy(event),
+		Environments:       environments,
+		OverrideConflicts:  conflicts,
+	})
+}
+
+func (s *AnalysisService) publishStatus(ctx context.Context, requestID string, status string, progress int64, message string) error {
+	return s.publish(ctx, &eventspb.AnalysisStatusEvent{
+		RequestId: requestID,
+		Status:    status,
+		Message:   message,
+		Progress:  progress,
+	})
+}
+
+// publishFailure сообщает об ошибке разрешения. Для конфликта версий
+// к статусу прикладывается объяснение, из каких требований он следует
+func (s *AnalysisService) publishFailure(ctx context.Context, requestID string, err error) error {
+	event := &eventspb.AnalysisStatusEvent{
+		RequestId: requestID,
+		Status:    analysis.StatusFailed,
+		Message:   err.Error(),
+	}
+	var conflict *ConflictError
+	if errors.As(err, &conflict) {
+		event.Explanation = conflict.Explanation
+	}
+	return s.publish(ctx, event)
+}
+
+func (s *AnalysisService) publish(ctx context.Context, event *eventspb.AnalysisStatusEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	event.Timestamp = timestamppb.Now()
+	event.ServiceName = ServiceName
+	if err := s.producer.PublishStatus(ctx, event); err != nil {
+		return fmt.Errorf("failed to publish %s status: %w", event.Status, err)
+	}
+	return nil
+}
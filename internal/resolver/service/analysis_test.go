@@ -0,0 +1,610 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/analysis"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/config"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/logger"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/mocks"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep425"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep508"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/kafka"
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/metrics"
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/service"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// release — версия пакета на тестовом индексе. Без files публикуется один sdist
+type release struct {
+	requiresDist   []string
+	requiresPython string
+	files          []string
+	yanked         bool
+}
+
+// newPyPIServer поднимает заглушку JSON API PyPI с пакетами packages[name][version]
+func newPyPIServer(t *testing.T, packages map[string]map[string]release) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/pypi/"), "/"), "/")
+		releases, ok := packages[parts[0]]
+		if !ok || parts[len(parts)-1] != "json" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var body any
+		switch len(parts) {
+		case 2:
+			project := pypi.Project{Info: pypi.Info{Name: parts[0]}, Releases: make(map[string][]pypi.File)}
+			for version, rel := range releases {
+				project.Releases[version] = releaseFiles(parts[0], version, rel)
+			}
+			body = project
+		case 3:
+			rel, ok := releases[parts[1]]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			body = pypi.Release{
+				Info: pypi.Info{
+					Name:           parts[0],
+					Version:        parts[1],
+					RequiresDist:   rel.requiresDist,
+					RequiresPython: rel.requiresPython,
+				},
+				URLs: releaseFiles(parts[0], parts[1], rel),
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func releaseFiles(name string, version string, rel release) []pypi.File {
+	filenames := rel.files
+	if len(filenames) == 0 {
+		filenames = []string{fmt.Sprintf("%s-%s.tar.gz", strings.ReplaceAll(name, "-", "_"), version)}
+	}
+
+	files := make([]pypi.File, len(filenames))
+	for i, filename := range filenames {
+		files[i] = pypi.File{
+			Filename:       filename,
+			RequiresPython: rel.requiresPython,
+			URL:            "https://files.example.com/" + filename,
+			Yanked:         rel.yanked,
+		}
+	}
+	return files
+}
+
+// flaskIndex — небольшой срез PyPI вокруг flask и requests
+var flaskIndex = map[string]map[string]release{
+	"flask": {
+		"2.3.3": {requiresDist: []string{"Werkzeug>=2.3.7", "Jinja2>=3.1.2", "click>=8.1.3"}},
+		"3.0.3": {
+			requiresDist: []string{
+				"Werkzeug>=3.0.0",
+				"Jinja2>=3.1.2",
+				"click>=8.1.3",
+				"importlib-metadata>=3.6.0; python_version < '3.10'",
+				"asgiref>=3.2; extra == 'async'",
+			},
+			requiresPython: ">=3.8",
+		},
+		"4.0.0": {requiresPython: ">=3.13"},
+	},
+	"werkzeug": {
+		"2.3.8": {requiresDist: []string{"MarkupSafe>=2.1.1"}},
+		"3.0.3": {requiresDist: []string{"MarkupSafe>=2.1.1"}},
+		"3.1.0": {requiresDist: []string{"MarkupSafe>=2.1.1"}, yanked: true},
+	},
+	"jinja2": {
+		"3.1.4": {requiresDist: []string{"MarkupSafe>=2.0", "Babel>=2.7; extra == 'i18n'"}},
+	},
+	"markupsafe": {
+		"2.1.5":    {},
+		"3.0.0rc1": {},
+	},
+	"click": {
+		"8.1.7": {requiresDist: []string{"colorama; platform_system == 'Windows'"}},
+	},
+	"colorama":           {"0.4.6": {}},
+	"importlib-metadata": {"8.0.0": {}},
+	"requests": {
+		"2.32.3": {requiresDist: []string{"idna<4,>=2.5", "PySocks!=1.5.7,>=1.5.6; extra == 'socks'"}},
+	},
+	"idna":    {"3.7": {}},
+	"pysocks": {"1.7.1": {}},
+}
+
+func newTestService(t *testing.T, index pypi.Index) (*service.AnalysisService, *[]*eventspb.AnalysisStatusEvent, *[]*eventspb.AnalysisResultEvent) {
+	var statuses []*eventspb.AnalysisStatusEvent
+	var results []*eventspb.AnalysisResultEvent
+
+	producer := mocks.NewMockResolverProducer()
+	producer.On("PublishStatus", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			statuses = append(statuses, args.Get(1).(*eventspb.AnalysisStatusEvent))
+		}).
+		Return(nil)
+	producer.On("PublishResult", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			results = append(results, args.Get(1).(*eventspb.AnalysisResultEvent))
+		}).
+		Return(nil)
+
+	analysisService := service.NewAnalysisService(
+		index,
+		producer,
+		metrics.NewMetrics(prometheus.NewRegistry()),
+		config.ResolverConfig{Timeout: 10 * time.Second, MaxPackages: 100},
+		&logger.Logger{Logger: zap.NewNop()},
+	)
+	return analysisService, &statuses, &results
+}
+
+func newTestIndex(server *httptest.Server) pypi.Index {
+	return pypi.NewMultiIndex(config.PyPIConfig{APIURL: server.URL + "/pypi", RequestTimeout: time.Second})
+}
+
+func resolvedByName(event *eventspb.AnalysisResultEvent) map[string]*eventspb.AnalysisResultEvent_ResolvedPackage {
+	packages := make(map[string]*eventspb.AnalysisResultEvent_ResolvedPackage, len(event.Packages))
+	for _, pkg := range event.Packages {
+		packages[pkg.PackageName] = pkg
+	}
+	return packages
+}
+
+func TestAnalysisService_ResolvesTransitiveDependencies(t *testing.T) {
+	server := newPyPIServer(t, flaskIndex)
+	analysisService, statuses, results := newTestService(t, newTestIndex(server))
+
+	err := analysisService.Handle(t.Context(), &eventspb.AnalysisStartedEvent{
+		RequestId:     "req-1",
+		PythonVersion: "3.12",
+		Packages: []*eventspb.AnalysisStartedEvent_RequiredPackage{
+			{PackageName: "flask", PackageVersion: ">=2"},
+			{PackageName: "requests", Extras: []string{"socks"}},
+			{PackageName: "tomli", Marker: `python_version < "3.11"`},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *results, 1)
+	result := (*results)[0]
+	assert.Equal(t, "req-1", result.RequestId)
+	assert.False(t, result.Offline)
+	assert.Equal(t, analysis.StrategyHighest, result.ResolutionStrategy)
+
+	versions := make(map[string]string)
+	for _, pkg := range result.Packages {
+		versions[pkg.PackageName] = pkg.Version
+	}
+	assert.Equal(t, map[string]string{
+		"flask":      "3.0.3",
+		"werkzeug":   "3.0.3",
+		"jinja2":     "3.1.4",
+		"markupsafe": "2.1.5",
+		"click":      "8.1.7",
+		"requests":   "2.32.3",
+		"idna":       "3.7",
+		"pysocks":    "1.7.1",
+	}, versions)
+
+	packages := resolvedByName(result)
+	assert.True(t, packages["flask"].Direct)
+	assert.False(t, packages["jinja2"].Direct)
+	assert.Equal(t, []string{"click", "jinja2", "werkzeug"}, packages["flask"].Dependencies)
+	assert.Equal(t, []string{"socks"}, packages["requests"].Extras)
+	assert.Equal(t, "pypi", packages["idna"].Index)
+	assert.Equal(t, "https://files.example.com/idna-3.7.tar.gz", packages["idna"].Url)
+
+	first, last := (*statuses)[0], (*statuses)[len(*statuses)-1]
+	assert.Equal(t, analysis.StatusRunning, first.Status)
+	assert.Equal(t, service.ServiceName, first.ServiceName)
+	assert.Equal(t, analysis.StatusCompleted, last.Status)
+	assert.Equal(t, int64(100), last.Progress)
+	for i := 1; i < len(*statuses); i++ {
+		assert.GreaterOrEqual(t, (*statuses)[i].Progress, (*statuses)[i-1].Progress)
+	}
+}
+
+func TestAnalysisService_TargetPlatformSelectsFiles(t *testing.T) {
+	server := newPyPIServer(t, map[string]map[string]release{
+		"numpy": {
+			"1.26.4": {files: []string{
+				"numpy-1.26.4.tar.gz",
+				"numpy-1.26.4-cp312-cp312-macosx_11_0_arm64.whl",
+				"numpy-1.26.4-cp312-cp312-manylinux_2_17_x86_64.manylinux2014_x86_64.whl",
+			}},
+			"2.0.0": {files: []string{"numpy-2.0.0-cp312-cp312-manylinux_2_17_x86_64.whl"}},
+		},
+	})
+
+	tests := []struct {
+		name      string
+		platform  *eventspb.TargetPlatform
+		version   string
+		filename  string
+		sdistOnly bool
+	}{
+		{"linux", &eventspb.TargetPlatform{Os: "linux", Arch: "x86_64"}, "2.0.0",
+			"numpy-2.0.0-cp312-cp312-manylinux_2_17_x86_64.whl", false},
+		{"macos", &eventspb.TargetPlatform{Os: "macos", Arch: "arm64"}, "1.26.4",
+			"numpy-1.26.4-cp312-cp312-macosx_11_0_arm64.whl", false},
+		{"musl", &eventspb.TargetPlatform{Os: "linux", Arch: "x86_64", Libc: "musl"}, "1.26.4",
+			"numpy-1.26.4.tar.gz", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysisService, _, results := newTestService(t, newTestIndex(server))
+
+			err := analysisService.Handle(t.Context(), &eventspb.AnalysisStartedEvent{
+				RequestId:      "req-" + tt.name,
+				PythonVersion:  "3.12",
+				Packages:       []*eventspb.AnalysisStartedEvent_RequiredPackage{{PackageName: "numpy"}},
+				TargetPlatform: tt.platform,
+			})
+			require.NoError(t, err)
+
+			require.Len(t, *results, 1)
+			numpy := (*results)[0].Packages[0]
+			assert.Equal(t, tt.version, numpy.Version)
+			assert.Equal(t, tt.filename, numpy.Filename)
+			assert.Equal(t, tt.sdistOnly, numpy.SdistOnly)
+		})
+	}
+}
+
+func TestAnalysisService_ReportsFailure(t *testing.T) {
+	server := newPyPIServer(t, flaskIndex)
+
+	tests := []struct {
+		name        string
+		packages    []*eventspb.AnalysisStartedEvent_RequiredPackage
+		explanation []string
+	}{
+		{"no matching version", []*eventspb.AnalysisStartedEvent_RequiredPackage{
+			{PackageName: "flask", PackageVersion: ">=5"},
+		}, []string{
+			"Because the request requires flask>=5, for which no version is available, the requirements are unsatisfiable.",
+		}},
+		{"unknown package", []*eventspb.AnalysisStartedEvent_RequiredPackage{
+			{PackageName: "does-not-exist"},
+		}, []string{
+			"Because the request requires does-not-exist, which is not in the package index, the requirements are unsatisfiable.",
+		}},
+		{"conflict", []*eventspb.AnalysisStartedEvent_RequiredPackage{
+			{PackageName: "werkzeug", PackageVersion: "<3"},
+			{PackageName: "flask", PackageVersion: ">=3"},
+		}, []string{
+			"Because flask 3.0.3 depends on werkzeug>=3.0.0 and the request requires werkzeug<3, flask>=3.0.3 cannot be used.",
+			"And because the request requires flask>=3, the requirements are unsatisfiable.",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysisService, statuses, results := newTestService(t, newTestIndex(server))
+
+			err := analysisService.Handle(t.Context(), &eventspb.AnalysisStartedEvent{
+				RequestId:     "req-failed",
+				PythonVersion: "3.12",
+				Packages:      tt.packages,
+			})
+			require.NoError(t, err)
+
+			assert.Empty(t, *results)
+			last := (*statuses)[len(*statuses)-1]
+			assert.Equal(t, analysis.StatusFailed, last.Status)
+			assert.Contains(t, last.Message, service.ErrConflict.Error())
+			assert.Equal(t, tt.explanation, last.Explanation)
+		})
+	}
+}
+
+func TestAnalysisService_RetriesAfterPublishFailure(t *testing.T) {
+	server := newPyPIServer(t, flaskIndex)
+
+	var statuses []*eventspb.AnalysisStatusEvent
+	var results []*eventspb.AnalysisResultEvent
+
+	producer := mocks.NewMockResolverProducer()
+	producer.On("PublishStatus", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+	producer.On("PublishStatus", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			statuses = append(statuses, args.Get(1).(*eventspb.AnalysisStatusEvent))
+		}).
+		Return(nil)
+	producer.On("PublishResult", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			results = append(results, args.Get(1).(*eventspb.AnalysisResultEvent))
+		}).
+		Return(nil)
+
+	analysisService := service.NewAnalysisService(
+		newTestIndex(server),
+		producer,
+		metrics.NewMetrics(prometheus.NewRegistry()),
+		config.ResolverConfig{Timeout: 10 * time.Second, MaxPackages: 100},
+		&logger.Logger{Logger: zap.NewNop()},
+	)
+	handler := kafka.RetryHandler(analysisService.Handle, time.Millisecond, 10*time.Millisecond)
+
+	err := handler(t.Context(), &eventspb.AnalysisStartedEvent{
+		RequestId:     "req-retry",
+		PythonVersion: "3.12",
+		Packages:      []*eventspb.AnalysisStartedEvent_RequiredPackage{{PackageName: "click"}},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "req-retry", results[0].RequestId)
+	require.NotEmpty(t, statuses)
+	assert.Equal(t, analysis.StatusRunning, statuses[0].Status)
+	assert.Equal(t, analysis.StatusCompleted, statuses[len(statuses)-1].Status)
+}
+
+func TestAnalysisService_RetryStopsOnCancel(t *testing.T) {
+	producer := mocks.NewMockResolverProducer()
+	producer.On("PublishStatus", mock.Anything, mock.Anything).Return(assert.AnError)
+
+	analysisService := service.NewAnalysisService(
+		pypi.NewSnapshot(),
+		producer,
+		metrics.NewMetrics(prometheus.NewRegistry()),
+		config.ResolverConfig{Timeout: 10 * time.Second, MaxPackages: 100},
+		&logger.Logger{Logger: zap.NewNop()},
+	)
+	handler := kafka.RetryHandler(analysisService.Handle, time.Millisecond, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	err := handler(ctx, &eventspb.AnalysisStartedEvent{RequestId: "req-cancel", PythonVersion: "3.12"})
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Greater(t, len(producer.Calls), 1)
+}
+
+func TestAnalysisService_LockedAndOffline(t *testing.T) {
+	snapshot := pypi.NewSnapshot()
+	for name, releases := range flaskIndex {
+		for version, rel := range releases {
+			for _, file := range releaseFiles(name, version, rel) {
+				file.Version = version
+				snapshot.Add(&pypi.Metadata{Name: name, Version: version, RequiresDist: rel.requiresDist}, file)
+			}
+		}
+	}
+	analysisService, _, results := newTestService(t, snapshot)
+
+	err := analysisService.Handle(t.Context(), &eventspb.AnalysisStartedEvent{
+		RequestId:     "req-locked",
+		PythonVersion: "3.12",
+		Locked:        true,
+		Packages: []*eventspb.AnalysisStartedEvent_RequiredPackage{
+			{PackageName: "flask", PackageVersion: "==2.3.3"},
+			{PackageName: "werkzeug", PackageVersion: "==2.3.8"},
+			{PackageName: "httpx", Url: "git+https://github.com/encode/httpx@0.27.0"},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *results, 1)
+	result := (*results)[0]
+	assert.True(t, result.Offline)
+	require.Len(t, result.Packages, 3)
+
+	packages := resolvedByName(result)
+	assert.Equal(t, "2.3.3", packages["flask"].Version)
+	assert.Empty(t, packages["flask"].Dependencies)
+	assert.Equal(t, "git+https://github.com/encode/httpx@0.27.0", packages[pep503.Normalize("httpx")].Url)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "httpx")
+}
+
+func TestAnalysisService_RecordsResolutionStrategy(t *testing.T) {
+	server := newPyPIServer(t, flaskIndex)
+	analysisService, _, results := newTestService(t, newTestIndex(server))
+
+	err := analysisService.Handle(t.Context(), &eventspb.AnalysisStartedEvent{
+		RequestId:          "req-lowest",
+		PythonVersion:      "3.12",
+		Packages:           []*eventspb.AnalysisStartedEvent_RequiredPackage{{PackageName: "flask", PackageVersion: ">=2"}},
+		ResolutionStrategy: analysis.StrategyLowestDirect,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *results, 1)
+	result := (*results)[0]
+	assert.Equal(t, analysis.StrategyLowestDirect, result.ResolutionStrategy)
+
+	packages := resolvedByName(result)
+	assert.Equal(t, "2.3.3", packages["flask"].Version)
+	assert.Equal(t, "3.0.3", packages["werkzeug"].Version)
+}
+
+func TestAnalysisService_UniversalResolution(t *testing.T) {
+	index := map[string]map[string]release{"pywin32": {"306": {}}}
+	for name, releases := range flaskIndex {
+		index[name] = releases
+	}
+	server := newPyPIServer(t, index)
+	analysisService, _, results := newTestService(t, newTestIndex(server))
+
+	err := analysisService.Handle(t.Context(), &eventspb.AnalysisStartedEvent{
+		RequestId: "req-universal",
+		Packages: []*eventspb.AnalysisStartedEvent_RequiredPackage{
+			{PackageName: "flask", PackageVersion: ">=2"},
+			{PackageName: "pywin32", Marker: `sys_platform == "win32"`},
+		},
+		Universal: &eventspb.UniversalResolution{
+			PythonVersions: []string{"3.13", "3.9"},
+			Platforms: []*eventspb.TargetPlatform{
+				{Os: "linux", Arch: "x86_64", Libc: "glibc", LibcVersion: "2.28"},
+				{Os: "macos", Arch: "arm64", MacosVersion: "14.0"},
+				{Os: "windows", Arch: "x86_64"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *results, 1)
+	result := (*results)[0]
+	ids := make([]string, len(result.Environments))
+	for i, environment := range result.Environments {
+		ids[i] = environment.Id
+	}
+	assert.Equal(t, []string{
+		"cp39-linux-x86_64", "cp39-macos-arm64", "cp39-windows-x86_64",
+		"cp313-linux-x86_64", "cp313-macos-arm64", "cp313-windows-x86_64",
+	}, ids)
+	assert.Equal(t, `python_version == "3.9" and sys_platform == "win32" and platform_machine == "AMD64"`,
+		result.Environments[2].Marker)
+
+	markers := make(map[string]string)
+	for _, pkg := range result.Packages {
+		markers[pkg.PackageName+"=="+pkg.Version] = pkg.Marker
+	}
+	assert.Equal(t, map[string]string{
+		"click==8.1.7":              `python_version == "3.9"`,
+		"colorama==0.4.6":           `python_version == "3.9" and sys_platform == "win32"`,
+		"flask==3.0.3":              `python_version == "3.9"`,
+		"flask==4.0.0":              `python_version == "3.13"`,
+		"importlib-metadata==8.0.0": `python_version == "3.9"`,
+		"jinja2==3.1.4":             `python_version == "3.9"`,
+		"markupsafe==2.1.5":         `python_version == "3.9"`,
+		"pywin32==306":              `sys_platform == "win32"`,
+		"werkzeug==3.0.3":           `python_version == "3.9"`,
+	}, markers)
+
+	click := resolvedByName(result)["click"]
+	assert.Empty(t, click.Dependencies)
+	require.Len(t, click.ConditionalDependencies, 1)
+	assert.Equal(t, "colorama", click.ConditionalDependencies[0].PackageName)
+	assert.Equal(t, []string{"cp39-windows-x86_64"}, click.ConditionalDependencies[0].Environments)
+
+	// Маркер каждого пакета выполняется ровно в тех окружениях, куда он ставится
+	for _, environment := range result.Environments {
+		env, err := pep425.Platform{
+			PythonVersion: environment.PythonVersion,
+			OS:            environment.Platform.Os,
+			Arch:          environment.Platform.Arch,
+		}.MarkerEnvironment()
+		require.NoError(t, err)
+		for _, pkg := range result.Packages {
+			marker, err := pep508.ParseMarker(pkg.Marker)
+			require.NoError(t, err)
+			assert.Equal(t, slices.Contains(pkg.Environments, environment.Id), marker.Evaluate(env),
+				"%s %s in %s", pkg.PackageName, pkg.Version, environment.Id)
+		}
+	}
+
+	selected, err := analysis.SelectEnvironment(result, "cp39-windows-x86_64")
+	require.NoError(t, err)
+	versions := make(map[string]string)
+	for _, pkg := range selected {
+		versions[pkg.PackageName] = pkg.Version
+		if pkg.PackageName == "click" {
+			assert.Equal(t, []string{"colorama"}, pkg.Dependencies)
+		}
+	}
+	assert.Equal(t, "3.0.3", versions["flask"])
+	assert.Contains(t, versions, "pywin32")
+	assert.Contains(t, versions, "importlib-metadata")
+
+	selected, err = analysis.SelectEnvironment(result, "cp313-linux-x86_64")
+	require.NoError(t, err)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "4.0.0", selected[0].Version)
+
+	_, err = analysis.SelectEnvironment(result, "cp27-linux-x86_64")
+	assert.ErrorIs(t, err, analysis.ErrUnknownEnvironment)
+}
+
+func TestAnalysisService_UniversalConflictNamesEnvironment(t *testing.T) {
+	server := newPyPIServer(t, flaskIndex)
+	analysisService, statuses, results := newTestService(t, newTestIndex(server))
+
+	err := analysisService.Handle(t.Context(), &eventspb.AnalysisStartedEvent{
+		RequestId: "req-universal-conflict",
+		Packages: []*eventspb.AnalysisStartedEvent_RequiredPackage{
+			{PackageName: "flask", PackageVersion: ">=3"},
+			{PackageName: "importlib-metadata", PackageVersion: "<8"},
+		},
+		Universal: &eventspb.UniversalResolution{
+			PythonVersions: []string{"3.9", "3.12"},
+			Platforms:      []*eventspb.TargetPlatform{{Os: "linux", Arch: "x86_64"}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, *results)
+	last := (*statuses)[len(*statuses)-1]
+	assert.Equal(t, analysis.StatusFailed, last.Status)
+	require.NotEmpty(t, last.Explanation)
+	assert.Equal(t, `In environment cp39-linux-x86_64 (python_version == "3.9" and sys_platform == "linux" and platform_machine == "x86_64"):`,
+		last.Explanation[0])
+}
+
+func TestAnalysisService_ConstraintsAndOverrides(t *testing.T) {
+	server := newPyPIServer(t, flaskIndex)
+	analysisService, _, results := newTestService(t, newTestIndex(server))
+
+	err := analysisService.Handle(t.Context(), &eventspb.AnalysisStartedEvent{
+		RequestId:     "req-constraints",
+		PythonVersion: "3.12",
+		Packages:      []*eventspb.AnalysisStartedEvent_RequiredPackage{{PackageName: "flask", PackageVersion: ">=2"}},
+		Constraints:   []string{"werkzeug<3"},
+	})
+	require.NoError(t, err)
+
+	err = analysisService.Handle(t.Context(), &eventspb.AnalysisStartedEvent{
+		RequestId:     "req-overrides",
+		PythonVersion: "3.12",
+		Packages:      []*eventspb.AnalysisStartedEvent_RequiredPackage{{PackageName: "flask", PackageVersion: ">=3"}},
+		Overrides:     []string{"werkzeug==2.3.8"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *results, 2)
+	constrained := resolvedByName((*results)[0])
+	assert.Equal(t, "2.3.3", constrained["flask"].Version)
+	assert.Equal(t, "2.3.8", constrained["werkzeug"].Version)
+	assert.Empty(t, (*results)[0].OverrideConflicts)
+
+	overridden := (*results)[1]
+	assert.Equal(t, "2.3.8", resolvedByName(overridden)["werkzeug"].Version)
+	require.Len(t, overridden.OverrideConflicts, 1)
+	conflict := overridden.OverrideConflicts[0]
+	assert.Equal(t, "flask", conflict.PackageName)
+	assert.Equal(t, "3.0.3", conflict.Version)
+	assert.Equal(t, "werkzeug>=3.0.0", conflict.Requirement)
+	assert.Equal(t, "werkzeug==2.3.8", conflict.Override)
+	assert.Equal(t, "2.3.8", conflict.ResolvedVersion)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// explainer выводит дерево вывода несовместимостей по строкам, как DefaultStringReporter
+// из pubgrub-rs: выводы, на которые ссылаются дважды, нумеруются и дальше упоминаются по номеру
+type explainer struct {
+	solver *solver
+	lines  []string
+	// shared — выводы, используемые в дереве больше одного раза
+	shared map[*incompatibility]bool
+	refs   map[*incompatibility]int
+	count  int
+}
+
+func (s *solver) explain(failure *incompatibility) []string {
+	e := &explainer{
+		solver: s,
+		shared: make(map[*incompatibility]bool),
+		refs:   make(map[*incompatibility]int),
+	}
+
+	if failure.kind != kindDerived {
+		return []string{fmt.Sprintf("Because %s, %s.", e.external(failure), e.conclusion(failure))}
+	}
+
+	seen := make(map[*incompatibility]bool)
+	var mark func(i *incompatibility)
+	mark = func(i *incompatibility) {
+		if i.kind != kindDerived {
+			return
+		}
+		if seen[i] {
+			e.shared[i] = true
+			return
+		}
+		seen[i] = true
+		mark(i.causes[0])
+		mark(i.causes[1])
+	}
+	mark(failure)
+
+	e.visit(failure)
+	return e.lines
+}
+
+func (e *explainer) visit(current *incompatibility) {
+	e.visitCauses(current)
+	if e.shared[current] {
+		if _, ok := e.refs[current]; !ok {
+			e.addRef(current)
+		}
+	}
+}
+
+func (e *explainer) visitCauses(current *incompatibility) {
+	first, second := current.causes[0], current.causes[1]
+	conclusion := e.conclusion(current)
+
+	switch {
+	case first.kind != kindDerived && second.kind != kindDerived:
+		e.add("Because %s and %s, %s.", e.external(first), e.external(second), conclusion)
+
+	case first.kind == kindDerived && second.kind == kindDerived:
+		firstRef, firstOK := e.refs[first]
+		secondRef, secondOK := e.refs[second]
+		switch {
+		case firstOK && secondOK:
+			e.add("Because %s (%d) and %s (%d), %s.",
+				e.conclusion(first), firstRef, e.conclusion(second), secondRef, conclusion)
+		case firstOK:
+			e.visit(second)
+			e.add("And because %s (%d), %s.", e.conclusion(first), firstRef, conclusion)
+		case secondOK:
+			e.visit(first)
+			e.add("And because %s (%d), %s.", e.conclusion(second), secondRef, conclusion)
+		default:
+			e.visit(first)
+			ref, ok := e.refs[first]
+			if !ok {
+				ref = e.addRef(first)
+			}
+			e.lines = append(e.lines, "")
+			e.visit(second)
+			e.add("And because %s (%d), %s.", e.conclusion(first), ref, conclusion)
+		}
+
+	default:
+		derived, external := first, second
+		if second.kind == kindDerived {
+			derived, external = second, first
+		}
+		if ref, ok := e.refs[derived]; ok {
+			e.add("Because %s (%d) and %s, %s.", e.conclusion(derived), ref, e.external(external), conclusion)
+			return
+		}
+
+		// Цепочку из вывода и внешней причины выгоднее рассказать одной фразой
+		priorFirst, priorSecond := derived.causes[0], derived.causes[1]
+		if !e.shared[derived] && (priorFirst.kind == kindDerived) != (priorSecond.kind == kindDerived) {
+			prior, priorExternal := priorFirst, priorSecond
+			if priorSecond.kind == kindDerived {
+				prior, priorExternal = priorSecond, priorFirst
+			}
+			e.visit(prior)
+			e.add("And because %s and %s, %s.", e.external(priorExternal), e.external(external), conclusion)
+			return
+		}
+
+		e.visit(derived)
+		e.add("And because %s, %s.", e.external(external), conclusion)
+	}
+}
+
+func (e *explainer) add(format string, args ...any) {
+	e.lines = append(e.lines, fmt.Sprintf(format, args...))
+}
+
+// addRef нумерует последнюю строку, чтобы дальше ссылаться на вывод current
+func (e *explainer) addRef(current *incompatibility) int {
+	e.count++
+	e.refs[current] = e.count
+	if len(e.lines) > 0 {
+		e.lines[len(e.lines)-1] += fmt.Sprintf(" (%d)", e.count)
+	}
+	return e.count
+}
+
+// external описывает внешнюю причину несовместимости
+func (e *explainer) external(i *incompatibility) string {
+	switch i.kind {
+	case kindRoot:
+		return "the request must be resolved"
+	case kindConstraint:
+		return "the constraints require " + i.requirement
+	}
+
+	dependency := i.parent + " " + i.version + " depends on " + i.requirement
+	if i.parent == rootPackage {
+		dependency = "the request requires " + i.requirement
+	}
+	if i.unavailable != "" {
+		dependency += ", " + i.unavailable
+	}
+	return dependency
+}
+
+// conclusion формулирует, что следует из несовместимости
+func (e *explainer) conclusion(i *incompatibility) string {
+	terms := i.terms
+	if len(terms) > 1 {
+		var withoutRoot []term
+		for _, t := range terms {
+			if t.pkg != rootPackage {
+				withoutRoot = append(withoutRoot, t)
+			}
+		}
+		terms = withoutRoot
+	}
+
+	switch {
+	case len(terms) == 0 || terms[0].pkg == rootPackage:
+		return "the requirements are unsatisfiable"
+	case len(terms) == 1 && terms[0].positive:
+		return e.solver.describe(terms[0]) + " cannot be used"
+	case len(terms) == 1:
+		return e.solver.describe(terms[0].negate()) + " is required"
+	case len(terms) == 2 && terms[0].positive != terms[1].positive:
+		dependent, dependency := terms[0], terms[1]
+		if !dependent.positive {
+			dependent, dependency = dependency, dependent
+		}
+		return e.solver.describe(dependent) + " depends on " + e.solver.describe(dependency.negate())
+	}
+
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		if t.positive {
+			parts[i] = e.solver.describe(t)
+		} else {
+			parts[i] = "not " + e.solver.describe(t.negate())
+		}
+	}
+	last := len(parts) - 1
+	return strings.Join(parts[:last], ", ") + " and " + parts[last] + " are incompatible"
+}
@@ -0,0 +1,104 @@
+package service
+
+// rootPackage — виртуальный пакет запроса, его зависимости — требования пользователя
+const rootPackage = ""
+
+type incompatibilityKind int
+
+const (
+	// kindRoot — запрос должен быть разрешён
+	kindRoot incompatibilityKind = iota
+	// kindDependency — версия пакета требует зависимость
+	kindDependency
+	// kindDerived — выведена при разборе конфликта из двух других
+	kindDerived
+	// kindConstraint — constraint запроса исключает версии пакета, не требуя сам пакет
+	kindConstraint
+)
+
+// incompatibility — набор термов, которые не могут выполняться одновременно
+type incompatibility struct {
+	terms []term
+	kind  incompatibilityKind
+
+	// Для kindDependency: кто требует, какая версия и текст требования.
+	// Для kindConstraint заполнен только текст constraint
+	parent      string
+	version     string
+	requirement string
+	// unavailable объясняет, почему у зависимости нет ни одной подходящей версии
+	unavailable string
+
+	causes [2]*incompatibility
+}
+
+func dependencyIncompatibility(parent term, version string, dependency term, requirement string) *incompatibility {
+	return &incompatibility{
+		terms:       []term{parent, dependency.negate()},
+		kind:        kindDependency,
+		parent:      parent.pkg,
+		version:     version,
+		requirement: requirement,
+	}
+}
+
+// unavailableIncompatibility запрещает версию, зависимость которой ничем не удовлетворить
+func unavailableIncompatibility(parent term, version string, requirement string, reason string) *incompatibility {
+	return &incompatibility{
+		terms:       []term{parent},
+		kind:        kindDependency,
+		parent:      parent.pkg,
+		version:     version,
+		requirement: requirement,
+		unavailable: reason,
+	}
+}
+
+// constraintIncompatibility запрещает версии excluded, пока запрос разрешается
+func constraintIncompatibility(excluded term, requirement string) *incompatibility {
+	return &incompatibility{
+		terms:       []term{{pkg: rootPackage, positive: true, set: fullSet(1)}, excluded},
+		kind:        kindConstraint,
+		requirement: requirement,
+	}
+}
+
+// derivedIncompatibility объединяет термы, как в pub: положительный терм корня
+// всегда выполнен и отбрасывается, термы одного пакета пересекаются
+func derivedIncompatibility(terms []term, conflict *incompatibility, satisfierCause *incompatibility) *incompatibility {
+	if len(terms) > 1 {
+		var withoutRoot []term
+		for _, t := range terms {
+			if !(t.positive && t.pkg == rootPackage) {
+				withoutRoot = append(withoutRoot, t)
+			}
+		}
+		terms = withoutRoot
+	}
+
+	var order []string
+	byPackage := make(map[string]term)
+	for _, t := range terms {
+		if existing, ok := byPackage[t.pkg]; ok {
+			byPackage[t.pkg] = existing.intersect(t)
+			continue
+		}
+		order = append(order, t.pkg)
+		byPackage[t.pkg] = t
+	}
+
+	merged := make([]term, len(order))
+	for i, pkg := range order {
+		merged[i] = byPackage[pkg]
+	}
+	return &incompatibility{
+		terms:  merged,
+		kind:   kindDerived,
+		causes: [2]*incompatibility{conflict, satisfierCause},
+	}
+}
+
+// isFailure — запрос неразрешим: термов нет или остался только корень
+func (i *incompatibility) isFailure() bool {
+	return len(i.terms) == 0 || (len(i.terms) == 1 && i.terms[0].pkg == rootPackage)
+}
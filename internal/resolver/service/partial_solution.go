@@ -0,0 +1,120 @@
+package service
+
+// assignment — решение (выбрана версия) или вывод из несовместимости cause
+type assignment struct {
+	term          term
+	decisionLevel int
+	index         int
+	// cause == nil у решений
+	cause *incompatibility
+}
+
+// partialSolution — упорядоченные назначения и их пересечение по каждому пакету
+type partialSolution struct {
+	assignments []assignment
+	// terms — пересечение всех назначений пакета
+	terms map[string]term
+	// decisions — индекс выбранной версии пакета
+	decisions map[string]int
+	// order — пакеты в порядке первого назначения, чтобы выбор следующего был детерминированным
+	order []string
+	level int
+}
+
+func newPartialSolution() *partialSolution {
+	return &partialSolution{
+		terms:     make(map[string]term),
+		decisions: make(map[string]int),
+	}
+}
+
+func (s *partialSolution) decide(pkg string, index int, size int) {
+	s.level++
+	s.decisions[pkg] = index
+	s.assign(term{pkg: pkg, positive: true, set: singletonSet(size, index)}, nil)
+}
+
+func (s *partialSolution) derive(t term, cause *incompatibility) {
+	s.assign(t, cause)
+}
+
+func (s *partialSolution) assign(t term, cause *incompatibility) {
+	s.assignments = append(s.assignments, assignment{
+		term:          t,
+		decisionLevel: s.level,
+		index:         len(s.assignments),
+		cause:         cause,
+	})
+	if existing, ok := s.terms[t.pkg]; ok {
+		s.terms[t.pkg] = existing.intersect(t)
+		return
+	}
+	s.terms[t.pkg] = t
+	s.order = append(s.order, t.pkg)
+}
+
+// backtrack отменяет все назначения после уровня решений level
+func (s *partialSolution) backtrack(level int) {
+	assignments := s.assignments
+	*s = *newPartialSolution()
+	for _, a := range assignments {
+		if a.decisionLevel > level {
+			break
+		}
+		s.level = a.decisionLevel
+		if a.cause == nil {
+			index, _ := a.term.set.first()
+			s.decisions[a.term.pkg] = index
+		}
+		s.assign(a.term, a.cause)
+	}
+	s.level = level
+}
+
+// relation сравнивает терм с назначениями его пакета
+func (s *partialSolution) relation(t term) relation {
+	assigned, ok := s.terms[t.pkg]
+	if !ok {
+		assigned = anyTerm(t.pkg, t.set.size)
+	}
+	return t.relationTo(assigned)
+}
+
+// satisfier возвращает самое раннее назначение, после которого терм выполнен.
+// Терм, выполненный без назначений, получает индекс -1 и нулевой уровень
+func (s *partialSolution) satisfier(t term) assignment {
+	if t.relationTo(anyTerm(t.pkg, t.set.size)) == relationSatisfied {
+		return assignment{term: t, index: -1}
+	}
+
+	var accumulated *term
+	for _, a := range s.assignments {
+		if a.term.pkg != t.pkg {
+			continue
+		}
+		if accumulated == nil {
+			accumulated = &a.term
+		} else {
+			next := accumulated.intersect(a.term)
+			accumulated = &next
+		}
+		if t.relationTo(*accumulated) == relationSatisfied {
+			return a
+		}
+	}
+	return s.assignments[len(s.assignments)-1]
+}
+
+// undecided возвращает пакеты, которые обязаны быть выбраны, но версия ещё не решена
+func (s *partialSolution) undecided() []string {
+	var packages []string
+	for _, pkg := range s.order {
+		if _, ok := s.decisions[pkg]; ok {
+			continue
+		}
+		if s.terms[pkg].positive {
+			packages = append(packages, pkg)
+		}
+	}
+	return packages
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep425"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep508"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+)
+
+// Candidate — версия пакета, которую можно установить в целевом окружении
+type Candidate struct {
+	Version pep440.Version
+	// File — дистрибутив, выбранный для установки
+	File      pypi.File
+	SdistOnly bool
+	// Yanked — все файлы версии отозваны по PEP 592, такую версию берут только по ==
+	Yanked bool
+}
+
+// Provider отдаёт версии и зависимости пакетов для одного окружения,
+// запоминая ответы индекса на время разрешения запроса
+type Provider struct {
+	index pypi.Index
+	env   pep508.Environment
+	// python == nil — версия интерпретатора не разбирается, Requires-Python не проверяется
+	python *pep440.Version
+	// compat == nil — платформа неизвестна, подходит любой файл
+	compat *pep425.Compatibility
+	// cache — ответы индекса, не зависящие от окружения
+	cache *indexCache
+
+	mu         sync.Mutex
+	candidates map[string][]Candidate
+}
+
+// indexCache запоминает файлы и metadata пакетов. Провайдеры разных окружений
+// одного запроса делят его, чтобы не спрашивать индекс повторно
+type indexCache struct {
+	mu       sync.Mutex
+	files    map[string][]pypi.File
+	metadata map[string]*pypi.Metadata
+}
+
+func NewProvider(index pypi.Index, env pep508.Environment, compat *pep425.Compatibility) *Provider {
+	return newProvider(index, env, compat, &indexCache{
+		files:    make(map[string][]pypi.File),
+		metadata: make(map[string]*pypi.Metadata),
+	})
+}
+
+func newProvider(index pypi.Index, env pep508.Environment, compat *pep425.Compatibility, cache *indexCache) *Provider {
+	provider := &Provider{
+		index:      index,
+		env:        env,
+		compat:     compat,
+		cache:      cache,
+		candidates: make(map[string][]Candidate),
+	}
+	if python, err := pep440.Parse(env.PythonFullVersion); err == nil {
+		provider.python = &python
+	}
+	return provider
+}
+
+// ForEnvironment возвращает провайдер другого окружения, который делит с p ответы индекса
+func (p *Provider) ForEnvironment(env pep508.Environment, compat *pep425.Compatibility) *Provider {
+	return newProvider(p.index, env, compat, p.cache)
+}
+
+// Environment возвращает окружение, для которого вычисляются маркеры
+func (p *Provider) Environment() pep508.Environment {
+	return p.env
+}
+
+// Candidates возвращает версии пакета от новой к старой. Версии без файлов
+// для целевой платформы или с неподходящим Requires-Python пропускаются
+func (p *Provider) Candidates(ctx context.Context, name string) ([]Candidate, error) {
+	name = pep503.Normalize(name)
+
+	p.mu.Lock()
+	cached, ok := p.candidates[name]
+	p.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	files, err := p.files(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[string][]pypi.File)
+	for _, file := range files {
+		if p.supportsPython(file.RequiresPython) {
+			byVersion[file.Version] = append(byVersion[file.Version], file)
+		}
+	}
+
+	var candidates []Candidate
+	for raw, files := range byVersion {
+		version, err := pep440.Parse(raw)
+		if err != nil {
+			continue
+		}
+		if candidate, ok := p.candidate(version, files); ok {
+			candidates = append(candidates, candidate)
+		}
+	}
+	slices.SortFunc(candidates, func(a, b Candidate) int {
+		return b.Version.Compare(a.Version)
+	})
+
+	p.mu.Lock()
+	p.candidates[name] = candidates
+	p.mu.Unlock()
+	return candidates, nil
+}
+
+func (p *Provider) files(ctx context.Context, name string) ([]pypi.File, error) {
+	p.cache.mu.Lock()
+	cached, ok := p.cache.files[name]
+	p.cache.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	files, err := p.index.Files(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	p.cache.mu.Lock()
+	p.cache.files[name] = files
+	p.cache.mu.Unlock()
+	return files, nil
+}
+
+// candidate выбирает файл версии, предпочитая неотозванные
+func (p *Provider) candidate(version pep440.Version, files []pypi.File) (Candidate, bool) {
+	available := slices.DeleteFunc(slices.Clone(files), func(file pypi.File) bool { return file.Yanked })
+	yanked := len(available) == 0
+	if yanked {
+		available = files
+	}
+
+	if p.compat == nil {
+		// Без платформы wheel всё равно предпочтительнее sdist
+		slices.SortStableFunc(available, func(a, b pypi.File) int {
+			return cmp.Compare(wheelOrder(a), wheelOrder(b))
+		})
+		return Candidate{Version: version, File: available[0], Yanked: yanked}, true
+	}
+
+	selection := pypi.SelectFiles(available, p.compat)
+	file, ok := selection.Best()
+	if !ok {
+		return Candidate{}, false
+	}
+	return Candidate{Version: version, File: file, SdistOnly: selection.SdistOnly(), Yanked: yanked}, true
+}
+
+func wheelOrder(file pypi.File) int {
+	if strings.HasSuffix(file.Filename, ".whl") {
+		return 0
+	}
+	return 1
+}
+
+func (p *Provider) supportsPython(requiresPython string) bool {
+	if requiresPython == "" || p.python == nil {
+		return true
+	}
+	specifiers, err := pep440.ParseSpecifierSet(requiresPython)
+	if err != nil {
+		// Некорректный Requires-Python pip тоже игнорирует
+		return true
+	}
+	return specifiers.ContainsPrereleases(*p.python, true)
+}
+
+// Metadata возвращает core metadata версии
+func (p *Provider) Metadata(ctx context.Context, name string, version string) (*pypi.Metadata, error) {
+	key := pep503.Normalize(name) + "==" + version
+
+	p.cache.mu.Lock()
+	cached, ok := p.cache.metadata[key]
+	p.cache.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	metadata, err := p.index.Metadata(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	p.cache.mu.Lock()
+	p.cache.metadata[key] = metadata
+	p.cache.mu.Unlock()
+	return metadata, nil
+}
+
+// Dependencies возвращает зависимости версии, нужные в окружении с запрошенными extras.
+// Некорректные строки Requires-Dist пропускаются, как это делает pip
+func (p *Provider) Dependencies(ctx context.Context, name string, version string, extras []string) ([]pep508.Requirement, error) {
+	metadata, err := p.Metadata(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	env := p.env.WithExtras(extras...)
+	var dependencies []pep508.Requirement
+	for _, raw := range metadata.RequiresDist {
+		requirement, err := pep508.ParseRequirement(raw)
+		if err != nil {
+			continue
+		}
+		if requirement.AppliesTo(env) {
+			dependencies = append(dependencies, requirement)
+		}
+	}
+	return dependencies, nil
+}
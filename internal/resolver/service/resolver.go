@@ -0,0 +1,763 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/analysis"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep503"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep508"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+)
+
+var (
+	ErrConflict        = errors.New("dependency conflict")
+	ErrTooManyPackages = errors.New("too many packages")
+)
+
+// ConflictError — согласованного набора версий нет. Explanation выводит конфликт
+// из требований по шагам, например "Because a 2.0 depends on b<3 and c 1.4 depends on b>=3, ..."
+type ConflictError struct {
+	Explanation []string
+}
+
+func (e *ConflictError) Error() string {
+	var lines []string
+	for _, line := range e.Explanation {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return fmt.Sprintf("%s: %s", ErrConflict, strings.Join(lines, " "))
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// ProgressFunc получает число выбранных пакетов и число уже обнаруженных
+type ProgressFunc func(resolved int, discovered int)
+
+// Request — входные данные разрешения
+type Request struct {
+	// Roots — зависимости, запрошенные пользователем
+	Roots []pep508.Requirement
+	// Locked — версии закреплены lock-файлом, транзитивные зависимости не разрешаются
+	Locked bool
+	// Strategy — одна из analysis.Strategy*, пустая строка — analysis.StrategyHighest
+	Strategy string
+	// Pinned — версии из предыдущего lock по нормализованному имени для analysis.StrategyPreferPinned
+	Pinned map[string]string
+	// Constraints ограничивают версии пакетов, но не добавляют их в разрешение, как pip -c
+	Constraints []pep508.Requirement
+	// Overrides заменяют условие версии во всех требованиях на пакет, включая metadata зависимостей.
+	// Из нескольких overrides пакета действует первый, чей маркер выполняется в окружении
+	Overrides []pep508.Requirement
+	Progress  ProgressFunc
+}
+
+// ResolvedPackage — выбранная версия пакета и рёбра графа к его зависимостям
+type ResolvedPackage struct {
+	Name    string
+	Version string
+	Extras  []string
+	// Dependencies — нормализованные имена прямых зависимостей пакета
+	Dependencies []string
+	Direct       bool
+	// URL задан для пакетов из прямых ссылок, их зависимости не разрешаются
+	URL       string
+	Index     string
+	File      pypi.File
+	SdistOnly bool
+
+	// Поля универсального разрешения. Marker — маркер окружений, в которые ставится пакет,
+	// пустой — во все; Dependencies тогда перечисляет рёбра, общие для всех его окружений
+	Marker                  string
+	Environments            []string
+	ConditionalDependencies []ConditionalDependency
+}
+
+// Resolution — полный транзитивный набор зависимостей, отсортированный по имени
+type Resolution struct {
+	Packages []*ResolvedPackage
+	Warnings []string
+	// Environments — окружения универсального разрешения, пусто для одного окружения
+	Environments []Environment
+	// OverrideConflicts — места, где override противоречит объявленному требованию
+	OverrideConflicts []OverrideConflict
+}
+
+// OverrideConflict — объявленное требование, которое override заменил,
+// а выбранная версия ему не удовлетворяет
+type OverrideConflict struct {
+	// Package и Version объявили требование, пустой Package — сам запрос
+	Package         string
+	Version         string
+	Requirement     string
+	Override        string
+	ResolvedVersion string
+	// Environments — окружения универсального разрешения, где возникает противоречие
+	Environments []string
+}
+
+// Resolver подбирает согласованный набор версий алгоритмом PubGrub: при конфликте он
+// запоминает несовместимость, откатывает решения и пробует другие версии, а если
+// вариантов не осталось — объясняет конфликт через цепочку требований
+type Resolver struct {
+	provider    *Provider
+	maxPackages int
+}
+
+func NewResolver(provider *Provider, maxPackages int) *Resolver {
+	return &Resolver{
+		provider:    provider,
+		maxPackages: maxPackages,
+	}
+}
+
+// solver — состояние одного разрешения
+type solver struct {
+	provider    *Provider
+	request     Request
+	maxPackages int
+
+	// candidates — версии пакетов по нормализованному имени, missing — пакеты, которых нет в индексе
+	candidates map[string][]Candidate
+	missing    map[string]bool
+	// urls — пакеты из прямых ссылок запроса, они не участвуют в подборе версий
+	urls map[string]string
+	// roots — требования запроса, применимые в окружении, direct — их нормализованные имена
+	roots  []pep508.Requirement
+	direct map[string]bool
+	pinned map[string]pep440.Version
+	// constraints и overrides — действующие в окружении constraints и overrides по имени пакета
+	constraints map[string][]pep508.Requirement
+	overrides   map[string]pep508.Requirement
+
+	incompatibilities map[string][]*incompatibility
+	// expanded — версии, зависимости которых уже добавлены
+	expanded map[string]bool
+	solution *partialSolution
+}
+
+// Resolve разрешает зависимости запроса
+func (r *Resolver) Resolve(ctx context.Context, request Request) (*Resolution, error) {
+	s := &solver{
+		provider:          r.provider,
+		request:           request,
+		maxPackages:       r.maxPackages,
+		candidates:        make(map[string][]Candidate),
+		missing:           make(map[string]bool),
+		urls:              make(map[string]string),
+		direct:            make(map[string]bool),
+		pinned:            make(map[string]pep440.Version),
+		constraints:       make(map[string][]pep508.Requirement),
+		overrides:         make(map[string]pep508.Requirement),
+		incompatibilities: make(map[string][]*incompatibility),
+		expanded:          make(map[string]bool),
+		solution:          newPartialSolution(),
+	}
+
+	env := r.provider.Environment()
+	for _, root := range request.Roots {
+		if !root.AppliesTo(env) {
+			continue
+		}
+		if root.URL != "" {
+			s.urls[pep503.Normalize(root.Name)] = root.URL
+		}
+		s.roots = append(s.roots, root)
+		s.direct[pep503.Normalize(root.Name)] = true
+	}
+	for name, raw := range request.Pinned {
+		// Непарсируемая версия из lock просто не учитывается
+		if version, err := pep440.Parse(raw); err == nil {
+			s.pinned[pep503.Normalize(name)] = version
+		}
+	}
+	for _, constraint := range request.Constraints {
+		if constraint.AppliesTo(env) {
+			name := pep503.Normalize(constraint.Name)
+			s.constraints[name] = append(s.constraints[name], constraint)
+		}
+	}
+	for _, override := range request.Overrides {
+		name := pep503.Normalize(override.Name)
+		if _, ok := s.overrides[name]; !ok && override.AppliesTo(env) {
+			s.overrides[name] = override
+		}
+	}
+
+	s.addIncompatibility(&incompatibility{
+		terms: []term{{pkg: rootPackage, set: fullSet(1)}},
+		kind:  kindRoot,
+	})
+
+	next := rootPackage
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := s.propagate(next); err != nil {
+			return nil, err
+		}
+
+		pkg, ok, err := s.decideNext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return s.resolution(ctx)
+		}
+		next = pkg
+	}
+}
+
+// extraPackage — виртуальный пакет name[extra]: его версия v требует name==v
+// и зависимости, которые включает extra
+func extraPackage(name string, extra string) string {
+	return name + "[" + extra + "]"
+}
+
+func splitPackage(pkg string) (string, string) {
+	name, extra, ok := strings.Cut(pkg, "[")
+	if !ok {
+		return pkg, ""
+	}
+	return name, strings.TrimSuffix(extra, "]")
+}
+
+func (s *solver) size(pkg string) int {
+	if pkg == rootPackage {
+		return 1
+	}
+	name, _ := splitPackage(pkg)
+	return len(s.candidates[name])
+}
+
+func (s *solver) version(pkg string, index int) string {
+	name, _ := splitPackage(pkg)
+	return s.candidates[name][index].Version.String()
+}
+
+// versions загружает версии пакета. Пакет, которого нет в индексе, получает пустой список
+func (s *solver) versions(ctx context.Context, name string) ([]Candidate, error) {
+	if candidates, ok := s.candidates[name]; ok {
+		return candidates, nil
+	}
+
+	candidates, err := s.provider.Candidates(ctx, name)
+	if errors.Is(err, pypi.ErrNotFound) {
+		s.missing[name] = true
+	} else if err != nil {
+		return nil, fmt.Errorf("package %s: %w", name, err)
+	}
+	s.candidates[name] = candidates
+
+	// Constraints известны до первого терма пакета, поэтому действуют на все его требования
+	for _, constraint := range s.constraints[name] {
+		excluded := s.matching(name, constraint.Specifier, true).complement()
+		if !excluded.isEmpty() {
+			s.addIncompatibility(constraintIncompatibility(
+				term{pkg: name, positive: true, set: excluded}, requirementText(constraint)))
+		}
+	}
+	return candidates, nil
+}
+
+// allowed возвращает версии, подходящие под условия. Отозванные версии
+// допускаются, только если условие закрепляет версию через ==
+func (s *solver) allowed(name string, specifiers pep440.SpecifierSet) versionSet {
+	return s.matching(name, specifiers, specifiers.IsPinned())
+}
+
+// matching возвращает версии, подходящие под условия, отозванные — только при yanked
+func (s *solver) matching(name string, specifiers pep440.SpecifierSet, yanked bool) versionSet {
+	candidates := s.candidates[name]
+
+	indexes := make(map[string]int, len(candidates))
+	versions := make([]pep440.Version, 0, len(candidates))
+	for i, candidate := range candidates {
+		if candidate.Yanked && !yanked {
+			continue
+		}
+		indexes[candidate.Version.String()] = i
+		versions = append(versions, candidate.Version)
+	}
+
+	set := emptySet(len(candidates))
+	for _, version := range specifiers.Filter(versions) {
+		set = set.union(singletonSet(len(candidates), indexes[version.String()]))
+	}
+	return set
+}
+
+func (s *solver) addIncompatibility(i *incompatibility) {
+	for _, t := range i.terms {
+		s.incompatibilities[t.pkg] = append(s.incompatibilities[t.pkg], i)
+	}
+}
+
+// propagate выводит следствия из несовместимостей, затронутых изменением пакета pkg
+func (s *solver) propagate(pkg string) error {
+	changed := []string{pkg}
+	for len(changed) > 0 {
+		current := changed[len(changed)-1]
+		changed = changed[:len(changed)-1]
+
+		incompatibilities := s.incompatibilities[current]
+		for i := len(incompatibilities) - 1; i >= 0; i-- {
+			state, unsatisfied := s.relation(incompatibilities[i])
+			if state == relationSatisfied {
+				cause, err := s.resolveConflict(incompatibilities[i])
+				if err != nil {
+					return err
+				}
+				if _, unsatisfied = s.relation(cause); unsatisfied == nil {
+					return fmt.Errorf("%w: learned incompatibility is not almost satisfied", ErrConflict)
+				}
+				s.solution.derive(unsatisfied.negate(), cause)
+				changed = []string{unsatisfied.pkg}
+				break
+			}
+			if state == relationInconclusive && unsatisfied != nil {
+				s.solution.derive(unsatisfied.negate(), incompatibilities[i])
+				if !slices.Contains(changed, unsatisfied.pkg) {
+					changed = append(changed, unsatisfied.pkg)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// relation сравнивает несовместимость с частичным решением. Если выполнены
+// все термы, кроме одного, возвращается relationInconclusive и этот терм
+func (s *solver) relation(i *incompatibility) (relation, *term) {
+	var unsatisfied *term
+	for j := range i.terms {
+		switch s.solution.relation(i.terms[j]) {
+		case relationContradicted:
+			return relationContradicted, nil
+		case relationInconclusive:
+			if unsatisfied != nil {
+				return relationInconclusive, nil
+			}
+			unsatisfied = &i.terms[j]
+		}
+	}
+	if unsatisfied == nil {
+		return relationSatisfied, nil
+	}
+	return relationInconclusive, unsatisfied
+}
+
+// resolveConflict выводит из нарушенной несовместимости новую, откатывает решения
+// до уровня, где она становится почти выполненной, и возвращает её
+func (s *solver) resolveConflict(conflict *incompatibility) (*incompatibility, error) {
+	learned := false
+	for !conflict.isFailure() {
+		var (
+			mostRecentTerm      *term
+			mostRecentSatisfier assignment
+			difference          *term
+		)
+		previousLevel := 1
+
+		for j := range conflict.terms {
+			t := &conflict.terms[j]
+			satisfier := s.solution.satisfier(*t)
+			if mostRecentTerm == nil || mostRecentSatisfier.index < satisfier.index {
+				if mostRecentTerm != nil {
+					previousLevel = max(previousLevel, mostRecentSatisfier.decisionLevel)
+				}
+				mostRecentTerm, mostRecentSatisfier, difference = t, satisfier, nil
+			} else {
+				previousLevel = max(previousLevel, satisfier.decisionLevel)
+			}
+
+			if mostRecentTerm == t {
+				// Удовлетворитель может покрывать больше версий, чем нужно терму:
+				// остаток должен быть выполнен более ранним назначением
+				rest := mostRecentSatisfier.term.difference(*mostRecentTerm)
+				if !rest.isEmpty() {
+					difference = &rest
+					previousLevel = max(previousLevel, s.solution.satisfier(rest.negate()).decisionLevel)
+				}
+			}
+		}
+
+		if previousLevel < mostRecentSatisfier.decisionLevel || mostRecentSatisfier.cause == nil {
+			s.solution.backtrack(previousLevel)
+			if learned {
+				s.addIncompatibility(conflict)
+			}
+			return conflict, nil
+		}
+
+		var terms []term
+		for _, t := range conflict.terms {
+			if t.pkg != mostRecentTerm.pkg {
+				terms = append(terms, t)
+			}
+		}
+		for _, t := range mostRecentSatisfier.cause.terms {
+			if t.pkg != mostRecentSatisfier.term.pkg {
+				terms = append(terms, t)
+			}
+		}
+		if difference != nil {
+			terms = append(terms, difference.negate())
+		}
+		conflict = derivedIncompatibility(terms, conflict, mostRecentSatisfier.cause)
+		learned = true
+	}
+
+	return nil, &ConflictError{Explanation: s.explain(conflict)}
+}
+
+// decideNext выбирает версию пакета с наименьшим числом вариантов. ok == false — все пакеты решены
+func (s *solver) decideNext(ctx context.Context) (string, bool, error) {
+	undecided := s.solution.undecided()
+	if len(undecided) == 0 {
+		return "", false, nil
+	}
+
+	pkg := undecided[0]
+	for _, candidate := range undecided[1:] {
+		if s.solution.terms[candidate].set.len() < s.solution.terms[pkg].set.len() {
+			pkg = candidate
+		}
+	}
+	index := s.pick(pkg, s.solution.terms[pkg].set)
+
+	incompatibilities, err := s.dependencyIncompatibilities(ctx, pkg, index)
+	if err != nil {
+		return "", false, err
+	}
+
+	conflict := false
+	for _, i := range incompatibilities {
+		s.addIncompatibility(i)
+		satisfied := true
+		for _, t := range i.terms {
+			if t.pkg != pkg && s.solution.relation(t) != relationSatisfied {
+				satisfied = false
+			}
+		}
+		conflict = conflict || satisfied
+	}
+	if conflict {
+		return pkg, true, nil
+	}
+
+	s.solution.decide(pkg, index, s.size(pkg))
+	resolved := s.resolvedCount()
+	if resolved > s.maxPackages {
+		return "", false, fmt.Errorf("%w: more than %d packages in the dependency tree", ErrTooManyPackages, s.maxPackages)
+	}
+	if s.request.Progress != nil {
+		s.request.Progress(resolved, len(s.candidates))
+	}
+	return pkg, true, nil
+}
+
+// pick выбирает версию из допустимых по стратегии запроса. Множество не пусто:
+// пустой положительный терм распространение уже признало бы конфликтом
+func (s *solver) pick(pkg string, set versionSet) int {
+	name, _ := splitPackage(pkg)
+	highest, _ := set.first()
+	lowest, _ := set.last()
+
+	switch s.request.Strategy {
+	case analysis.StrategyLowest:
+		return lowest
+	case analysis.StrategyLowestDirect:
+		if s.direct[name] {
+			return lowest
+		}
+	case analysis.StrategyPreferPinned:
+		if version, ok := s.pinned[name]; ok {
+			for i, candidate := range s.candidates[name] {
+				if set.contains(i) && candidate.Version.Equal(version) {
+					return i
+				}
+			}
+		}
+	}
+	return highest
+}
+
+func (s *solver) resolvedCount() int {
+	count := len(s.urls)
+	for pkg := range s.solution.decisions {
+		if pkg != rootPackage && !strings.Contains(pkg, "[") {
+			count++
+		}
+	}
+	return count
+}
+
+// dependencyIncompatibilities строит несовместимости "версия требует зависимость".
+// Для каждой версии они строятся один раз, после отката повторно не добавляются
+func (s *solver) dependencyIncompatibilities(ctx context.Context, pkg string, index int) ([]*incompatibility, error) {
+	key := fmt.Sprintf("%s@%d", pkg, index)
+	if s.expanded[key] {
+		return nil, nil
+	}
+	s.expanded[key] = true
+
+	parent := term{pkg: pkg, positive: true, set: singletonSet(s.size(pkg), index)}
+	var version string
+	var requirements []pep508.Requirement
+	var incompatibilities []*incompatibility
+
+	switch name, extra := splitPackage(pkg); {
+	case pkg == rootPackage:
+		requirements = s.roots
+
+	case extra != "":
+		version = s.version(pkg, index)
+		base := term{pkg: name, positive: true, set: singletonSet(s.size(name), index)}
+		incompatibilities = append(incompatibilities, dependencyIncompatibility(parent, version, base, name+"=="+version))
+		if !s.request.Locked {
+			dependencies, err := s.provider.Dependencies(ctx, name, version, []string{extra})
+			if err != nil {
+				return nil, fmt.Errorf("dependencies of %s %s: %w", name, version, err)
+			}
+			// Зависимости без extra уже требует сам пакет
+			env := s.provider.Environment()
+			for _, dependency := range dependencies {
+				if !dependency.AppliesTo(env) {
+					requirements = append(requirements, dependency)
+				}
+			}
+		}
+
+	default:
+		version = s.version(pkg, index)
+		if !s.request.Locked {
+			dependencies, err := s.provider.Dependencies(ctx, name, version, nil)
+			if err != nil {
+				return nil, fmt.Errorf("dependencies of %s %s: %w", name, version, err)
+			}
+			requirements = dependencies
+		}
+	}
+
+	for _, declared := range requirements {
+		name := pep503.Normalize(declared.Name)
+		if _, ok := s.urls[name]; ok {
+			continue
+		}
+		if _, err := s.versions(ctx, name); err != nil {
+			return nil, err
+		}
+
+		requirement, overridden := s.override(declared)
+		text := requirementText(requirement)
+		if original := requirementText(declared); overridden && text != original {
+			text += " (overriding " + original + ")"
+		}
+		set := s.allowed(name, requirement.Specifier)
+		switch {
+		case s.missing[name]:
+			incompatibilities = append(incompatibilities,
+				unavailableIncompatibility(parent, version, text, "which is not in the package index"))
+			continue
+		case set.isEmpty():
+			incompatibilities = append(incompatibilities,
+				unavailableIncompatibility(parent, version, text, "for which no version is available"))
+			continue
+		}
+
+		dependency := term{pkg: name, positive: true, set: set}
+		incompatibilities = append(incompatibilities, dependencyIncompatibility(parent, version, dependency, text))
+		for _, extra := range requirement.Extras {
+			dependency.pkg = extraPackage(name, pep503.Normalize(extra))
+			incompatibilities = append(incompatibilities, dependencyIncompatibility(parent, version, dependency, text))
+		}
+	}
+	return incompatibilities, nil
+}
+
+// override подставляет в требование условие версии из override пакета.
+// ok == false — override для пакета нет, требование возвращается как есть
+func (s *solver) override(requirement pep508.Requirement) (pep508.Requirement, bool) {
+	override, ok := s.overrides[pep503.Normalize(requirement.Name)]
+	if !ok || requirement.URL != "" {
+		return requirement, false
+	}
+	requirement.Specifier = override.Specifier
+	return requirement, true
+}
+
+// overrideConflict проверяет, противоречит ли выбранная версия объявленному требованию,
+// которое заменил override. Pre-релизы сверяются только с границами требования
+func (s *solver) overrideConflict(parent string, version string, declared pep508.Requirement, packages map[string]*ResolvedPackage) (OverrideConflict, bool) {
+	name := pep503.Normalize(declared.Name)
+	override, overridden := s.override(declared)
+	resolved := packages[name]
+	if !overridden || resolved == nil || resolved.URL != "" {
+		return OverrideConflict{}, false
+	}
+	resolvedVersion, err := pep440.Parse(resolved.Version)
+	if err != nil || declared.Specifier.ContainsPrereleases(resolvedVersion, true) {
+		return OverrideConflict{}, false
+	}
+	return OverrideConflict{
+		Package:         parent,
+		Version:         version,
+		Requirement:     requirementText(declared),
+		Override:        requirementText(override),
+		ResolvedVersion: resolved.Version,
+	}, true
+}
+
+// requirementText — требование без маркера, как его показывают в объяснении конфликта
+func requirementText(requirement pep508.Requirement) string {
+	requirement.Name = pep503.Normalize(requirement.Name)
+	requirement.Marker = pep508.Marker{}
+	return requirement.String()
+}
+
+// describe записывает множество версий терма через границы известных версий.
+// Отозванные версии и pre-релизы вне множества не разрывают диапазон
+func (s *solver) describe(t term) string {
+	name, _ := splitPackage(t.pkg)
+	candidates := s.candidates[name]
+
+	var visible []int
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if t.set.contains(i) || !(candidates[i].Yanked || candidates[i].Version.IsPrerelease()) {
+			visible = append(visible, i)
+		}
+	}
+
+	var parts []string
+	for start := 0; start < len(visible); start++ {
+		if !t.set.contains(visible[start]) {
+			continue
+		}
+		end := start
+		for end+1 < len(visible) && t.set.contains(visible[end+1]) {
+			end++
+		}
+
+		lower := candidates[visible[start]].Version.String()
+		upper := candidates[visible[end]].Version.String()
+		switch {
+		case start == 0 && end == len(visible)-1:
+			return t.pkg
+		case start == end && start > 0 && end < len(visible)-1:
+			parts = append(parts, "=="+lower)
+		case start == 0:
+			parts = append(parts, "<="+upper)
+		case end == len(visible)-1:
+			parts = append(parts, ">="+lower)
+		default:
+			parts = append(parts, ">="+lower+",<="+upper)
+		}
+		start = end
+	}
+
+	if len(parts) == 1 && strings.HasPrefix(parts[0], "==") {
+		return t.pkg + " " + strings.TrimPrefix(parts[0], "==")
+	}
+	return t.pkg + strings.Join(parts, " or ")
+}
+
+// resolution собирает граф из решённых версий
+func (s *solver) resolution(ctx context.Context) (*Resolution, error) {
+	packages := make(map[string]*ResolvedPackage)
+	extras := make(map[string][]string)
+	for pkg, index := range s.solution.decisions {
+		name, extra := splitPackage(pkg)
+		switch {
+		case pkg == rootPackage:
+		case extra != "":
+			extras[name] = append(extras[name], extra)
+		default:
+			candidate := s.candidates[name][index]
+			packages[name] = &ResolvedPackage{
+				Name:      name,
+				Version:   candidate.Version.String(),
+				Index:     candidate.File.Index,
+				File:      candidate.File,
+				SdistOnly: candidate.SdistOnly,
+			}
+		}
+	}
+
+	resolution := &Resolution{}
+	for name, url := range s.urls {
+		packages[name] = &ResolvedPackage{Name: name, URL: url}
+		resolution.Warnings = append(resolution.Warnings, fmt.Sprintf(
+			"%s is installed from %s, its dependencies are not resolved", name, url))
+	}
+	slices.Sort(resolution.Warnings)
+
+	for _, root := range s.roots {
+		if conflict, ok := s.overrideConflict(rootPackage, "", root, packages); ok {
+			resolution.OverrideConflicts = append(resolution.OverrideConflicts, conflict)
+		}
+		pkg := packages[pep503.Normalize(root.Name)]
+		pkg.Direct = true
+		if pkg.URL != "" {
+			for _, extra := range root.Extras {
+				extras[pkg.Name] = append(extras[pkg.Name], pep503.Normalize(extra))
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(packages)) {
+		pkg := packages[name]
+		pkg.Extras = slices.Compact(slices.Sorted(slices.Values(extras[name])))
+
+		if pinned, ok := s.pinned[name]; ok && s.request.Strategy == analysis.StrategyPreferPinned && pkg.URL == "" {
+			if version, err := pep440.Parse(pkg.Version); err == nil && !version.Equal(pinned) {
+				resolution.Warnings = append(resolution.Warnings, fmt.Sprintf(
+					"%s %s from the previous lock was changed to %s to satisfy the requirements", name, pinned, pkg.Version))
+			}
+		}
+
+		if pkg.URL == "" && !s.request.Locked {
+			dependencies, err := s.provider.Dependencies(ctx, name, pkg.Version, pkg.Extras)
+			if err != nil {
+				return nil, fmt.Errorf("dependencies of %s %s: %w", name, pkg.Version, err)
+			}
+			edges := make(map[string]bool)
+			for _, dependency := range dependencies {
+				if conflict, ok := s.overrideConflict(name, pkg.Version, dependency, packages); ok {
+					resolution.OverrideConflicts = append(resolution.OverrideConflicts, conflict)
+				}
+				if dependency := pep503.Normalize(dependency.Name); packages[dependency] != nil {
+					edges[dependency] = true
+				}
+			}
+			pkg.Dependencies = slices.Sorted(maps.Keys(edges))
+		}
+		resolution.Packages = append(resolution.Packages, pkg)
+	}
+	return resolution, nil
+}
+
+// String возвращает пакет в виде name==version или name @ url
+func (p *ResolvedPackage) String() string {
+	var b strings.Builder
+	b.WriteString(p.Name)
+	if len(p.Extras) > 0 {
+		b.WriteString("[" + strings.Join(p.Extras, ",") + "]")
+	}
+	if p.URL != "" {
+		b.WriteString(" @ " + p.URL)
+	} else {
+		b.WriteString("==" + p.Version)
+	}
+	return b.String()
+}
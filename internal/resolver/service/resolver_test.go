@@ -0,0 +1,314 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/analysis"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep508"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pypi"
+	"github.com/0hJonny/python-deps-crawler/internal/resolver/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSnapshotResolver строит resolver над снимком: packages[name][version] — Requires-Dist версии
+func newSnapshotResolver(t *testing.T, packages map[string]map[string][]string, maxPackages int) *service.Resolver {
+	snapshot := pypi.NewSnapshot()
+	for name, releases := range packages {
+		for version, requiresDist := range releases {
+			snapshot.Add(
+				&pypi.Metadata{Name: name, Version: version, RequiresDist: requiresDist},
+				pypi.File{Filename: name + "-" + version + ".tar.gz", Version: version},
+			)
+		}
+	}
+	provider := service.NewProvider(snapshot, pep508.NewEnvironment("3.12"), nil)
+	return service.NewResolver(provider, maxPackages)
+}
+
+func parseRoots(t *testing.T, requirements ...string) []pep508.Requirement {
+	roots := make([]pep508.Requirement, len(requirements))
+	for i, requirement := range requirements {
+		root, err := pep508.ParseRequirement(requirement)
+		require.NoError(t, err)
+		roots[i] = root
+	}
+	return roots
+}
+
+func resolvedVersions(resolution *service.Resolution) map[string]string {
+	versions := make(map[string]string, len(resolution.Packages))
+	for _, pkg := range resolution.Packages {
+		versions[pkg.Name] = pkg.Version
+	}
+	return versions
+}
+
+func TestResolver_Backtracks(t *testing.T) {
+	tests := []struct {
+		name     string
+		packages map[string]map[string][]string
+		roots    []string
+		expected map[string]string
+	}{
+		{
+			name: "older version of a direct dependency",
+			packages: map[string]map[string][]string{
+				"a": {"1.0": {"b"}, "2.0": {"b<3"}},
+				"b": {"2.0": nil, "3.0": nil},
+				"c": {"1.4": {"b>=3"}},
+			},
+			roots:    []string{"a", "c"},
+			expected: map[string]string{"a": "1.0", "b": "3.0", "c": "1.4"},
+		},
+		{
+			name: "conflict discovered deep in the tree",
+			packages: map[string]map[string][]string{
+				"foo":   {"1.0.0": {"bar==1.0.0"}, "2.0.0": {"bar==2.0.0"}},
+				"bar":   {"1.0.0": nil, "2.0.0": {"baz==1.0.0"}},
+				"baz":   {"1.0.0": {"qux==2.0.0"}},
+				"qux":   {"1.0.0": nil},
+				"other": {"1.0.0": {"bar"}},
+			},
+			roots:    []string{"foo", "other"},
+			expected: map[string]string{"foo": "1.0.0", "bar": "1.0.0", "other": "1.0.0"},
+		},
+		{
+			name: "extra forces an older version",
+			packages: map[string]map[string][]string{
+				"x": {
+					"1.0": {"y<2; extra == 'fast'"},
+					"2.0": {"y>=2; extra == 'fast'"},
+				},
+				"y": {"1.0": nil, "2.0": nil},
+			},
+			roots:    []string{"x[fast]", "y<2"},
+			expected: map[string]string{"x": "1.0", "y": "1.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := newSnapshotResolver(t, tt.packages, 100)
+
+			resolution, err := resolver.Resolve(t.Context(), service.Request{Roots: parseRoots(t, tt.roots...)})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resolvedVersions(resolution))
+		})
+	}
+}
+
+func TestResolver_Strategies(t *testing.T) {
+	packages := map[string]map[string][]string{
+		"a": {"1.0": {"b>=1"}, "1.5": {"b>=2"}, "2.0": {"b>=2"}},
+		"b": {"1.0": nil, "2.0": nil, "3.0": nil},
+	}
+
+	tests := []struct {
+		strategy string
+		roots    []string
+		pinned   map[string]string
+		expected map[string]string
+		warnings []string
+	}{
+		{strategy: "", roots: []string{"a"}, expected: map[string]string{"a": "2.0", "b": "3.0"}},
+		{strategy: analysis.StrategyHighest, roots: []string{"a<2"}, expected: map[string]string{"a": "1.5", "b": "3.0"}},
+		{strategy: analysis.StrategyLowest, roots: []string{"a"}, expected: map[string]string{"a": "1.0", "b": "1.0"}},
+		{strategy: analysis.StrategyLowest, roots: []string{"a>=1.5"}, expected: map[string]string{"a": "1.5", "b": "2.0"}},
+		{strategy: analysis.StrategyLowestDirect, roots: []string{"a"}, expected: map[string]string{"a": "1.0", "b": "3.0"}},
+		{
+			strategy: analysis.StrategyPreferPinned,
+			roots:    []string{"a"},
+			pinned:   map[string]string{"a": "1.5", "B": "2.0"},
+			expected: map[string]string{"a": "1.5", "b": "2.0"},
+		},
+		{
+			strategy: analysis.StrategyPreferPinned,
+			roots:    []string{"a>=1.5"},
+			pinned:   map[string]string{"a": "1.0", "b": "1.0"},
+			expected: map[string]string{"a": "2.0", "b": "3.0"},
+			warnings: []string{
+				"a 1.0 from the previous lock was changed to 2.0 to satisfy the requirements",
+				"b 1.0 from the previous lock was changed to 3.0 to satisfy the requirements",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy+" "+tt.roots[0], func(t *testing.T) {
+			resolver := newSnapshotResolver(t, packages, 100)
+
+			resolution, err := resolver.Resolve(t.Context(), service.Request{
+				Roots:    parseRoots(t, tt.roots...),
+				Strategy: tt.strategy,
+				Pinned:   tt.pinned,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resolvedVersions(resolution))
+			assert.Equal(t, tt.warnings, resolution.Warnings)
+		})
+	}
+}
+
+func TestResolver_ExtrasAndGraph(t *testing.T) {
+	resolver := newSnapshotResolver(t, map[string]map[string][]string{
+		"x": {"1.0": {"y", "z>=1; extra == 'fast'", "w; python_version < '3.8'"}},
+		"y": {"1.0": nil},
+		"z": {"1.0": nil},
+		"w": {"1.0": nil},
+	}, 100)
+
+	resolution, err := resolver.Resolve(t.Context(), service.Request{Roots: parseRoots(t, "x[fast]")})
+	require.NoError(t, err)
+
+	require.Len(t, resolution.Packages, 3)
+	x := resolution.Packages[0]
+	assert.Equal(t, "x[fast]==1.0", x.String())
+	assert.True(t, x.Direct)
+	assert.Equal(t, []string{"y", "z"}, x.Dependencies)
+	assert.False(t, resolution.Packages[1].Direct)
+}
+
+func TestResolver_ExplainsConflict(t *testing.T) {
+	tests := []struct {
+		name        string
+		packages    map[string]map[string][]string
+		roots       []string
+		explanation []string
+	}{
+		{
+			name: "two dependents disagree",
+			packages: map[string]map[string][]string{
+				"a": {"2.0": {"b<3"}},
+				"b": {"2.0": nil, "3.0": nil},
+				"c": {"1.4": {"b>=3"}},
+			},
+			roots: []string{"a==2.0", "c==1.4"},
+			explanation: []string{
+				"Because c 1.4 depends on b>=3 and a 2.0 depends on b<3, c and a are incompatible.",
+				"And because the request requires a==2.0 and the request requires c==1.4, the requirements are unsatisfiable.",
+			},
+		},
+		{
+			name: "every version of a dependency conflicts",
+			packages: map[string]map[string][]string{
+				"a": {"1.0": {"b<2"}, "2.0": {"b<3"}},
+				"b": {"1.0": nil, "2.0": nil, "3.0": nil},
+				"c": {"1.4": {"b>=3"}},
+			},
+			roots: []string{"a", "c"},
+			explanation: []string{
+				"Because a 2.0 depends on b<3 and a 1.0 depends on b<2, a depends on b<=2.0.",
+				"And because c 1.4 depends on b>=3, c and a are incompatible.",
+				"And because the request requires a and the request requires c, the requirements are unsatisfiable.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := newSnapshotResolver(t, tt.packages, 100)
+
+			_, err := resolver.Resolve(t.Context(), service.Request{Roots: parseRoots(t, tt.roots...)})
+			require.ErrorIs(t, err, service.ErrConflict)
+
+			var conflict *service.ConflictError
+			require.ErrorAs(t, err, &conflict)
+			assert.Equal(t, tt.explanation, conflict.Explanation)
+		})
+	}
+}
+
+func TestResolver_TooManyPackages(t *testing.T) {
+	resolver := newSnapshotResolver(t, map[string]map[string][]string{
+		"a": {"1.0": {"b"}},
+		"b": {"1.0": {"c"}},
+		"c": {"1.0": nil},
+	}, 2)
+
+	_, err := resolver.Resolve(t.Context(), service.Request{Roots: parseRoots(t, "a")})
+	assert.ErrorIs(t, err, service.ErrTooManyPackages)
+}
+
+func TestResolver_ConstraintsAndOverrides(t *testing.T) {
+	packages := map[string]map[string][]string{
+		"a": {"1.0": {"b<2"}, "2.0": {"b>=3"}},
+		"b": {"1.0": nil, "2.0": nil, "3.0": nil},
+		"c": {"1.0": {"b"}},
+	}
+
+	tests := []struct {
+		name        string
+		roots       []string
+		constraints []string
+		overrides   []string
+		expected    map[string]string
+		conflicts   []service.OverrideConflict
+	}{
+		{
+			name:        "constraint limits a transitive dependency",
+			roots:       []string{"c"},
+			constraints: []string{"b<3", "d<1", "b>=3; python_version < '3.8'"},
+			expected:    map[string]string{"c": "1.0", "b": "2.0"},
+		},
+		{
+			name:        "constraint forces an older dependent",
+			roots:       []string{"a"},
+			constraints: []string{"b<3"},
+			expected:    map[string]string{"a": "1.0", "b": "1.0"},
+		},
+		{
+			name:      "override replaces declared requirements",
+			roots:     []string{"a==1.0", "c"},
+			overrides: []string{"b>=2"},
+			expected:  map[string]string{"a": "1.0", "b": "3.0", "c": "1.0"},
+			conflicts: []service.OverrideConflict{
+				{Package: "a", Version: "1.0", Requirement: "b<2", Override: "b>=2", ResolvedVersion: "3.0"},
+			},
+		},
+		{
+			name:      "override of a direct requirement",
+			roots:     []string{"b<2"},
+			overrides: []string{"b==2.0; python_version >= '3.8'", "b==3.0"},
+			expected:  map[string]string{"b": "2.0"},
+			conflicts: []service.OverrideConflict{
+				{Requirement: "b<2", Override: "b==2.0", ResolvedVersion: "2.0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := newSnapshotResolver(t, packages, 100)
+
+			resolution, err := resolver.Resolve(t.Context(), service.Request{
+				Roots:       parseRoots(t, tt.roots...),
+				Constraints: parseRoots(t, tt.constraints...),
+				Overrides:   parseRoots(t, tt.overrides...),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resolvedVersions(resolution))
+			assert.Equal(t, tt.conflicts, resolution.OverrideConflicts)
+		})
+	}
+}
+
+func TestResolver_ExplainsConstraintConflict(t *testing.T) {
+	resolver := newSnapshotResolver(t, map[string]map[string][]string{
+		"a": {"2.0": {"b>=3"}},
+		"b": {"2.0": nil, "3.0": nil},
+	}, 100)
+
+	_, err := resolver.Resolve(t.Context(), service.Request{
+		Roots:       parseRoots(t, "a==2.0"),
+		Constraints: parseRoots(t, "b<3"),
+		Overrides:   parseRoots(t, "b>=2.5"),
+	})
+
+	var conflict *service.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, []string{
+		"Because a 2.0 depends on b>=2.5 (overriding b>=3) and the constraints require b<3, a cannot be used.",
+		"And because the request requires a==2.0, the requirements are unsatisfiable.",
+	}, conflict.Explanation)
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep425"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep440"
+	"github.com/0hJonny/python-deps-crawler/internal/pkg/pep508"
+	eventspb "github.com/0hJonny/python-deps-crawler/pkg/proto/api_gateway_kafka_events"
+)
+
+// Environment — одно окружение универсального разрешения
+type Environment struct {
+	// ID — устойчивый идентификатор вида cp312-linux-x86_64
+	ID string
+	// Platform — нормализованная платформа вместе с версией Python
+	Platform pep425.Platform
+	// Marker — маркер PEP 508, который выполняется в этом окружении
+	Marker string
+
+	markers pep508.Environment
+	compat  *pep425.Compatibility
+}
+
+// ConditionalDependency — ребро графа, которое есть только в части окружений пакета
+type ConditionalDependency struct {
+	Name         string
+	Marker       string
+	Environments []string
+}
+
+// universe — окружения универсального разрешения: каждая версия Python на каждой платформе.
+// Окружения упорядочены по возрастанию версии Python, внутри версии — по платформам запроса
+type universe struct {
+	environments []Environment
+	pythons      []string
+	platforms    []pep508.Environment
+}
+
+// newUniverse перечисляет окружения события
+func newUniverse(universal *eventspb.UniversalResolution) (*universe, error) {
+	if len(universal.PythonVersions) == 0 || len(universal.Platforms) == 0 {
+		return nil, fmt.Errorf("%w: universal resolution needs python versions and platforms", ErrInvalidRequest)
+	}
+
+	pythons := slices.Clone(universal.PythonVersions)
+	for _, python := range pythons {
+		if _, err := pep440.Parse(python); err != nil {
+			return nil, fmt.Errorf("%w: python version %q: %w", ErrInvalidRequest, python, err)
+		}
+	}
+	slices.SortFunc(pythons, func(a, b string) int {
+		return pep440.MustParse(a).Compare(pep440.MustParse(b))
+	})
+
+	u := &universe{}
+	for _, python := range pythons {
+		for _, target := range universal.Platforms {
+			compat, err := pep425.NewCompatibility(pep425.Platform{
+				PythonVersion: python,
+				OS:            target.Os,
+				Arch:          target.Arch,
+				Libc:          target.Libc,
+				LibcVersion:   target.LibcVersion,
+				MacOSVersion:  target.MacosVersion,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+			}
+			markers, err := compat.Platform().MarkerEnvironment()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+			}
+			u.environments = append(u.environments, newEnvironment(compat, markers))
+		}
+		u.pythons = append(u.pythons, pep508.NewEnvironment(python).PythonVersion)
+	}
+	for _, environment := range u.environments[:len(universal.Platforms)] {
+		u.platforms = append(u.platforms, environment.markers)
+	}
+	return u, nil
+}
+
+func newEnvironment(compat *pep425.Compatibility, markers pep508.Environment) Environment {
+	platform := compat.Platform()
+	os := platform.OS
+	if platform.Libc == pep425.LibcMusl {
+		os += "-musl"
+	}
+	return Environment{
+		ID:       fmt.Sprintf("cp%s-%s-%s", strings.ReplaceAll(markers.PythonVersion, ".", ""), os, platform.Arch),
+		Platform: platform,
+		Marker: joinMarkers(" and ",
+			fmt.Sprintf("python_version == %q", markers.PythonVersion),
+			fmt.Sprintf("sys_platform == %q", markers.SysPlatform),
+			fmt.Sprintf("platform_machine == %q", markers.PlatformMachine),
+		),
+		markers: markers,
+		compat:  compat,
+	}
+}
+
+// resolveUniversal разрешает запрос в каждом окружении отдельно и сводит результаты
+// в один разветвлённый lock, где у каждого пакета указаны окружения, в которые он ставится
+func (s *AnalysisService) resolveUniversal(ctx context.Context, event *eventspb.AnalysisStartedEvent, request Request) (*Resolution, error) {
+	u, err := newUniverse(event.Universal)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := s.progressReporter(ctx, event.RequestId, len(u.environments))
+	resolutions := make([]*Resolution, len(u.environments))
+	var provider *Provider
+	for i, environment := range u.environments {
+		if provider == nil {
+			provider = NewProvider(s.index, environment.markers, environment.compat)
+		} else {
+			provider = provider.ForEnvironment(environment.markers, environment.compat)
+		}
+
+		request.Progress = progress(i, environment.ID)
+		resolution, err := NewResolver(provider, s.maxPackages).Resolve(ctx, request)
+		if err != nil {
+			return nil, environmentError(environment, err)
+		}
+		resolutions[i] = resolution
+	}
+	return u.merge(resolutions), nil
+}
+
+// environmentError указывает в ошибке окружение, в котором разрешение не удалось
+func environmentError(environment Environment, err error) error {
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		return fmt.Errorf("environment %s: %w", environment.ID, err)
+	}
+	explanation := append([]string{fmt.Sprintf("In environment %s (%s):", environment.ID, environment.Marker)},
+		conflict.Explanation...)
+	return &ConflictError{Explanation: explanation}
+}
+
+// merge сводит разрешения окружений. Пакет с одной версией и одним файлом во всех
+// окружениях становится одной записью, отличающиеся файлы (wheel под платформу) — отдельными
+func (u *universe) merge(resolutions []*Resolution) *Resolution {
+	type entry struct {
+		pkg     *ResolvedPackage
+		present []bool
+		edges   map[string][]bool
+	}
+	entries := make(map[string]*entry)
+	var keys []string
+	warnings := make(map[string]bool)
+	type conflictEntry struct {
+		conflict OverrideConflict
+		present  []bool
+	}
+	conflicts := make(map[string]*conflictEntry)
+	var conflictKeys []string
+
+	for i, resolution := range resolutions {
+		for _, pkg := range resolution.Packages {
+			key := pkg.Name + "==" + pkg.Version + " " + pkg.URL + pkg.File.Filename
+			e, ok := entries[key]
+			if !ok {
+				merged := *pkg
+				merged.Dependencies = nil
+				e = &entry{pkg: &merged, present: make([]bool, len(u.environments)), edges: make(map[string][]bool)}
+				entries[key] = e
+				keys = append(keys, key)
+			}
+			e.present[i] = true
+			e.pkg.Direct = e.pkg.Direct || pkg.Direct
+			// Extras объединяются: запись описывает версию, а не набор extras окружения
+			e.pkg.Extras = slices.Compact(slices.Sorted(slices.Values(append(slices.Clone(e.pkg.Extras), pkg.Extras...))))
+			for _, dependency := range pkg.Dependencies {
+				if e.edges[dependency] == nil {
+					e.edges[dependency] = make([]bool, len(u.environments))
+				}
+				e.edges[dependency][i] = true
+			}
+		}
+		for _, warning := range resolution.Warnings {
+			warnings[warning] = true
+		}
+		for _, conflict := range resolution.OverrideConflicts {
+			key := strings.Join([]string{conflict.Package, conflict.Version, conflict.Requirement,
+				conflict.Override, conflict.ResolvedVersion}, "\x00")
+			if conflicts[key] == nil {
+				conflicts[key] = &conflictEntry{conflict: conflict, present: make([]bool, len(u.environments))}
+				conflictKeys = append(conflictKeys, key)
+			}
+			conflicts[key].present[i] = true
+		}
+	}
+
+	merged := &Resolution{
+		Warnings:     slices.Sorted(maps.Keys(warnings)),
+		Environments: u.environments,
+	}
+	for _, key := range conflictKeys {
+		conflict := conflicts[key].conflict
+		conflict.Environments = u.ids(conflicts[key].present)
+		merged.OverrideConflicts = append(merged.OverrideConflicts, conflict)
+	}
+	for _, key := range keys {
+		e := entries[key]
+		pkg := e.pkg
+		pkg.Environments = u.ids(e.present)
+		pkg.Marker = u.marker(e.present)
+		for _, dependency := range slices.Sorted(maps.Keys(e.edges)) {
+			if slices.Equal(e.edges[dependency], e.present) {
+				pkg.Dependencies = append(pkg.Dependencies, dependency)
+				continue
+			}
+			pkg.ConditionalDependencies = append(pkg.ConditionalDependencies, ConditionalDependency{
+				Name:         dependency,
+				Marker:       u.marker(e.edges[dependency]),
+				Environments: u.ids(e.edges[dependency]),
+			})
+		}
+		merged.Packages = append(merged.Packages, pkg)
+	}
+
+	// Записи одного пакета идут в порядке первого окружения, где они ставятся
+	first := func(pkg *ResolvedPackage) int {
+		return slices.IndexFunc(u.environments, func(environment Environment) bool {
+			return environment.ID == pkg.Environments[0]
+		})
+	}
+	slices.SortStableFunc(merged.Packages, func(a, b *ResolvedPackage) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(first(a), first(b)))
+	})
+	return merged
+}
+
+func (u *universe) ids(selected []bool) []string {
+	var ids []string
+	for i, environment := range u.environments {
+		if selected[i] {
+			ids = append(ids, environment.ID)
+		}
+	}
+	return ids
+}
+
+// marker описывает подмножество окружений маркером PEP 508, пустая строка — все окружения.
+// Платформы с одинаковым набором версий Python описываются одним условием. Платформы,
+// которые различаются только libc, маркеры не различают: точный состав дают идентификаторы окружений
+func (u *universe) marker(selected []bool) string {
+	if !slices.Contains(selected, false) {
+		return ""
+	}
+
+	// pythons[q] — версии Python, в которых выбрана платформа q
+	var groups [][]int
+	groupPythons := make(map[string]int)
+	for q := range u.platforms {
+		pythons := make([]bool, len(u.pythons))
+		key := ""
+		for p := range u.pythons {
+			pythons[p] = selected[p*len(u.platforms)+q]
+			key += fmt.Sprint(pythons[p])
+		}
+		if !slices.Contains(pythons, true) {
+			continue
+		}
+		if g, ok := groupPythons[key]; ok {
+			groups[g] = append(groups[g], q)
+			continue
+		}
+		groupPythons[key] = len(groups)
+		groups = append(groups, []int{q})
+	}
+
+	clauses := make([]string, len(groups))
+	for i, platforms := range groups {
+		pythons := make([]bool, len(u.pythons))
+		for p := range u.pythons {
+			pythons[p] = selected[p*len(u.platforms)+platforms[0]]
+		}
+		clauses[i] = joinMarkers(" and ", u.pythonMarker(pythons), u.platformMarker(platforms))
+	}
+	if len(clauses) == 1 {
+		return clauses[0]
+	}
+	return joinMarkers(" or ", clauses...)
+}
+
+// pythonMarker описывает версии Python диапазонами соседних версий запроса
+func (u *universe) pythonMarker(selected []bool) string {
+	if !slices.Contains(selected, false) {
+		return ""
+	}
+
+	var ranges []string
+	for start := 0; start < len(selected); start++ {
+		if !selected[start] {
+			continue
+		}
+		end := start
+		for end+1 < len(selected) && selected[end+1] {
+			end++
+		}
+		switch {
+		case start == end:
+			ranges = append(ranges, fmt.Sprintf("python_version == %q", u.pythons[start]))
+		case start == 0:
+			ranges = append(ranges, fmt.Sprintf("python_version < %q", u.pythons[end+1]))
+		case end == len(selected)-1:
+			ranges = append(ranges, fmt.Sprintf("python_version >= %q", u.pythons[start]))
+		default:
+			ranges = append(ranges, fmt.Sprintf("python_version >= %q and python_version < %q",
+				u.pythons[start], u.pythons[end+1]))
+		}
+		start = end
+	}
+	return joinMarkers(" or ", ranges...)
+}
+
+// platformMarker описывает платформы через sys_platform, а если выбраны не все
+// архитектуры системы — добавляет platform_machine
+func (u *universe) platformMarker(selected []int) string {
+	if len(selected) == len(u.platforms) {
+		return ""
+	}
+
+	var markers []string
+	for _, q := range selected {
+		platform := u.platforms[q]
+		system := fmt.Sprintf("sys_platform == %q", platform.SysPlatform)
+		whole := true
+		for other, candidate := range u.platforms {
+			if candidate.SysPlatform == platform.SysPlatform && !slices.Contains(selected, other) {
+				whole = false
+			}
+		}
+		marker := system
+		if !whole {
+			marker = joinMarkers(" and ", system, fmt.Sprintf("platform_machine == %q", platform.PlatformMachine))
+		}
+		if !slices.Contains(markers, marker) {
+			markers = append(markers, marker)
+		}
+	}
+	return joinMarkers(" or ", markers...)
+}
+
+// joinMarkers соединяет непустые маркеры. Дизъюнкции заключаются в скобки,
+// а конъюнкции внутри " or " — для читаемости, хотя and и так связывает сильнее
+func joinMarkers(separator string, markers ...string) string {
+	var parts []string
+	for _, marker := range markers {
+		if marker != "" {
+			parts = append(parts, marker)
+		}
+	}
+	if len(parts) > 1 {
+		for i, part := range parts {
+			if strings.Contains(part, " or ") || separator == " or " && strings.Contains(part, " and ") {
+				parts[i] = "(" + part + ")"
+			}
+		}
+	}
+	return strings.Join(parts, separator)
+}
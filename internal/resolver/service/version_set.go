@@ -0,0 +1,163 @@
+package service
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// versionSet — подмножество известных версий пакета. Бит i соответствует i-му
+// кандидату провайдера, кандидаты упорядочены от новой версии к старой
+type versionSet struct {
+	words []uint64
+	size  int
+}
+
+func emptySet(size int) versionSet {
+	return versionSet{words: make([]uint64, (size+63)/64), size: size}
+}
+
+func fullSet(size int) versionSet {
+	return emptySet(size).complement()
+}
+
+func singletonSet(size int, index int) versionSet {
+	set := emptySet(size)
+	set.words[index/64] |= 1 << (index % 64)
+	return set
+}
+
+func (s versionSet) contains(index int) bool {
+	return s.words[index/64]&(1<<(index%64)) != 0
+}
+
+func (s versionSet) isEmpty() bool {
+	for _, word := range s.words {
+		if word != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s versionSet) len() int {
+	n := 0
+	for _, word := range s.words {
+		n += bits.OnesCount64(word)
+	}
+	return n
+}
+
+func (s versionSet) equal(other versionSet) bool {
+	return slices.Equal(s.words, other.words)
+}
+
+func (s versionSet) complement() versionSet {
+	result := emptySet(s.size)
+	for i, word := range s.words {
+		result.words[i] = ^word
+	}
+	// Биты за пределами известных версий всегда сброшены
+	if tail := s.size % 64; tail != 0 {
+		result.words[len(result.words)-1] &= 1<<tail - 1
+	}
+	return result
+}
+
+func (s versionSet) intersect(other versionSet) versionSet {
+	result := emptySet(s.size)
+	for i := range s.words {
+		result.words[i] = s.words[i] & other.words[i]
+	}
+	return result
+}
+
+func (s versionSet) union(other versionSet) versionSet {
+	result := emptySet(s.size)
+	for i := range s.words {
+		result.words[i] = s.words[i] | other.words[i]
+	}
+	return result
+}
+
+// first возвращает индекс наибольшей версии множества
+func (s versionSet) first() (int, bool) {
+	for i, word := range s.words {
+		if word != 0 {
+			return i*64 + bits.TrailingZeros64(word), true
+		}
+	}
+	return 0, false
+}
+
+// last возвращает индекс наименьшей версии множества
+func (s versionSet) last() (int, bool) {
+	for i := len(s.words) - 1; i >= 0; i-- {
+		if word := s.words[i]; word != 0 {
+			return i*64 + 63 - bits.LeadingZeros64(word), true
+		}
+	}
+	return 0, false
+}
+
+// term — утверждение о пакете в терминах PubGrub. Положительный терм требует выбрать
+// версию из множества, отрицательный запрещает эти версии, но допускает отсутствие пакета
+type term struct {
+	pkg      string
+	positive bool
+	set      versionSet
+}
+
+// anyTerm выполняется при любом выборе, в том числе когда пакет не выбран
+func anyTerm(pkg string, size int) term {
+	return term{pkg: pkg, set: emptySet(size)}
+}
+
+func (t term) negate() term {
+	return term{pkg: t.pkg, positive: !t.positive, set: t.set}
+}
+
+func (t term) isEmpty() bool {
+	return t.positive && t.set.isEmpty()
+}
+
+func (t term) equal(other term) bool {
+	return t.positive == other.positive && t.set.equal(other.set)
+}
+
+func (t term) intersect(other term) term {
+	switch {
+	case t.positive && other.positive:
+		return term{pkg: t.pkg, positive: true, set: t.set.intersect(other.set)}
+	case t.positive:
+		return term{pkg: t.pkg, positive: true, set: t.set.intersect(other.set.complement())}
+	case other.positive:
+		return term{pkg: t.pkg, positive: true, set: t.set.complement().intersect(other.set)}
+	default:
+		return term{pkg: t.pkg, set: t.set.union(other.set)}
+	}
+}
+
+// difference возвращает часть t, не покрытую other
+func (t term) difference(other term) term {
+	return t.intersect(other.negate())
+}
+
+type relation int
+
+const (
+	relationSatisfied relation = iota
+	relationContradicted
+	relationInconclusive
+)
+
+// relationTo сравнивает терм с пересечением всех назначений пакета
+func (t term) relationTo(assigned term) relation {
+	intersection := t.intersect(assigned)
+	if intersection.isEmpty() {
+		return relationContradicted
+	}
+	if assigned.intersect(t).equal(assigned) {
+		return relationSatisfied
+	}
+	return relationInconclusive
+}
@@ -22,6 +22,147 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Machine-readable error codes returned in ErrorResponse
+type ErrorCode int32
+
+const (
+	ErrorCode_ERROR_CODE_UNSPECIFIED              ErrorCode = 0
+	ErrorCode_ERROR_CODE_INVALID_BODY             ErrorCode = 1
+	ErrorCode_ERROR_CODE_EMPTY_BODY               ErrorCode = 2
+	ErrorCode_ERROR_CODE_INVALID_CONTENT_TYPE     ErrorCode = 3
+	ErrorCode_ERROR_CODE_MISSING_PROTOBUF_DATA    ErrorCode = 4
+	ErrorCode_ERROR_CODE_PROTOBUF_UNMARSHAL_ERROR ErrorCode = 5
+	ErrorCode_ERROR_CODE_MISSING_JSON_DATA        ErrorCode = 6
+	ErrorCode_ERROR_CODE_JSON_UNMARSHAL_ERROR     ErrorCode = 7
+	ErrorCode_ERROR_CODE_VALIDATION_ERROR         ErrorCode = 8
+	ErrorCode_ERROR_CODE_UUID_GENERATE_ERROR      ErrorCode = 9
+	ErrorCode_ERROR_CODE_KAFKA_PUBLISH_ERROR      ErrorCode = 10
+	ErrorCode_ERROR_CODE_SERIALIZATION_ERROR      ErrorCode = 11
+	ErrorCode_ERROR_CODE_ANALYSIS_NOT_FOUND       ErrorCode = 12
+	ErrorCode_ERROR_CODE_STATUS_STORE_ERROR       ErrorCode = 13
+	ErrorCode_ERROR_CODE_REQUIREMENTS_PARSE_ERROR ErrorCode = 14
+	ErrorCode_ERROR_CODE_PYPROJECT_PARSE_ERROR    ErrorCode = 15
+	ErrorCode_ERROR_CODE_LOCKFILE_PARSE_ERROR     ErrorCode = 16
+)
+
+// Enum value maps for ErrorCode.
+var (
+	ErrorCode_name = map[int32]string{
+		0:  "ERROR_CODE_UNSPECIFIED",
+		1:  "ERROR_CODE_INVALID_BODY",
+		2:  "ERROR_CODE_EMPTY_BODY",
+		3:  "ERROR_CODE_INVALID_CONTENT_TYPE",
+		4:  "ERROR_CODE_MISSING_PROTOBUF_DATA",
+		5:  "ERROR_CODE_PROTOBUF_UNMARSHAL_ERROR",
+		6:  "ERROR_CODE_MISSING_JSON_DATA",
+		7:  "ERROR_CODE_JSON_UNMARSHAL_ERROR",
+		8:  "ERROR_CODE_VALIDATION_ERROR",
+		9:  "ERROR_CODE_UUID_GENERATE_ERROR",
+		10: "ERROR_CODE_KAFKA_PUBLISH_ERROR",
+		11: "ERROR_CODE_SERIALIZATION_ERROR",
+		12: "ERROR_CODE_ANALYSIS_NOT_FOUND",
+		13: "ERROR_CODE_STATUS_STORE_ERROR",
+		14: "ERROR_CODE_REQUIREMENTS_PARSE_ERROR",
+		15: "ERROR_CODE_PYPROJECT_PARSE_ERROR",
+		16: "ERROR_CODE_LOCKFILE_PARSE_ERROR",
+	}
+	ErrorCode_value = map[string]int32{
+		"ERROR_CODE_UNSPECIFIED":              0,
+		"ERROR_CODE_INVALID_BODY":             1,
+		"ERROR_CODE_EMPTY_BODY":               2,
+		"ERROR_CODE_INVALID_CONTENT_TYPE":     3,
+		"ERROR_CODE_MISSING_PROTOBUF_DATA":    4,
+		"ERROR_CODE_PROTOBUF_UNMARSHAL_ERROR": 5,
+		"ERROR_CODE_MISSING_JSON_DATA":        6,
+		"ERROR_CODE_JSON_UNMARSHAL_ERROR":     7,
+		"ERROR_CODE_VALIDATION_ERROR":         8,
+		"ERROR_CODE_UUID_GENERATE_ERROR":      9,
+		"ERROR_CODE_KAFKA_PUBLISH_ERROR":      10,
+		"ERROR_CODE_SERIALIZATION_ERROR":      11,
+		"ERROR_CODE_ANALYSIS_NOT_FOUND":       12,
+		"ERROR_CODE_STATUS_STORE_ERROR":       13,
+		"ERROR_CODE_REQUIREMENTS_PARSE_ERROR": 14,
+		"ERROR_CODE_PYPROJECT_PARSE_ERROR":    15,
+		"ERROR_CODE_LOCKFILE_PARSE_ERROR":     16,
+	}
+)
+
+func (x ErrorCode) Enum() *ErrorCode {
+	p := new(ErrorCode)
+	*p = x
+	return p
+}
+
+func (x ErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_gateway_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorCode) Type() protoreflect.EnumType {
+	return &file_api_gateway_proto_enumTypes[0]
+}
+
+func (x ErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorCode.Descriptor instead.
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_api_gateway_proto_rawDescGZIP(), []int{0}
+}
+
+type SubscriptionRequest_Action int32
+
+const (
+	SubscriptionRequest_ACTION_UNSPECIFIED SubscriptionRequest_Action = 0
+	SubscriptionRequest_ACTION_SUBSCRIBE   SubscriptionRequest_Action = 1
+	SubscriptionRequest_ACTION_UNSUBSCRIBE SubscriptionRequest_Action = 2
+)
+
+// Enum value maps for SubscriptionRequest_Action.
+var (
+	SubscriptionRequest_Action_name = map[int32]string{
+		0: "ACTION_UNSPECIFIED",
+		1: "ACTION_SUBSCRIBE",
+		2: "ACTION_UNSUBSCRIBE",
+	}
+	SubscriptionRequest_Action_value = map[string]int32{
+		"ACTION_UNSPECIFIED": 0,
+		"ACTION_SUBSCRIBE":   1,
+		"ACTION_UNSUBSCRIBE": 2,
+	}
+)
+
+func (x SubscriptionRequest_Action) Enum() *SubscriptionRequest_Action {
+	p := new(SubscriptionRequest_Action)
+	*p = x
+	return p
+}
+
+func (x SubscriptionRequest_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubscriptionRequest_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_gateway_proto_enumTypes[1].Descriptor()
+}
+
+func (SubscriptionRequest_Action) Type() protoreflect.EnumType {
+	return &file_api_gateway_proto_enumTypes[1]
+}
+
+func (x SubscriptionRequest_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubscriptionRequest_Action.Descriptor instead.
+func (SubscriptionRequest_Action) EnumDescriptor() ([]byte, []int) {
+	return file_api_gateway_proto_rawDescGZIP(), []int{6, 0}
+}
+
 // Request crawl deps
 type AnalyzeRequest struct {
 	state         protoimpl.MessageState            `protogen:"open.v1"`
@@ -29,6 +170,24 @@ type AnalyzeRequest struct {
 	PythonVersion string                            `protobuf:"bytes,2,opt,name=python_version,json=pythonVersion,proto3" json:"python_version,omitempty"`
 	RepositoryUrl string                            `protobuf:"bytes,3,opt,name=repository_url,json=repositoryUrl,proto3" json:"repository_url,omitempty"`
 	Packages      []*AnalyzeRequest_RequiredPackage `protobuf:"bytes,4,rep,name=packages,proto3" json:"packages,omitempty"`
+	// Packages are pinned by a lock file and must not be re-resolved
+	Locked bool `protobuf:"varint,5,opt,name=locked,proto3" json:"locked,omitempty"`
+	// Optional platform to select wheels for
+	TargetPlatform *TargetPlatform `protobuf:"bytes,6,opt,name=target_platform,json=targetPlatform,proto3" json:"target_platform,omitempty"`
+	// highest (default), lowest, lowest-direct or prefer-pinned
+	ResolutionStrategy string `protobuf:"bytes,7,opt,name=resolution_strategy,json=resolutionStrategy,proto3" json:"resolution_strategy,omitempty"`
+	// Versions from a previous lock by package name; prefer-pinned keeps them
+	// unless a requirement forces a change
+	PinnedVersions map[string]string `protobuf:"bytes,8,rep,name=pinned_versions,json=pinnedVersions,proto3" json:"pinned_versions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Resolve one forked lock for several environments instead of python_version alone;
+	// cannot be combined with target_platform or locked
+	Universal *UniversalResolution `protobuf:"bytes,9,opt,name=universal,proto3" json:"universal,omitempty"`
+	// PEP 508 requirements that limit versions of packages without requiring them,
+	// like pip -c, e.g. "urllib3<2"
+	Constraints []string `protobuf:"bytes,10,rep,name=constraints,proto3" json:"constraints,omitempty"`
+	// PEP 508 requirements that replace the version specifier of every requirement
+	// on the package, including ones declared by other packages, e.g. "pydantic>=2"
+	Overrides     []string `protobuf:"bytes,11,rep,name=overrides,proto3" json:"overrides,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -91,20 +250,205 @@ func (x *AnalyzeRequest) GetPackages() []*AnalyzeRequest_RequiredPackage {
 	return nil
 }
 
+func (x *AnalyzeRequest) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+func (x *AnalyzeRequest) GetTargetPlatform() *TargetPlatform {
+	if x != nil {
+		return x.TargetPlatform
+	}
+	return nil
+}
+
+func (x *AnalyzeRequest) GetResolutionStrategy() string {
+	if x != nil {
+		return x.ResolutionStrategy
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetPinnedVersions() map[string]string {
+	if x != nil {
+		return x.PinnedVersions
+	}
+	return nil
+}
+
+func (x *AnalyzeRequest) GetUniversal() *UniversalResolution {
+	if x != nil {
+		return x.Universal
+	}
+	return nil
+}
+
+func (x *AnalyzeRequest) GetConstraints() []string {
+	if x != nil {
+		return x.Constraints
+	}
+	return nil
+}
+
+func (x *AnalyzeRequest) GetOverrides() []string {
+	if x != nil {
+		return x.Overrides
+	}
+	return nil
+}
+
+// Environments of a universal resolution: every python version on every platform
+type UniversalResolution struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Python versions such as 3.9 to 3.13; defaults to python_version
+	PythonVersions []string `protobuf:"bytes,1,rep,name=python_versions,json=pythonVersions,proto3" json:"python_versions,omitempty"`
+	// Defaults to linux x86_64, macos arm64 and windows x86_64
+	Platforms     []*TargetPlatform `protobuf:"bytes,2,rep,name=platforms,proto3" json:"platforms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UniversalResolution) Reset() {
+	*x = UniversalResolution{}
+	mi := &file_api_gateway_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UniversalResolution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UniversalResolution) ProtoMessage() {}
+
+func (x *UniversalResolution) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gateway_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UniversalResolution.ProtoReflect.Descriptor instead.
+func (*UniversalResolution) Descriptor() ([]byte, []int) {
+	return file_api_gateway_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UniversalResolution) GetPythonVersions() []string {
+	if x != nil {
+		return x.PythonVersions
+	}
+	return nil
+}
+
+func (x *UniversalResolution) GetPlatforms() []*TargetPlatform {
+	if x != nil {
+		return x.Platforms
+	}
+	return nil
+}
+
+// Target platform for wheel selection; empty fields fall back to linux x86_64 glibc 2.28
+type TargetPlatform struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// linux, macos or windows
+	Os   string `protobuf:"bytes,1,opt,name=os,proto3" json:"os,omitempty"`
+	Arch string `protobuf:"bytes,2,opt,name=arch,proto3" json:"arch,omitempty"`
+	// glibc or musl, linux only
+	Libc          string `protobuf:"bytes,3,opt,name=libc,proto3" json:"libc,omitempty"`
+	LibcVersion   string `protobuf:"bytes,4,opt,name=libc_version,json=libcVersion,proto3" json:"libc_version,omitempty"`
+	MacosVersion  string `protobuf:"bytes,5,opt,name=macos_version,json=macosVersion,proto3" json:"macos_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TargetPlatform) Reset() {
+	*x = TargetPlatform{}
+	mi := &file_api_gateway_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TargetPlatform) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetPlatform) ProtoMessage() {}
+
+func (x *TargetPlatform) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gateway_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetPlatform.ProtoReflect.Descriptor instead.
+func (*TargetPlatform) Descriptor() ([]byte, []int) {
+	return file_api_gateway_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TargetPlatform) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *TargetPlatform) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *TargetPlatform) GetLibc() string {
+	if x != nil {
+		return x.Libc
+	}
+	return ""
+}
+
+func (x *TargetPlatform) GetLibcVersion() string {
+	if x != nil {
+		return x.LibcVersion
+	}
+	return ""
+}
+
+func (x *TargetPlatform) GetMacosVersion() string {
+	if x != nil {
+		return x.MacosVersion
+	}
+	return ""
+}
+
 // Response request ID
 type AnalyzeResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RequestId string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Status    string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Message   string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Non-fatal issues found while importing the request
+	Warnings      []string `protobuf:"bytes,5,rep,name=warnings,proto3" json:"warnings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AnalyzeResponse) Reset() {
 	*x = AnalyzeResponse{}
-	mi := &file_api_gateway_proto_msgTypes[1]
+	mi := &file_api_gateway_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -116,7 +460,7 @@ func (x *AnalyzeResponse) String() string {
 func (*AnalyzeResponse) ProtoMessage() {}
 
 func (x *AnalyzeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_gateway_proto_msgTypes[1]
+	mi := &file_api_gateway_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -129,7 +473,7 @@ func (x *AnalyzeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AnalyzeResponse.ProtoReflect.Descriptor instead.
 func (*AnalyzeResponse) Descriptor() ([]byte, []int) {
-	return file_api_gateway_proto_rawDescGZIP(), []int{1}
+	return file_api_gateway_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *AnalyzeResponse) GetRequestId() string {
@@ -160,6 +504,13 @@ func (x *AnalyzeResponse) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *AnalyzeResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
 // Request status
 type StatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -170,7 +521,7 @@ type StatusRequest struct {
 
 func (x *StatusRequest) Reset() {
 	*x = StatusRequest{}
-	mi := &file_api_gateway_proto_msgTypes[2]
+	mi := &file_api_gateway_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -182,7 +533,7 @@ func (x *StatusRequest) String() string {
 func (*StatusRequest) ProtoMessage() {}
 
 func (x *StatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_gateway_proto_msgTypes[2]
+	mi := &file_api_gateway_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -195,7 +546,7 @@ func (x *StatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
 func (*StatusRequest) Descriptor() ([]byte, []int) {
-	return file_api_gateway_proto_rawDescGZIP(), []int{2}
+	return file_api_gateway_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *StatusRequest) GetRequestId() string {
@@ -207,18 +558,22 @@ func (x *StatusRequest) GetRequestId() string {
 
 // Response status
 type StatusResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	Progress      int64                  `protobuf:"varint,4,opt,name=progress,proto3" json:"progress,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	RequestId   string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Status      string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Message     string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Progress    int64                  `protobuf:"varint,4,opt,name=progress,proto3" json:"progress,omitempty"`
+	ServiceName string                 `protobuf:"bytes,5,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Step-by-step derivation of a dependency conflict when the analysis failed
+	Explanation   []string `protobuf:"bytes,7,rep,name=explanation,proto3" json:"explanation,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *StatusResponse) Reset() {
 	*x = StatusResponse{}
-	mi := &file_api_gateway_proto_msgTypes[3]
+	mi := &file_api_gateway_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -230,7 +585,7 @@ func (x *StatusResponse) String() string {
 func (*StatusResponse) ProtoMessage() {}
 
 func (x *StatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_gateway_proto_msgTypes[3]
+	mi := &file_api_gateway_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -243,7 +598,7 @@ func (x *StatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
 func (*StatusResponse) Descriptor() ([]byte, []int) {
-	return file_api_gateway_proto_rawDescGZIP(), []int{3}
+	return file_api_gateway_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *StatusResponse) GetRequestId() string {
@@ -274,18 +629,175 @@ func (x *StatusResponse) GetProgress() int64 {
 	return 0
 }
 
+func (x *StatusResponse) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *StatusResponse) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *StatusResponse) GetExplanation() []string {
+	if x != nil {
+		return x.Explanation
+	}
+	return nil
+}
+
+// WebSocket command to manage status subscriptions
+type SubscriptionRequest struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Action        SubscriptionRequest_Action `protobuf:"varint,1,opt,name=action,proto3,enum=api_gateway.SubscriptionRequest_Action" json:"action,omitempty"`
+	RequestIds    []string                   `protobuf:"bytes,2,rep,name=request_ids,json=requestIds,proto3" json:"request_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionRequest) Reset() {
+	*x = SubscriptionRequest{}
+	mi := &file_api_gateway_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionRequest) ProtoMessage() {}
+
+func (x *SubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gateway_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*SubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_gateway_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubscriptionRequest) GetAction() SubscriptionRequest_Action {
+	if x != nil {
+		return x.Action
+	}
+	return SubscriptionRequest_ACTION_UNSPECIFIED
+}
+
+func (x *SubscriptionRequest) GetRequestIds() []string {
+	if x != nil {
+		return x.RequestIds
+	}
+	return nil
+}
+
+// Error returned by every endpoint in the negotiated encoding
+type ErrorResponse struct {
+	state      protoimpl.MessageState          `protogen:"open.v1"`
+	Code       ErrorCode                       `protobuf:"varint,1,opt,name=code,proto3,enum=api_gateway.ErrorCode" json:"code,omitempty"`
+	Message    string                          `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Violations []*ErrorResponse_FieldViolation `protobuf:"bytes,3,rep,name=violations,proto3" json:"violations,omitempty"`
+	RequestId  string                          `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Whether the same request may succeed if retried later
+	Retryable     bool `protobuf:"varint,5,opt,name=retryable,proto3" json:"retryable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorResponse) Reset() {
+	*x = ErrorResponse{}
+	mi := &file_api_gateway_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorResponse) ProtoMessage() {}
+
+func (x *ErrorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gateway_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorResponse.ProtoReflect.Descriptor instead.
+func (*ErrorResponse) Descriptor() ([]byte, []int) {
+	return file_api_gateway_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ErrorResponse) GetCode() ErrorCode {
+	if x != nil {
+		return x.Code
+	}
+	return ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
+func (x *ErrorResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ErrorResponse) GetViolations() []*ErrorResponse_FieldViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+func (x *ErrorResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ErrorResponse) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
 type AnalyzeRequest_RequiredPackage struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	PackageName    string                 `protobuf:"bytes,1,opt,name=package_name,json=packageName,proto3" json:"package_name,omitempty"`
 	PackageVersion string                 `protobuf:"bytes,2,opt,name=package_version,json=packageVersion,proto3" json:"package_version,omitempty"`
 	Extras         []string               `protobuf:"bytes,3,rep,name=extras,proto3" json:"extras,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// PEP 508 environment marker
+	Marker string `protobuf:"bytes,4,opt,name=marker,proto3" json:"marker,omitempty"`
+	// Direct URL or VCS reference
+	Url           string   `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
+	Hashes        []string `protobuf:"bytes,6,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AnalyzeRequest_RequiredPackage) Reset() {
 	*x = AnalyzeRequest_RequiredPackage{}
-	mi := &file_api_gateway_proto_msgTypes[4]
+	mi := &file_api_gateway_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -297,7 +809,7 @@ func (x *AnalyzeRequest_RequiredPackage) String() string {
 func (*AnalyzeRequest_RequiredPackage) ProtoMessage() {}
 
 func (x *AnalyzeRequest_RequiredPackage) ProtoReflect() protoreflect.Message {
-	mi := &file_api_gateway_proto_msgTypes[4]
+	mi := &file_api_gateway_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,36 +846,180 @@ func (x *AnalyzeRequest_RequiredPackage) GetExtras() []string {
 	return nil
 }
 
+func (x *AnalyzeRequest_RequiredPackage) GetMarker() string {
+	if x != nil {
+		return x.Marker
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest_RequiredPackage) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest_RequiredPackage) GetHashes() []string {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+type ErrorResponse_FieldViolation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorResponse_FieldViolation) Reset() {
+	*x = ErrorResponse_FieldViolation{}
+	mi := &file_api_gateway_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorResponse_FieldViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorResponse_FieldViolation) ProtoMessage() {}
+
+func (x *ErrorResponse_FieldViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gateway_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorResponse_FieldViolation.ProtoReflect.Descriptor instead.
+func (*ErrorResponse_FieldViolation) Descriptor() ([]byte, []int) {
+	return file_api_gateway_proto_rawDescGZIP(), []int{7, 0}
+}
+
+func (x *ErrorResponse_FieldViolation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ErrorResponse_FieldViolation) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
 var File_api_gateway_proto protoreflect.FileDescriptor
 
 const file_api_gateway_proto_rawDesc = "" +
 	"\n" +
-	"\x11api_gateway.proto\x12\vapi_gateway\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb7\x02\n" +
+	"\x11api_gateway.proto\x12\vapi_gateway\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa6\x06\n" +
 	"\x0eAnalyzeRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
 	"\x0epython_version\x18\x02 \x01(\tR\rpythonVersion\x12%\n" +
 	"\x0erepository_url\x18\x03 \x01(\tR\rrepositoryUrl\x12G\n" +
-	"\bpackages\x18\x04 \x03(\v2+.api_gateway.AnalyzeRequest.RequiredPackageR\bpackages\x1au\n" +
+	"\bpackages\x18\x04 \x03(\v2+.api_gateway.AnalyzeRequest.RequiredPackageR\bpackages\x12\x16\n" +
+	"\x06locked\x18\x05 \x01(\bR\x06locked\x12D\n" +
+	"\x0ftarget_platform\x18\x06 \x01(\v2\x1b.api_gateway.TargetPlatformR\x0etargetPlatform\x12/\n" +
+	"\x13resolution_strategy\x18\a \x01(\tR\x12resolutionStrategy\x12X\n" +
+	"\x0fpinned_versions\x18\b \x03(\v2/.api_gateway.AnalyzeRequest.PinnedVersionsEntryR\x0epinnedVersions\x12>\n" +
+	"\tuniversal\x18\t \x01(\v2 .api_gateway.UniversalResolutionR\tuniversal\x12 \n" +
+	"\vconstraints\x18\n" +
+	" \x03(\tR\vconstraints\x12\x1c\n" +
+	"\toverrides\x18\v \x03(\tR\toverrides\x1a\xb7\x01\n" +
 	"\x0fRequiredPackage\x12!\n" +
 	"\fpackage_name\x18\x01 \x01(\tR\vpackageName\x12'\n" +
 	"\x0fpackage_version\x18\x02 \x01(\tR\x0epackageVersion\x12\x16\n" +
-	"\x06extras\x18\x03 \x03(\tR\x06extras\"\x9d\x01\n" +
+	"\x06extras\x18\x03 \x03(\tR\x06extras\x12\x16\n" +
+	"\x06marker\x18\x04 \x01(\tR\x06marker\x12\x10\n" +
+	"\x03url\x18\x05 \x01(\tR\x03url\x12\x16\n" +
+	"\x06hashes\x18\x06 \x03(\tR\x06hashes\x1aA\n" +
+	"\x13PinnedVersionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"y\n" +
+	"\x13UniversalResolution\x12'\n" +
+	"\x0fpython_versions\x18\x01 \x03(\tR\x0epythonVersions\x129\n" +
+	"\tplatforms\x18\x02 \x03(\v2\x1b.api_gateway.TargetPlatformR\tplatforms\"\x90\x01\n" +
+	"\x0eTargetPlatform\x12\x0e\n" +
+	"\x02os\x18\x01 \x01(\tR\x02os\x12\x12\n" +
+	"\x04arch\x18\x02 \x01(\tR\x04arch\x12\x12\n" +
+	"\x04libc\x18\x03 \x01(\tR\x04libc\x12!\n" +
+	"\flibc_version\x18\x04 \x01(\tR\vlibcVersion\x12#\n" +
+	"\rmacos_version\x18\x05 \x01(\tR\fmacosVersion\"\xb9\x01\n" +
 	"\x0fAnalyzeResponse\x12\x1d\n" +
 	"\n" +
 	"request_id\x18\x01 \x01(\tR\trequestId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
 	"\amessage\x18\x03 \x01(\tR\amessage\x129\n" +
 	"\n" +
-	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\".\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1a\n" +
+	"\bwarnings\x18\x05 \x03(\tR\bwarnings\".\n" +
 	"\rStatusRequest\x12\x1d\n" +
 	"\n" +
-	"request_id\x18\x01 \x01(\tR\trequestId\"}\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\"\xfd\x01\n" +
 	"\x0eStatusResponse\x12\x1d\n" +
 	"\n" +
 	"request_id\x18\x01 \x01(\tR\trequestId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
 	"\amessage\x18\x03 \x01(\tR\amessage\x12\x1a\n" +
-	"\bprogress\x18\x04 \x01(\x03R\bprogressB>Z<github.com/0hJonny/python-deps-crawler/pkg/proto/api_gatewayb\x06proto3"
+	"\bprogress\x18\x04 \x01(\x03R\bprogress\x12!\n" +
+	"\fservice_name\x18\x05 \x01(\tR\vserviceName\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12 \n" +
+	"\vexplanation\x18\a \x03(\tR\vexplanation\"\xc7\x01\n" +
+	"\x13SubscriptionRequest\x12?\n" +
+	"\x06action\x18\x01 \x01(\x0e2'.api_gateway.SubscriptionRequest.ActionR\x06action\x12\x1f\n" +
+	"\vrequest_ids\x18\x02 \x03(\tR\n" +
+	"requestIds\"N\n" +
+	"\x06Action\x12\x16\n" +
+	"\x12ACTION_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10ACTION_SUBSCRIBE\x10\x01\x12\x16\n" +
+	"\x12ACTION_UNSUBSCRIBE\x10\x02\"\xa7\x02\n" +
+	"\rErrorResponse\x12*\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x16.api_gateway.ErrorCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12I\n" +
+	"\n" +
+	"violations\x18\x03 \x03(\v2).api_gateway.ErrorResponse.FieldViolationR\n" +
+	"violations\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x04 \x01(\tR\trequestId\x12\x1c\n" +
+	"\tretryable\x18\x05 \x01(\bR\tretryable\x1aH\n" +
+	"\x0eFieldViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription*\xe1\x04\n" +
+	"\tErrorCode\x12\x1a\n" +
+	"\x16ERROR_CODE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17ERROR_CODE_INVALID_BODY\x10\x01\x12\x19\n" +
+	"\x15ERROR_CODE_EMPTY_BODY\x10\x02\x12#\n" +
+	"\x1fERROR_CODE_INVALID_CONTENT_TYPE\x10\x03\x12$\n" +
+	" ERROR_CODE_MISSING_PROTOBUF_DATA\x10\x04\x12'\n" +
+	"#ERROR_CODE_PROTOBUF_UNMARSHAL_ERROR\x10\x05\x12 \n" +
+	"\x1cERROR_CODE_MISSING_JSON_DATA\x10\x06\x12#\n" +
+	"\x1fERROR_CODE_JSON_UNMARSHAL_ERROR\x10\a\x12\x1f\n" +
+	"\x1bERROR_CODE_VALIDATION_ERROR\x10\b\x12\"\n" +
+	"\x1eERROR_CODE_UUID_GENERATE_ERROR\x10\t\x12\"\n" +
+	"\x1eERROR_CODE_KAFKA_PUBLISH_ERROR\x10\n" +
+	"\x12\"\n" +
+	"\x1eERROR_CODE_SERIALIZATION_ERROR\x10\v\x12!\n" +
+	"\x1dERROR_CODE_ANALYSIS_NOT_FOUND\x10\f\x12!\n" +
+	"\x1dERROR_CODE_STATUS_STORE_ERROR\x10\r\x12'\n" +
+	"#ERROR_CODE_REQUIREMENTS_PARSE_ERROR\x10\x0e\x12$\n" +
+	" ERROR_CODE_PYPROJECT_PARSE_ERROR\x10\x0f\x12#\n" +
+	"\x1fERROR_CODE_LOCKFILE_PARSE_ERROR\x10\x102\xed\x01\n" +
+	"\x0fAnalysisService\x12J\n" +
+	"\rStartAnalysis\x12\x1b.api_gateway.AnalyzeRequest\x1a\x1c.api_gateway.AnalyzeResponse\x12D\n" +
+	"\tGetStatus\x12\x1a.api_gateway.StatusRequest\x1a\x1b.api_gateway.StatusResponse\x12H\n" +
+	"\vWatchStatus\x12\x1a.api_gateway.StatusRequest\x1a\x1b.api_gateway.StatusResponse0\x01B>Z<github.com/0hJonny/python-deps-crawler/pkg/proto/api_gatewayb\x06proto3"
 
 var (
 	file_api_gateway_proto_rawDescOnce sync.Once
@@ -377,23 +1033,46 @@ func file_api_gateway_proto_rawDescGZIP() []byte {
 	return file_api_gateway_proto_rawDescData
 }
 
-var file_api_gateway_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_api_gateway_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_api_gateway_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_api_gateway_proto_goTypes = []any{
-	(*AnalyzeRequest)(nil),                 // 0: api_gateway.AnalyzeRequest
-	(*AnalyzeResponse)(nil),                // 1: api_gateway.AnalyzeResponse
-	(*StatusRequest)(nil),                  // 2: api_gateway.StatusRequest
-	(*StatusResponse)(nil),                 // 3: api_gateway.StatusResponse
-	(*AnalyzeRequest_RequiredPackage)(nil), // 4: api_gateway.AnalyzeRequest.RequiredPackage
-	(*timestamppb.Timestamp)(nil),          // 5: google.protobuf.Timestamp
+	(ErrorCode)(0),                         // 0: api_gateway.ErrorCode
+	(SubscriptionRequest_Action)(0),        // 1: api_gateway.SubscriptionRequest.Action
+	(*AnalyzeRequest)(nil),                 // 2: api_gateway.AnalyzeRequest
+	(*UniversalResolution)(nil),            // 3: api_gateway.UniversalResolution
+	(*TargetPlatform)(nil),                 // 4: api_gateway.TargetPlatform
+	(*AnalyzeResponse)(nil),                // 5: api_gateway.AnalyzeResponse
+	(*StatusRequest)(nil),                  // 6: api_gateway.StatusRequest
+	(*StatusResponse)(nil),                 // 7: api_gateway.StatusResponse
+	(*SubscriptionRequest)(nil),            // 8: api_gateway.SubscriptionRequest
+	(*ErrorResponse)(nil),                  // 9: api_gateway.ErrorResponse
+	(*AnalyzeRequest_RequiredPackage)(nil), // 10: api_gateway.AnalyzeRequest.RequiredPackage
+	nil,                                    // 11: api_gateway.AnalyzeRequest.PinnedVersionsEntry
+	(*ErrorResponse_FieldViolation)(nil),   // 12: api_gateway.ErrorResponse.FieldViolation
+	(*timestamppb.Timestamp)(nil),          // 13: google.protobuf.Timestamp
 }
 var file_api_gateway_proto_depIdxs = []int32{
-	4, // 0: api_gateway.AnalyzeRequest.packages:type_name -> api_gateway.AnalyzeRequest.RequiredPackage
-	5, // 1: api_gateway.AnalyzeResponse.created_at:type_name -> google.protobuf.Timestamp
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	10, // 0: api_gateway.AnalyzeRequest.packages:type_name -> api_gateway.AnalyzeRequest.RequiredPackage
+	4,  // 1: api_gateway.AnalyzeRequest.target_platform:type_name -> api_gateway.TargetPlatform
+	11, // 2: api_gateway.AnalyzeRequest.pinned_versions:type_name -> api_gateway.AnalyzeRequest.PinnedVersionsEntry
+	3,  // 3: api_gateway.AnalyzeRequest.universal:type_name -> api_gateway.UniversalResolution
+	4,  // 4: api_gateway.UniversalResolution.platforms:type_name -> api_gateway.TargetPlatform
+	13, // 5: api_gateway.AnalyzeResponse.created_at:type_name -> google.protobuf.Timestamp
+	13, // 6: api_gateway.StatusResponse.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 7: api_gateway.SubscriptionRequest.action:type_name -> api_gateway.SubscriptionRequest.Action
+	0,  // 8: api_gateway.ErrorResponse.code:type_name -> api_gateway.ErrorCode
+	12, // 9: api_gateway.ErrorResponse.violations:type_name -> api_gateway.ErrorResponse.FieldViolation
+	2,  // 10: api_gateway.AnalysisService.StartAnalysis:input_type -> api_gateway.AnalyzeRequest
+	6,  // 11: api_gateway.AnalysisService.GetStatus:input_type -> api_gateway.StatusRequest
+	6,  // 12: api_gateway.AnalysisService.WatchStatus:input_type -> api_gateway.StatusRequest
+	5,  // 13: api_gateway.AnalysisService.StartAnalysis:output_type -> api_gateway.AnalyzeResponse
+	7,  // 14: api_gateway.AnalysisService.GetStatus:output_type -> api_gateway.StatusResponse
+	7,  // 15: api_gateway.AnalysisService.WatchStatus:output_type -> api_gateway.StatusResponse
+	13, // [13:16] is the sub-list for method output_type
+	10, // [10:13] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_api_gateway_proto_init() }
@@ -406,13 +1085,14 @@ func file_api_gateway_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_gateway_proto_rawDesc), len(file_api_gateway_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      2,
+			NumMessages:   11,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_api_gateway_proto_goTypes,
 		DependencyIndexes: file_api_gateway_proto_depIdxs,
+		EnumInfos:         file_api_gateway_proto_enumTypes,
 		MessageInfos:      file_api_gateway_proto_msgTypes,
 	}.Build()
 	File_api_gateway_proto = out.File
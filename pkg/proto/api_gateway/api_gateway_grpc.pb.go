@@ -0,0 +1,207 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: api_gateway.proto
+
+package api_gateway
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AnalysisService_StartAnalysis_FullMethodName = "/api_gateway.AnalysisService/StartAnalysis"
+	AnalysisService_GetStatus_FullMethodName     = "/api_gateway.AnalysisService/GetStatus"
+	AnalysisService_WatchStatus_FullMethodName   = "/api_gateway.AnalysisService/WatchStatus"
+)
+
+// AnalysisServiceClient is the client API for AnalysisService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Analysis API served over gRPC alongside the HTTP gateway
+type AnalysisServiceClient interface {
+	StartAnalysis(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error)
+	GetStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Streams status updates until the analysis reaches a terminal status
+	WatchStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StatusResponse], error)
+}
+
+type analysisServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAnalysisServiceClient(cc grpc.ClientConnInterface) AnalysisServiceClient {
+	return &analysisServiceClient{cc}
+}
+
+func (c *analysisServiceClient) StartAnalysis(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnalyzeResponse)
+	err := c.cc.Invoke(ctx, AnalysisService_StartAnalysis_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analysisServiceClient) GetStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, AnalysisService_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analysisServiceClient) WatchStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StatusResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AnalysisService_ServiceDesc.Streams[0], AnalysisService_WatchStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StatusRequest, StatusResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalysisService_WatchStatusClient = grpc.ServerStreamingClient[StatusResponse]
+
+// AnalysisServiceServer is the server API for AnalysisService service.
+// All implementations must embed UnimplementedAnalysisServiceServer
+// for forward compatibility.
+//
+// Analysis API served over gRPC alongside the HTTP gateway
+type AnalysisServiceServer interface {
+	StartAnalysis(context.Context, *AnalyzeRequest) (*AnalyzeResponse, error)
+	GetStatus(context.Context, *StatusRequest) (*StatusResponse, error)
+	// Streams status updates until the analysis reaches a terminal status
+	WatchStatus(*StatusRequest, grpc.ServerStreamingServer[StatusResponse]) error
+	mustEmbedUnimplementedAnalysisServiceServer()
+}
+
+// UnimplementedAnalysisServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAnalysisServiceServer struct{}
+
+func (UnimplementedAnalysisServiceServer) StartAnalysis(context.Context, *AnalyzeRequest) (*AnalyzeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartAnalysis not implemented")
+}
+func (UnimplementedAnalysisServiceServer) GetStatus(context.Context, *StatusRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedAnalysisServiceServer) WatchStatus(*StatusRequest, grpc.ServerStreamingServer[StatusResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStatus not implemented")
+}
+func (UnimplementedAnalysisServiceServer) mustEmbedUnimplementedAnalysisServiceServer() {}
+func (UnimplementedAnalysisServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeAnalysisServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AnalysisServiceServer will
+// result in compilation errors.
+type UnsafeAnalysisServiceServer interface {
+	mustEmbedUnimplementedAnalysisServiceServer()
+}
+
+func RegisterAnalysisServiceServer(s grpc.ServiceRegistrar, srv AnalysisServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAnalysisServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AnalysisService_ServiceDesc, srv)
+}
+
+func _AnalysisService_StartAnalysis_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalysisServiceServer).StartAnalysis(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalysisService_StartAnalysis_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalysisServiceServer).StartAnalysis(ctx, req.(*AnalyzeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalysisService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalysisServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalysisService_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalysisServiceServer).GetStatus(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalysisService_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AnalysisServiceServer).WatchStatus(m, &grpc.GenericServerStream[StatusRequest, StatusResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalysisService_WatchStatusServer = grpc.ServerStreamingServer[StatusResponse]
+
+// AnalysisService_ServiceDesc is the grpc.ServiceDesc for AnalysisService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AnalysisService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api_gateway.AnalysisService",
+	HandlerType: (*AnalysisServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StartAnalysis",
+			Handler:    _AnalysisService_StartAnalysis_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _AnalysisService_GetStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchStatus",
+			Handler:       _AnalysisService_WatchStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api_gateway.proto",
+}
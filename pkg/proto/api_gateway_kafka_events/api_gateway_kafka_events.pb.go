@@ -31,6 +31,20 @@ type AnalysisStartedEvent struct {
 	RepositoryUrl string                                  `protobuf:"bytes,4,opt,name=repository_url,json=repositoryUrl,proto3" json:"repository_url,omitempty"`
 	Packages      []*AnalysisStartedEvent_RequiredPackage `protobuf:"bytes,5,rep,name=packages,proto3" json:"packages,omitempty"`
 	Timestamp     *timestamppb.Timestamp                  `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Packages are pinned by a lock file, resolution must be skipped
+	Locked bool `protobuf:"varint,7,opt,name=locked,proto3" json:"locked,omitempty"`
+	// Normalized target platform, absent when the request did not set one
+	TargetPlatform *TargetPlatform `protobuf:"bytes,8,opt,name=target_platform,json=targetPlatform,proto3" json:"target_platform,omitempty"`
+	// highest, lowest, lowest-direct or prefer-pinned, never empty
+	ResolutionStrategy string `protobuf:"bytes,9,opt,name=resolution_strategy,json=resolutionStrategy,proto3" json:"resolution_strategy,omitempty"`
+	// Previously locked versions by normalized name, kept by prefer-pinned
+	PinnedVersions map[string]string `protobuf:"bytes,10,rep,name=pinned_versions,json=pinnedVersions,proto3" json:"pinned_versions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Normalized universal resolution environments, absent for single-environment requests
+	Universal *UniversalResolution `protobuf:"bytes,11,opt,name=universal,proto3" json:"universal,omitempty"`
+	// PEP 508 constraints with normalized names
+	Constraints []string `protobuf:"bytes,12,rep,name=constraints,proto3" json:"constraints,omitempty"`
+	// PEP 508 overrides with normalized names
+	Overrides     []string `protobuf:"bytes,13,rep,name=overrides,proto3" json:"overrides,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -107,22 +121,205 @@ func (x *AnalysisStartedEvent) GetTimestamp() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *AnalysisStartedEvent) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+func (x *AnalysisStartedEvent) GetTargetPlatform() *TargetPlatform {
+	if x != nil {
+		return x.TargetPlatform
+	}
+	return nil
+}
+
+func (x *AnalysisStartedEvent) GetResolutionStrategy() string {
+	if x != nil {
+		return x.ResolutionStrategy
+	}
+	return ""
+}
+
+func (x *AnalysisStartedEvent) GetPinnedVersions() map[string]string {
+	if x != nil {
+		return x.PinnedVersions
+	}
+	return nil
+}
+
+func (x *AnalysisStartedEvent) GetUniversal() *UniversalResolution {
+	if x != nil {
+		return x.Universal
+	}
+	return nil
+}
+
+func (x *AnalysisStartedEvent) GetConstraints() []string {
+	if x != nil {
+		return x.Constraints
+	}
+	return nil
+}
+
+func (x *AnalysisStartedEvent) GetOverrides() []string {
+	if x != nil {
+		return x.Overrides
+	}
+	return nil
+}
+
+// Environments of a universal resolution: every python version on every platform
+type UniversalResolution struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Python versions with distinct major.minor, never empty
+	PythonVersions []string `protobuf:"bytes,1,rep,name=python_versions,json=pythonVersions,proto3" json:"python_versions,omitempty"`
+	// Normalized platforms, never empty
+	Platforms     []*TargetPlatform `protobuf:"bytes,2,rep,name=platforms,proto3" json:"platforms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UniversalResolution) Reset() {
+	*x = UniversalResolution{}
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UniversalResolution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UniversalResolution) ProtoMessage() {}
+
+func (x *UniversalResolution) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UniversalResolution.ProtoReflect.Descriptor instead.
+func (*UniversalResolution) Descriptor() ([]byte, []int) {
+	return file_api_gateway_kafka_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UniversalResolution) GetPythonVersions() []string {
+	if x != nil {
+		return x.PythonVersions
+	}
+	return nil
+}
+
+func (x *UniversalResolution) GetPlatforms() []*TargetPlatform {
+	if x != nil {
+		return x.Platforms
+	}
+	return nil
+}
+
+// Target platform for wheel selection
+type TargetPlatform struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Os            string                 `protobuf:"bytes,1,opt,name=os,proto3" json:"os,omitempty"`
+	Arch          string                 `protobuf:"bytes,2,opt,name=arch,proto3" json:"arch,omitempty"`
+	Libc          string                 `protobuf:"bytes,3,opt,name=libc,proto3" json:"libc,omitempty"`
+	LibcVersion   string                 `protobuf:"bytes,4,opt,name=libc_version,json=libcVersion,proto3" json:"libc_version,omitempty"`
+	MacosVersion  string                 `protobuf:"bytes,5,opt,name=macos_version,json=macosVersion,proto3" json:"macos_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TargetPlatform) Reset() {
+	*x = TargetPlatform{}
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TargetPlatform) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetPlatform) ProtoMessage() {}
+
+func (x *TargetPlatform) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetPlatform.ProtoReflect.Descriptor instead.
+func (*TargetPlatform) Descriptor() ([]byte, []int) {
+	return file_api_gateway_kafka_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TargetPlatform) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *TargetPlatform) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *TargetPlatform) GetLibc() string {
+	if x != nil {
+		return x.Libc
+	}
+	return ""
+}
+
+func (x *TargetPlatform) GetLibcVersion() string {
+	if x != nil {
+		return x.LibcVersion
+	}
+	return ""
+}
+
+func (x *TargetPlatform) GetMacosVersion() string {
+	if x != nil {
+		return x.MacosVersion
+	}
+	return ""
+}
+
 // Kafka event for status updates
 type AnalysisStatusEvent struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	Progress      int64                  `protobuf:"varint,4,opt,name=progress,proto3" json:"progress,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	ServiceName   string                 `protobuf:"bytes,6,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	RequestId   string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Status      string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Message     string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Progress    int64                  `protobuf:"varint,4,opt,name=progress,proto3" json:"progress,omitempty"`
+	Timestamp   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ServiceName string                 `protobuf:"bytes,6,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	// Step-by-step derivation of a dependency conflict, set on failed statuses
+	Explanation   []string `protobuf:"bytes,7,rep,name=explanation,proto3" json:"explanation,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AnalysisStatusEvent) Reset() {
 	*x = AnalysisStatusEvent{}
-	mi := &file_api_gateway_kafka_events_proto_msgTypes[1]
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -134,7 +331,7 @@ func (x *AnalysisStatusEvent) String() string {
 func (*AnalysisStatusEvent) ProtoMessage() {}
 
 func (x *AnalysisStatusEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_gateway_kafka_events_proto_msgTypes[1]
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -147,7 +344,7 @@ func (x *AnalysisStatusEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AnalysisStatusEvent.ProtoReflect.Descriptor instead.
 func (*AnalysisStatusEvent) Descriptor() ([]byte, []int) {
-	return file_api_gateway_kafka_events_proto_rawDescGZIP(), []int{1}
+	return file_api_gateway_kafka_events_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *AnalysisStatusEvent) GetRequestId() string {
@@ -192,18 +389,163 @@ func (x *AnalysisStatusEvent) GetServiceName() string {
 	return ""
 }
 
+func (x *AnalysisStatusEvent) GetExplanation() []string {
+	if x != nil {
+		return x.Explanation
+	}
+	return nil
+}
+
+// Kafka event with the resolved transitive dependency set
+type AnalysisResultEvent struct {
+	state         protoimpl.MessageState                 `protogen:"open.v1"`
+	RequestId     string                                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	PythonVersion string                                 `protobuf:"bytes,2,opt,name=python_version,json=pythonVersion,proto3" json:"python_version,omitempty"`
+	Packages      []*AnalysisResultEvent_ResolvedPackage `protobuf:"bytes,3,rep,name=packages,proto3" json:"packages,omitempty"`
+	// Resolved from an offline snapshot instead of live package indexes
+	Offline        bool            `protobuf:"varint,4,opt,name=offline,proto3" json:"offline,omitempty"`
+	TargetPlatform *TargetPlatform `protobuf:"bytes,5,opt,name=target_platform,json=targetPlatform,proto3" json:"target_platform,omitempty"`
+	// Non-fatal issues, e.g. direct URL packages that were not traversed
+	Warnings    []string               `protobuf:"bytes,6,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	Timestamp   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ServiceName string                 `protobuf:"bytes,8,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	// Strategy that selected the versions
+	ResolutionStrategy string `protobuf:"bytes,9,opt,name=resolution_strategy,json=resolutionStrategy,proto3" json:"resolution_strategy,omitempty"`
+	// Environments of a universal result, absent for single-environment results.
+	// The same version may be listed once per distinct file, e.g. per-platform wheels
+	Environments      []*AnalysisResultEvent_Environment      `protobuf:"bytes,10,rep,name=environments,proto3" json:"environments,omitempty"`
+	OverrideConflicts []*AnalysisResultEvent_OverrideConflict `protobuf:"bytes,11,rep,name=override_conflicts,json=overrideConflicts,proto3" json:"override_conflicts,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AnalysisResultEvent) Reset() {
+	*x = AnalysisResultEvent{}
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalysisResultEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalysisResultEvent) ProtoMessage() {}
+
+func (x *AnalysisResultEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalysisResultEvent.ProtoReflect.Descriptor instead.
+func (*AnalysisResultEvent) Descriptor() ([]byte, []int) {
+	return file_api_gateway_kafka_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AnalysisResultEvent) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent) GetPythonVersion() string {
+	if x != nil {
+		return x.PythonVersion
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent) GetPackages() []*AnalysisResultEvent_ResolvedPackage {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+func (x *AnalysisResultEvent) GetOffline() bool {
+	if x != nil {
+		return x.Offline
+	}
+	return false
+}
+
+func (x *AnalysisResultEvent) GetTargetPlatform() *TargetPlatform {
+	if x != nil {
+		return x.TargetPlatform
+	}
+	return nil
+}
+
+func (x *AnalysisResultEvent) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *AnalysisResultEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *AnalysisResultEvent) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent) GetResolutionStrategy() string {
+	if x != nil {
+		return x.ResolutionStrategy
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent) GetEnvironments() []*AnalysisResultEvent_Environment {
+	if x != nil {
+		return x.Environments
+	}
+	return nil
+}
+
+func (x *AnalysisResultEvent) GetOverrideConflicts() []*AnalysisResultEvent_OverrideConflict {
+	if x != nil {
+		return x.OverrideConflicts
+	}
+	return nil
+}
+
 type AnalysisStartedEvent_RequiredPackage struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	PackageName    string                 `protobuf:"bytes,1,opt,name=package_name,json=packageName,proto3" json:"package_name,omitempty"`
-	PackageVersion string                 `protobuf:"bytes,2,opt,name=package_version,json=packageVersion,proto3" json:"package_version,omitempty"`
-	Extras         []string               `protobuf:"bytes,3,rep,name=extras,proto3" json:"extras,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// PEP 503 normalized name
+	PackageName    string   `protobuf:"bytes,1,opt,name=package_name,json=packageName,proto3" json:"package_name,omitempty"`
+	PackageVersion string   `protobuf:"bytes,2,opt,name=package_version,json=packageVersion,proto3" json:"package_version,omitempty"`
+	Extras         []string `protobuf:"bytes,3,rep,name=extras,proto3" json:"extras,omitempty"`
+	// PEP 508 environment marker
+	Marker string `protobuf:"bytes,4,opt,name=marker,proto3" json:"marker,omitempty"`
+	// Direct URL or VCS reference
+	Url    string   `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
+	Hashes []string `protobuf:"bytes,6,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	// Name as spelled by the user
+	DisplayName   string `protobuf:"bytes,7,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AnalysisStartedEvent_RequiredPackage) Reset() {
 	*x = AnalysisStartedEvent_RequiredPackage{}
-	mi := &file_api_gateway_kafka_events_proto_msgTypes[2]
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -215,7 +557,7 @@ func (x *AnalysisStartedEvent_RequiredPackage) String() string {
 func (*AnalysisStartedEvent_RequiredPackage) ProtoMessage() {}
 
 func (x *AnalysisStartedEvent_RequiredPackage) ProtoReflect() protoreflect.Message {
-	mi := &file_api_gateway_kafka_events_proto_msgTypes[2]
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -252,11 +594,404 @@ func (x *AnalysisStartedEvent_RequiredPackage) GetExtras() []string {
 	return nil
 }
 
+func (x *AnalysisStartedEvent_RequiredPackage) GetMarker() string {
+	if x != nil {
+		return x.Marker
+	}
+	return ""
+}
+
+func (x *AnalysisStartedEvent_RequiredPackage) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *AnalysisStartedEvent_RequiredPackage) GetHashes() []string {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+func (x *AnalysisStartedEvent_RequiredPackage) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+type AnalysisResultEvent_ResolvedPackage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// PEP 503 normalized name
+	PackageName string `protobuf:"bytes,1,opt,name=package_name,json=packageName,proto3" json:"package_name,omitempty"`
+	Version     string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Extras activated by the user or by dependent packages
+	Extras []string `protobuf:"bytes,3,rep,name=extras,proto3" json:"extras,omitempty"`
+	// Normalized names of the packages it depends on
+	Dependencies []string `protobuf:"bytes,4,rep,name=dependencies,proto3" json:"dependencies,omitempty"`
+	// Requested by the user rather than pulled in transitively
+	Direct bool `protobuf:"varint,5,opt,name=direct,proto3" json:"direct,omitempty"`
+	// Index the package was resolved from
+	Index string `protobuf:"bytes,6,opt,name=index,proto3" json:"index,omitempty"`
+	// Distribution selected for the target platform
+	Filename string `protobuf:"bytes,7,opt,name=filename,proto3" json:"filename,omitempty"`
+	Url      string `protobuf:"bytes,8,opt,name=url,proto3" json:"url,omitempty"`
+	// No compatible wheel, the package has to be built from the sdist
+	SdistOnly bool `protobuf:"varint,9,opt,name=sdist_only,json=sdistOnly,proto3" json:"sdist_only,omitempty"`
+	// Universal results: PEP 508 marker of the environments the package is installed in,
+	// empty when it is installed in all of them
+	Marker string `protobuf:"bytes,10,opt,name=marker,proto3" json:"marker,omitempty"`
+	// Universal results: ids of the environments the package is installed in
+	Environments []string `protobuf:"bytes,11,rep,name=environments,proto3" json:"environments,omitempty"`
+	// Universal results: dependencies that apply only in some of the package's environments,
+	// dependencies lists the ones that apply in all of them
+	ConditionalDependencies []*AnalysisResultEvent_ConditionalDependency `protobuf:"bytes,12,rep,name=conditional_dependencies,json=conditionalDependencies,proto3" json:"conditional_dependencies,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) Reset() {
+	*x = AnalysisResultEvent_ResolvedPackage{}
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalysisResultEvent_ResolvedPackage) ProtoMessage() {}
+
+func (x *AnalysisResultEvent_ResolvedPackage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalysisResultEvent_ResolvedPackage.ProtoReflect.Descriptor instead.
+func (*AnalysisResultEvent_ResolvedPackage) Descriptor() ([]byte, []int) {
+	return file_api_gateway_kafka_events_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) GetExtras() []string {
+	if x != nil {
+		return x.Extras
+	}
+	return nil
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) GetDependencies() []string {
+	if x != nil {
+		return x.Dependencies
+	}
+	return nil
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) GetDirect() bool {
+	if x != nil {
+		return x.Direct
+	}
+	return false
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) GetIndex() string {
+	if x != nil {
+		return x.Index
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) GetSdistOnly() bool {
+	if x != nil {
+		return x.SdistOnly
+	}
+	return false
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) GetMarker() string {
+	if x != nil {
+		return x.Marker
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) GetEnvironments() []string {
+	if x != nil {
+		return x.Environments
+	}
+	return nil
+}
+
+func (x *AnalysisResultEvent_ResolvedPackage) GetConditionalDependencies() []*AnalysisResultEvent_ConditionalDependency {
+	if x != nil {
+		return x.ConditionalDependencies
+	}
+	return nil
+}
+
+type AnalysisResultEvent_ConditionalDependency struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PackageName string                 `protobuf:"bytes,1,opt,name=package_name,json=packageName,proto3" json:"package_name,omitempty"`
+	// PEP 508 marker of the environments the dependency applies in
+	Marker        string   `protobuf:"bytes,2,opt,name=marker,proto3" json:"marker,omitempty"`
+	Environments  []string `protobuf:"bytes,3,rep,name=environments,proto3" json:"environments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalysisResultEvent_ConditionalDependency) Reset() {
+	*x = AnalysisResultEvent_ConditionalDependency{}
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalysisResultEvent_ConditionalDependency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalysisResultEvent_ConditionalDependency) ProtoMessage() {}
+
+func (x *AnalysisResultEvent_ConditionalDependency) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalysisResultEvent_ConditionalDependency.ProtoReflect.Descriptor instead.
+func (*AnalysisResultEvent_ConditionalDependency) Descriptor() ([]byte, []int) {
+	return file_api_gateway_kafka_events_proto_rawDescGZIP(), []int{4, 1}
+}
+
+func (x *AnalysisResultEvent_ConditionalDependency) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_ConditionalDependency) GetMarker() string {
+	if x != nil {
+		return x.Marker
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_ConditionalDependency) GetEnvironments() []string {
+	if x != nil {
+		return x.Environments
+	}
+	return nil
+}
+
+// Environment of a universal resolution
+type AnalysisResultEvent_Environment struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Stable id such as cp312-linux-x86_64
+	Id            string          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PythonVersion string          `protobuf:"bytes,2,opt,name=python_version,json=pythonVersion,proto3" json:"python_version,omitempty"`
+	Platform      *TargetPlatform `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`
+	// PEP 508 marker matching this environment
+	Marker        string `protobuf:"bytes,4,opt,name=marker,proto3" json:"marker,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalysisResultEvent_Environment) Reset() {
+	*x = AnalysisResultEvent_Environment{}
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalysisResultEvent_Environment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalysisResultEvent_Environment) ProtoMessage() {}
+
+func (x *AnalysisResultEvent_Environment) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalysisResultEvent_Environment.ProtoReflect.Descriptor instead.
+func (*AnalysisResultEvent_Environment) Descriptor() ([]byte, []int) {
+	return file_api_gateway_kafka_events_proto_rawDescGZIP(), []int{4, 2}
+}
+
+func (x *AnalysisResultEvent_Environment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_Environment) GetPythonVersion() string {
+	if x != nil {
+		return x.PythonVersion
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_Environment) GetPlatform() *TargetPlatform {
+	if x != nil {
+		return x.Platform
+	}
+	return nil
+}
+
+func (x *AnalysisResultEvent_Environment) GetMarker() string {
+	if x != nil {
+		return x.Marker
+	}
+	return ""
+}
+
+// Declared requirement that an override replaced and the resolved version does not satisfy
+type AnalysisResultEvent_OverrideConflict struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Package that declared the requirement, empty for the request itself
+	PackageName string `protobuf:"bytes,1,opt,name=package_name,json=packageName,proto3" json:"package_name,omitempty"`
+	Version     string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// Requirement as declared, e.g. urllib3<2
+	Requirement string `protobuf:"bytes,3,opt,name=requirement,proto3" json:"requirement,omitempty"`
+	// Override that replaced it, e.g. urllib3>=2
+	Override        string `protobuf:"bytes,4,opt,name=override,proto3" json:"override,omitempty"`
+	ResolvedVersion string `protobuf:"bytes,5,opt,name=resolved_version,json=resolvedVersion,proto3" json:"resolved_version,omitempty"`
+	// Universal results: environments the conflict occurs in
+	Environments  []string `protobuf:"bytes,6,rep,name=environments,proto3" json:"environments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalysisResultEvent_OverrideConflict) Reset() {
+	*x = AnalysisResultEvent_OverrideConflict{}
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalysisResultEvent_OverrideConflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalysisResultEvent_OverrideConflict) ProtoMessage() {}
+
+func (x *AnalysisResultEvent_OverrideConflict) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gateway_kafka_events_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalysisResultEvent_OverrideConflict.ProtoReflect.Descriptor instead.
+func (*AnalysisResultEvent_OverrideConflict) Descriptor() ([]byte, []int) {
+	return file_api_gateway_kafka_events_proto_rawDescGZIP(), []int{4, 3}
+}
+
+func (x *AnalysisResultEvent_OverrideConflict) GetPackageName() string {
+	if x != nil {
+		return x.PackageName
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_OverrideConflict) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_OverrideConflict) GetRequirement() string {
+	if x != nil {
+		return x.Requirement
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_OverrideConflict) GetOverride() string {
+	if x != nil {
+		return x.Override
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_OverrideConflict) GetResolvedVersion() string {
+	if x != nil {
+		return x.ResolvedVersion
+	}
+	return ""
+}
+
+func (x *AnalysisResultEvent_OverrideConflict) GetEnvironments() []string {
+	if x != nil {
+		return x.Environments
+	}
+	return nil
+}
+
 var File_api_gateway_kafka_events_proto protoreflect.FileDescriptor
 
 const file_api_gateway_kafka_events_proto_rawDesc = "" +
 	"\n" +
-	"\x1eapi_gateway_kafka_events.proto\x12\x18api_gateway_kafka_events\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa9\x03\n" +
+	"\x1eapi_gateway_kafka_events.proto\x12\x18api_gateway_kafka_events\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe8\a\n" +
 	"\x14AnalysisStartedEvent\x12\x1d\n" +
 	"\n" +
 	"request_id\x18\x01 \x01(\tR\trequestId\x12\x17\n" +
@@ -264,11 +999,35 @@ const file_api_gateway_kafka_events_proto_rawDesc = "" +
 	"\x0epython_version\x18\x03 \x01(\tR\rpythonVersion\x12%\n" +
 	"\x0erepository_url\x18\x04 \x01(\tR\rrepositoryUrl\x12Z\n" +
 	"\bpackages\x18\x05 \x03(\v2>.api_gateway_kafka_events.AnalysisStartedEvent.RequiredPackageR\bpackages\x128\n" +
-	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x1au\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06locked\x18\a \x01(\bR\x06locked\x12Q\n" +
+	"\x0ftarget_platform\x18\b \x01(\v2(.api_gateway_kafka_events.TargetPlatformR\x0etargetPlatform\x12/\n" +
+	"\x13resolution_strategy\x18\t \x01(\tR\x12resolutionStrategy\x12k\n" +
+	"\x0fpinned_versions\x18\n" +
+	" \x03(\v2B.api_gateway_kafka_events.AnalysisStartedEvent.PinnedVersionsEntryR\x0epinnedVersions\x12K\n" +
+	"\tuniversal\x18\v \x01(\v2-.api_gateway_kafka_events.UniversalResolutionR\tuniversal\x12 \n" +
+	"\vconstraints\x18\f \x03(\tR\vconstraints\x12\x1c\n" +
+	"\toverrides\x18\r \x03(\tR\toverrides\x1a\xda\x01\n" +
 	"\x0fRequiredPackage\x12!\n" +
 	"\fpackage_name\x18\x01 \x01(\tR\vpackageName\x12'\n" +
 	"\x0fpackage_version\x18\x02 \x01(\tR\x0epackageVersion\x12\x16\n" +
-	"\x06extras\x18\x03 \x03(\tR\x06extras\"\xdf\x01\n" +
+	"\x06extras\x18\x03 \x03(\tR\x06extras\x12\x16\n" +
+	"\x06marker\x18\x04 \x01(\tR\x06marker\x12\x10\n" +
+	"\x03url\x18\x05 \x01(\tR\x03url\x12\x16\n" +
+	"\x06hashes\x18\x06 \x03(\tR\x06hashes\x12!\n" +
+	"\fdisplay_name\x18\a \x01(\tR\vdisplayName\x1aA\n" +
+	"\x13PinnedVersionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x86\x01\n" +
+	"\x13UniversalResolution\x12'\n" +
+	"\x0fpython_versions\x18\x01 \x03(\tR\x0epythonVersions\x12F\n" +
+	"\tplatforms\x18\x02 \x03(\v2(.api_gateway_kafka_events.TargetPlatformR\tplatforms\"\x90\x01\n" +
+	"\x0eTargetPlatform\x12\x0e\n" +
+	"\x02os\x18\x01 \x01(\tR\x02os\x12\x12\n" +
+	"\x04arch\x18\x02 \x01(\tR\x04arch\x12\x12\n" +
+	"\x04libc\x18\x03 \x01(\tR\x04libc\x12!\n" +
+	"\flibc_version\x18\x04 \x01(\tR\vlibcVersion\x12#\n" +
+	"\rmacos_version\x18\x05 \x01(\tR\fmacosVersion\"\x81\x02\n" +
 	"\x13AnalysisStatusEvent\x12\x1d\n" +
 	"\n" +
 	"request_id\x18\x01 \x01(\tR\trequestId\x12\x16\n" +
@@ -276,7 +1035,53 @@ const file_api_gateway_kafka_events_proto_rawDesc = "" +
 	"\amessage\x18\x03 \x01(\tR\amessage\x12\x1a\n" +
 	"\bprogress\x18\x04 \x01(\x03R\bprogress\x128\n" +
 	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12!\n" +
-	"\fservice_name\x18\x06 \x01(\tR\vserviceNameB@Z>github.com/0hJonny/python-deps-crawler/pkg/proto/kafka_messageb\x06proto3"
+	"\fservice_name\x18\x06 \x01(\tR\vserviceName\x12 \n" +
+	"\vexplanation\x18\a \x03(\tR\vexplanation\"\xdb\f\n" +
+	"\x13AnalysisResultEvent\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12%\n" +
+	"\x0epython_version\x18\x02 \x01(\tR\rpythonVersion\x12Y\n" +
+	"\bpackages\x18\x03 \x03(\v2=.api_gateway_kafka_events.AnalysisResultEvent.ResolvedPackageR\bpackages\x12\x18\n" +
+	"\aoffline\x18\x04 \x01(\bR\aoffline\x12Q\n" +
+	"\x0ftarget_platform\x18\x05 \x01(\v2(.api_gateway_kafka_events.TargetPlatformR\x0etargetPlatform\x12\x1a\n" +
+	"\bwarnings\x18\x06 \x03(\tR\bwarnings\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12!\n" +
+	"\fservice_name\x18\b \x01(\tR\vserviceName\x12/\n" +
+	"\x13resolution_strategy\x18\t \x01(\tR\x12resolutionStrategy\x12]\n" +
+	"\fenvironments\x18\n" +
+	" \x03(\v29.api_gateway_kafka_events.AnalysisResultEvent.EnvironmentR\fenvironments\x12m\n" +
+	"\x12override_conflicts\x18\v \x03(\v2>.api_gateway_kafka_events.AnalysisResultEvent.OverrideConflictR\x11overrideConflicts\x1a\xc1\x03\n" +
+	"\x0fResolvedPackage\x12!\n" +
+	"\fpackage_name\x18\x01 \x01(\tR\vpackageName\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x16\n" +
+	"\x06extras\x18\x03 \x03(\tR\x06extras\x12\"\n" +
+	"\fdependencies\x18\x04 \x03(\tR\fdependencies\x12\x16\n" +
+	"\x06direct\x18\x05 \x01(\bR\x06direct\x12\x14\n" +
+	"\x05index\x18\x06 \x01(\tR\x05index\x12\x1a\n" +
+	"\bfilename\x18\a \x01(\tR\bfilename\x12\x10\n" +
+	"\x03url\x18\b \x01(\tR\x03url\x12\x1d\n" +
+	"\n" +
+	"sdist_only\x18\t \x01(\bR\tsdistOnly\x12\x16\n" +
+	"\x06marker\x18\n" +
+	" \x01(\tR\x06marker\x12\"\n" +
+	"\fenvironments\x18\v \x03(\tR\fenvironments\x12~\n" +
+	"\x18conditional_dependencies\x18\f \x03(\v2C.api_gateway_kafka_events.AnalysisResultEvent.ConditionalDependencyR\x17conditionalDependencies\x1av\n" +
+	"\x15ConditionalDependency\x12!\n" +
+	"\fpackage_name\x18\x01 \x01(\tR\vpackageName\x12\x16\n" +
+	"\x06marker\x18\x02 \x01(\tR\x06marker\x12\"\n" +
+	"\fenvironments\x18\x03 \x03(\tR\fenvironments\x1a\xa2\x01\n" +
+	"\vEnvironment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0epython_version\x18\x02 \x01(\tR\rpythonVersion\x12D\n" +
+	"\bplatform\x18\x03 \x01(\v2(.api_gateway_kafka_events.TargetPlatformR\bplatform\x12\x16\n" +
+	"\x06marker\x18\x04 \x01(\tR\x06marker\x1a\xdc\x01\n" +
+	"\x10OverrideConflict\x12!\n" +
+	"\fpackage_name\x18\x01 \x01(\tR\vpackageName\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12 \n" +
+	"\vrequirement\x18\x03 \x01(\tR\vrequirement\x12\x1a\n" +
+	"\boverride\x18\x04 \x01(\tR\boverride\x12)\n" +
+	"\x10resolved_version\x18\x05 \x01(\tR\x0fresolvedVersion\x12\"\n" +
+	"\fenvironments\x18\x06 \x03(\tR\fenvironmentsB@Z>github.com/0hJonny/python-deps-crawler/pkg/proto/kafka_messageb\x06proto3"
 
 var (
 	file_api_gateway_kafka_events_proto_rawDescOnce sync.Once
@@ -290,22 +1095,41 @@ func file_api_gateway_kafka_events_proto_rawDescGZIP() []byte {
 	return file_api_gateway_kafka_events_proto_rawDescData
 }
 
-var file_api_gateway_kafka_events_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_gateway_kafka_events_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_api_gateway_kafka_events_proto_goTypes = []any{
 	(*AnalysisStartedEvent)(nil),                 // 0: api_gateway_kafka_events.AnalysisStartedEvent
-	(*AnalysisStatusEvent)(nil),                  // 1: api_gateway_kafka_events.AnalysisStatusEvent
-	(*AnalysisStartedEvent_RequiredPackage)(nil), // 2: api_gateway_kafka_events.AnalysisStartedEvent.RequiredPackage
-	(*timestamppb.Timestamp)(nil),                // 3: google.protobuf.Timestamp
+	(*UniversalResolution)(nil),                  // 1: api_gateway_kafka_events.UniversalResolution
+	(*TargetPlatform)(nil),                       // 2: api_gateway_kafka_events.TargetPlatform
+	(*AnalysisStatusEvent)(nil),                  // 3: api_gateway_kafka_events.AnalysisStatusEvent
+	(*AnalysisResultEvent)(nil),                  // 4: api_gateway_kafka_events.AnalysisResultEvent
+	(*AnalysisStartedEvent_RequiredPackage)(nil), // 5: api_gateway_kafka_events.AnalysisStartedEvent.RequiredPackage
+	nil, // 6: api_gateway_kafka_events.AnalysisStartedEvent.PinnedVersionsEntry
+	(*AnalysisResultEvent_ResolvedPackage)(nil),       // 7: api_gateway_kafka_events.AnalysisResultEvent.ResolvedPackage
+	(*AnalysisResultEvent_ConditionalDependency)(nil), // 8: api_gateway_kafka_events.AnalysisResultEvent.ConditionalDependency
+	(*AnalysisResultEvent_Environment)(nil),           // 9: api_gateway_kafka_events.AnalysisResultEvent.Environment
+	(*AnalysisResultEvent_OverrideConflict)(nil),      // 10: api_gateway_kafka_events.AnalysisResultEvent.OverrideConflict
+	(*timestamppb.Timestamp)(nil),                     // 11: google.protobuf.Timestamp
 }
 var file_api_gateway_kafka_events_proto_depIdxs = []int32{
-	2, // 0: api_gateway_kafka_events.AnalysisStartedEvent.packages:type_name -> api_gateway_kafka_events.AnalysisStartedEvent.RequiredPackage
-	3, // 1: api_gateway_kafka_events.AnalysisStartedEvent.timestamp:type_name -> google.protobuf.Timestamp
-	3, // 2: api_gateway_kafka_events.AnalysisStatusEvent.timestamp:type_name -> google.protobuf.Timestamp
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	5,  // 0: api_gateway_kafka_events.AnalysisStartedEvent.packages:type_name -> api_gateway_kafka_events.AnalysisStartedEvent.RequiredPackage
+	11, // 1: api_gateway_kafka_events.AnalysisStartedEvent.timestamp:type_name -> google.protobuf.Timestamp
+	2,  // 2: api_gateway_kafka_events.AnalysisStartedEvent.target_platform:type_name -> api_gateway_kafka_events.TargetPlatform
+	6,  // 3: api_gateway_kafka_events.AnalysisStartedEvent.pinned_versions:type_name -> api_gateway_kafka_events.AnalysisStartedEvent.PinnedVersionsEntry
+	1,  // 4: api_gateway_kafka_events.AnalysisStartedEvent.universal:type_name -> api_gateway_kafka_events.UniversalResolution
+	2,  // 5: api_gateway_kafka_events.UniversalResolution.platforms:type_name -> api_gateway_kafka_events.TargetPlatform
+	11, // 6: api_gateway_kafka_events.AnalysisStatusEvent.timestamp:type_name -> google.protobuf.Timestamp
+	7,  // 7: api_gateway_kafka_events.AnalysisResultEvent.packages:type_name -> api_gateway_kafka_events.AnalysisResultEvent.ResolvedPackage
+	2,  // 8: api_gateway_kafka_events.AnalysisResultEvent.target_platform:type_name -> api_gateway_kafka_events.TargetPlatform
+	11, // 9: api_gateway_kafka_events.AnalysisResultEvent.timestamp:type_name -> google.protobuf.Timestamp
+	9,  // 10: api_gateway_kafka_events.AnalysisResultEvent.environments:type_name -> api_gateway_kafka_events.AnalysisResultEvent.Environment
+	10, // 11: api_gateway_kafka_events.AnalysisResultEvent.override_conflicts:type_name -> api_gateway_kafka_events.AnalysisResultEvent.OverrideConflict
+	8,  // 12: api_gateway_kafka_events.AnalysisResultEvent.ResolvedPackage.conditional_dependencies:type_name -> api_gateway_kafka_events.AnalysisResultEvent.ConditionalDependency
+	2,  // 13: api_gateway_kafka_events.AnalysisResultEvent.Environment.platform:type_name -> api_gateway_kafka_events.TargetPlatform
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_api_gateway_kafka_events_proto_init() }
@@ -319,7 +1143,7 @@ func file_api_gateway_kafka_events_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_gateway_kafka_events_proto_rawDesc), len(file_api_gateway_kafka_events_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},